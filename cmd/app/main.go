@@ -1,10 +1,22 @@
 package main
 
 import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
 	_ "github.com/MogboPython/belvaphilips_backend/cmd/app/docs"
+	"github.com/MogboPython/belvaphilips_backend/internal/auth"
 	"github.com/MogboPython/belvaphilips_backend/internal/config"
 	"github.com/MogboPython/belvaphilips_backend/internal/database"
 	"github.com/MogboPython/belvaphilips_backend/internal/handler"
+	"github.com/MogboPython/belvaphilips_backend/internal/mailer"
+	"github.com/MogboPython/belvaphilips_backend/internal/notifier"
+	"github.com/MogboPython/belvaphilips_backend/internal/payment"
+	"github.com/MogboPython/belvaphilips_backend/internal/pdf"
 	"github.com/MogboPython/belvaphilips_backend/internal/repository"
 	"github.com/MogboPython/belvaphilips_backend/internal/router"
 	"github.com/MogboPython/belvaphilips_backend/internal/service"
@@ -18,6 +30,9 @@ import (
 	"github.com/gofiber/swagger"
 )
 
+// shutdownTimeout is how long requests in flight get to finish after SIGTERM
+const shutdownTimeout = 10 * time.Second
+
 // @title						Belva Philips Backend API
 // @version					1.0
 // @description				This is an backend API for Belva Philips website
@@ -26,6 +41,21 @@ import (
 // @in							header
 // @name						Authorization
 func main() {
+	// Cancelled on SIGTERM or Ctrl-C so the background workers finish their current batch and stop
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var workers sync.WaitGroup
+
+	startWorker := func(start func(context.Context)) {
+		workers.Add(1)
+
+		go func() {
+			defer workers.Done()
+			start(ctx)
+		}()
+	}
+
 	app := fiber.New()
 	app.Use(logger.New(logger.Config{
 		Format:     "${cyan}[${time}] ${white}${pid} ${red}${status} ${blue}[${method}] ${white}${path}\n",
@@ -49,27 +79,113 @@ func main() {
 
 	storageService := storage.NewStorageService(storageClient)
 
+	mail, err := mailer.New()
+	if err != nil {
+		log.Fatalf("Failed to configure mail transport: %v", err)
+	}
+
+	textNotifier, err := notifier.New()
+	if err != nil {
+		log.Fatalf("Failed to configure text message provider: %v", err)
+	}
+
+	paymentProvider, err := payment.New()
+	if err != nil {
+		log.Fatalf("Failed to configure payment provider: %v", err)
+	}
+
+	verifier, err := auth.New()
+	if err != nil {
+		log.Fatalf("Failed to configure identity provider: %v", err)
+	}
+	defer verifier.Close()
+
 	userRepo := repository.NewUserRepository(db)
 	orderRepo := repository.NewOrderRepository(db)
 	postRepo := repository.NewPostRepository(db, storageService)
+	outboxRepo := repository.NewOutboxRepository(db)
+	catalogRepo := repository.NewCatalogRepository(db)
+	invoiceRepo := repository.NewInvoiceRepository(db)
+	paymentRepo := repository.NewPaymentRepository(db)
+	adminRepo := repository.NewAdminRepository(db)
+	roleRepo := repository.NewRoleRepository(db)
+	sessionRepo := repository.NewSessionRepository(db)
+	exportRepo := repository.NewExportRepository(db)
+	organizationRepo := repository.NewOrganizationRepository(db)
+	preferenceRepo := repository.NewNotificationPreferenceRepository(db)
+	membershipRepo := repository.NewMembershipRepository(db)
+	analyticsRepo := repository.NewAnalyticsRepository(db)
 
-	userService := service.NewUserService(userRepo)
+	userService := service.NewUserService(userRepo, sessionRepo, preferenceRepo, verifier)
 	userHandler := handler.NewUserHandler(userService)
 
-	adminService := service.NewAdminService(userRepo)
+	adminService := service.NewAdminService(userRepo, adminRepo, roleRepo, sessionRepo)
 	adminHandler := handler.NewAdminHandler(adminService)
 
-	orderService := service.NewOrderService(orderRepo)
+	sessionService := service.NewSessionService(sessionRepo)
+	sessionHandler := handler.NewSessionHandler(sessionService)
+
+	roleService := service.NewRoleService(roleRepo, adminRepo)
+	roleHandler := handler.NewRoleHandler(roleService)
+
+	outboxService := service.NewOutboxService(outboxRepo, mail, textNotifier)
+	outboxHandler := handler.NewOutboxHandler(outboxService)
+
+	startWorker(outboxService.Start)
+
+	exportService := service.NewExportService(exportRepo, userRepo, orderRepo)
+	exportHandler := handler.NewExportHandler(exportService)
+
+	startWorker(exportService.Start)
+
+	organizationService := service.NewOrganizationService(organizationRepo, userRepo)
+	organizationHandler := handler.NewOrganizationHandler(organizationService)
+
+	membershipService := service.NewMembershipService(membershipRepo, userRepo)
+	membershipHandler := handler.NewMembershipHandler(membershipService)
+
+	startWorker(membershipService.Start)
+
+	analyticsService := service.NewAnalyticsService(analyticsRepo)
+	analyticsHandler := handler.NewAnalyticsHandler(analyticsService)
+
+	catalogService := service.NewCatalogService(catalogRepo)
+	catalogHandler := handler.NewCatalogHandler(catalogService)
+
+	orderService := service.NewOrderService(orderRepo, userRepo, catalogRepo, organizationRepo, preferenceRepo, membershipRepo)
 	orderHandler := handler.NewOrderHandler(orderService)
 
+	invoiceService := service.NewInvoiceService(invoiceRepo, orderRepo, pdf.New())
+	invoiceHandler := handler.NewInvoiceHandler(invoiceService)
+
+	paymentService := service.NewPaymentService(paymentRepo, invoiceRepo, orderRepo, preferenceRepo, paymentProvider)
+	paymentHandler := handler.NewPaymentHandler(paymentService)
+
 	postService := service.NewPostService(postRepo, storageService)
 	postHandler := handler.NewPostHandler(postService)
 
+	contactService := service.NewContactService(mail)
+	contactHandler := handler.NewContactHandler(contactService)
+
 	app.Get("/swagger/*", swagger.HandlerDefault)
 
-	router.SetupRoutes(app, userHandler, adminHandler, orderHandler, postHandler)
+	router.SetupRoutes(app, userHandler, adminHandler, orderHandler, postHandler, outboxHandler, contactHandler, catalogHandler, invoiceHandler, paymentHandler, roleHandler, sessionHandler, exportHandler, organizationHandler, membershipHandler, analyticsHandler, roleService, organizationService, verifier, sessionService, userService)
+
+	go func() {
+		<-ctx.Done()
+		log.Info("Shutting down")
+
+		if err := app.ShutdownWithTimeout(shutdownTimeout); err != nil {
+			log.Errorf("Failed to shut down the server: %v", err)
+		}
+	}()
+
+	err = app.Listen(":" + config.Config("PORT"))
+
+	stop()
+	workers.Wait()
 
-	if err := app.Listen(":" + config.Config("PORT")); err != nil {
+	if err != nil {
 		log.Fatalf("Server failed to start: %v", err)
 	}
 }
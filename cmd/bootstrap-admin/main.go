@@ -0,0 +1,74 @@
+// Command bootstrap-admin creates the first admin account of a fresh database and gives it the owner role.
+//
+//	go run ./cmd/bootstrap-admin -username owner -email owner@example.com -name "Studio Owner"
+//
+// The password is read from BOOTSTRAP_ADMIN_PASSWORD. When it is not set a temporary password is
+// generated and printed, and the admin is asked to change it at the first sign in.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/MogboPython/belvaphilips_backend/internal/config"
+	"github.com/MogboPython/belvaphilips_backend/internal/database"
+	"github.com/MogboPython/belvaphilips_backend/internal/repository"
+	"github.com/MogboPython/belvaphilips_backend/internal/service"
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/MogboPython/belvaphilips_backend/pkg/utils"
+	"github.com/MogboPython/belvaphilips_backend/pkg/validator"
+	_ "github.com/lib/pq"
+)
+
+const (
+	minPasswordLength       = 12
+	temporaryPasswordLength = 16
+)
+
+func main() {
+	var req model.AdminInviteRequest
+
+	flag.StringVar(&req.Username, "username", "", "username of the admin")
+	flag.StringVar(&req.Email, "email", "", "email address of the admin")
+	flag.StringVar(&req.Name, "name", "", "full name of the admin")
+	flag.Parse()
+
+	if err := validator.New().Validate(req); err != nil {
+		fail("invalid admin: %v", err)
+	}
+
+	password := config.Config("BOOTSTRAP_ADMIN_PASSWORD")
+	generated := password == ""
+
+	if generated {
+		var err error
+		if password, err = utils.GenerateTemporaryPassword(temporaryPasswordLength); err != nil {
+			fail("failed to generate password: %v", err)
+		}
+	} else if len(password) < minPasswordLength {
+		fail("BOOTSTRAP_ADMIN_PASSWORD must be at least %d characters", minPasswordLength)
+	}
+
+	if err := database.ConnectDB(); err != nil {
+		fail("failed to connect to the database: %v", err)
+	}
+
+	adminService := service.NewAdminService(repository.NewUserRepository(database.DB), repository.NewAdminRepository(database.DB), repository.NewRoleRepository(database.DB), repository.NewSessionRepository(database.DB))
+
+	admin, err := adminService.CreateFirstAdmin(&req, password, generated)
+	if err != nil {
+		fail("%v", err)
+	}
+
+	fmt.Printf("Created admin %s (%s)\n", admin.Username, admin.ID)
+
+	if generated {
+		fmt.Printf("Temporary password: %s\n", password)
+	}
+}
+
+func fail(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ActorFromContext returns the caller described by the JWT validated by middleware.Protected.
+// Identity provider tokens name the user in their subject and never grant staff roles
+func ActorFromContext(c *fiber.Ctx) *model.Actor {
+	actor := &model.Actor{}
+
+	token, ok := c.Locals("user").(*jwt.Token)
+	if !ok {
+		return actor
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return actor
+	}
+
+	if IsUpstream(token) {
+		actor.ID, _ = claims.GetSubject()
+		actor.Role = model.RoleAuthenticated
+
+		return actor
+	}
+
+	actor.ID, _ = claims["sessionId"].(string)
+	actor.Role, _ = claims["role"].(string)
+	actor.TokenID, _ = claims["jti"].(string)
+
+	return actor
+}
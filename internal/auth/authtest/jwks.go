@@ -0,0 +1,91 @@
+// Package authtest serves signing keys the way the identity provider does, for tests of code
+// that verifies provider tokens
+package authtest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+// JWKS serves the public half of its signing keys as a JSON Web Key Set
+type JWKS struct {
+	keys   map[string]*rsa.PrivateKey
+	server *httptest.Server
+	mu     sync.Mutex
+}
+
+// NewJWKS starts serving a key for every kid. The server is closed when the test ends
+func NewJWKS(t *testing.T, kids ...string) *JWKS {
+	t.Helper()
+
+	j := &JWKS{keys: map[string]*rsa.PrivateKey{}}
+	for _, kid := range kids {
+		j.AddKey(t, kid)
+	}
+
+	j.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		j.mu.Lock()
+		defer j.mu.Unlock()
+
+		keys := []map[string]string{}
+		for kid, key := range j.keys {
+			keys = append(keys, map[string]string{
+				"kty": "RSA",
+				"alg": "RS256",
+				"use": "sig",
+				"kid": kid,
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			})
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": keys})
+	}))
+	t.Cleanup(j.server.Close)
+
+	return j
+}
+
+// URL is where the key set is served
+func (j *JWKS) URL() string {
+	return j.server.URL
+}
+
+// AddKey starts serving a new key under kid, as the provider does when it rotates keys
+func (j *JWKS) AddKey(t *testing.T, kid string) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	j.mu.Lock()
+	j.keys[kid] = key
+	j.mu.Unlock()
+}
+
+// Sign returns a token with claims signed by the key served under kid
+func (j *JWKS) Sign(t *testing.T, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+
+	j.mu.Lock()
+	key := j.keys[kid]
+	j.mu.Unlock()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+
+	signed, err := token.SignedString(key)
+	assert.NoError(t, err)
+
+	return signed
+}
@@ -0,0 +1,111 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MogboPython/belvaphilips_backend/internal/auth/authtest"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testIssuer   = "https://project.supabase.co/auth/v1"
+	testAudience = "authenticated"
+)
+
+func userClaims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":  "user-1",
+		"iss":  testIssuer,
+		"aud":  testAudience,
+		"role": "service_role",
+		"exp":  time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	jwks := authtest.NewJWKS(t, "key-1")
+
+	v, err := NewVerifier(jwks.URL(), testIssuer, testAudience)
+	assert.NoError(t, err)
+	t.Cleanup(v.Close)
+
+	t.Run("Should return the subject of a valid token", func(t *testing.T) {
+		t.Parallel()
+
+		subject, err := v.Verify(jwks.Sign(t, "key-1", userClaims()))
+
+		assert.NoError(t, err)
+		assert.Equal(t, "user-1", subject)
+	})
+
+	t.Run("Should reject tokens for another issuer or audience", func(t *testing.T) {
+		t.Parallel()
+
+		claims := userClaims()
+		claims["iss"] = "https://attacker.example.com"
+		_, err := v.Verify(jwks.Sign(t, "key-1", claims))
+		assert.ErrorIs(t, err, ErrUntrustedToken)
+
+		claims = userClaims()
+		claims["aud"] = "anon"
+		_, err = v.Verify(jwks.Sign(t, "key-1", claims))
+		assert.ErrorIs(t, err, ErrUntrustedToken)
+	})
+
+	t.Run("Should reject expired tokens and tokens without an expiry", func(t *testing.T) {
+		t.Parallel()
+
+		claims := userClaims()
+		claims["exp"] = time.Now().Add(-time.Minute).Unix()
+		_, err := v.Verify(jwks.Sign(t, "key-1", claims))
+		assert.ErrorIs(t, err, jwt.ErrTokenExpired)
+
+		claims = userClaims()
+		delete(claims, "exp")
+		_, err = v.Verify(jwks.Sign(t, "key-1", claims))
+		assert.ErrorIs(t, err, ErrUntrustedToken)
+	})
+
+	t.Run("Should reject tokens signed with a key outside the JWKS", func(t *testing.T) {
+		t.Parallel()
+
+		other := authtest.NewJWKS(t, "key-1")
+
+		_, err := v.Verify(other.Sign(t, "key-1", userClaims()))
+
+		assert.ErrorIs(t, err, jwt.ErrTokenSignatureInvalid)
+	})
+}
+
+func TestVerifyKeyRotation(t *testing.T) {
+	t.Parallel()
+
+	jwks := authtest.NewJWKS(t, "key-1")
+
+	v, err := NewVerifier(jwks.URL(), "", "")
+	assert.NoError(t, err)
+	t.Cleanup(v.Close)
+
+	// The provider publishes a new key after the verifier cached the JWKS
+	jwks.AddKey(t, "key-2")
+
+	subject, err := v.Verify(jwks.Sign(t, "key-2", userClaims()))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", subject)
+}
+
+func TestVerifyWithoutJWKS(t *testing.T) {
+	t.Parallel()
+
+	v, err := NewVerifier("", "", "")
+	assert.NoError(t, err)
+
+	_, err = v.Verify(authtest.NewJWKS(t, "key-1").Sign(t, "key-1", userClaims()))
+
+	assert.ErrorIs(t, err, ErrVerifierNotConfigured)
+}
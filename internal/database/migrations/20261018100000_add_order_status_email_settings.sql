@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS public.order_status_email_settings (
+    status TEXT PRIMARY KEY,
+    email_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at TIMESTAMPTZ DEFAULT now()
+);
+
+INSERT INTO public.order_status_email_settings (status) VALUES
+    ('quoted'),
+    ('accepted'),
+    ('product_received'),
+    ('shooting'),
+    ('editing'),
+    ('delivered'),
+    ('mark_completed'),
+    ('cancelled')
+ON CONFLICT (status) DO NOTHING;
+
+-- +goose Down
+DROP TABLE IF EXISTS order_status_email_settings;
@@ -0,0 +1,44 @@
+-- +goose Up
+ALTER TABLE public.orders
+ADD COLUMN currency TEXT NOT NULL DEFAULT '',
+ADD COLUMN quoted_amount BIGINT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS public.quotes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id UUID NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    currency TEXT NOT NULL,
+    total BIGINT NOT NULL,
+    notes TEXT NOT NULL DEFAULT '',
+    rejection_reason TEXT NOT NULL DEFAULT '',
+    issued_by TEXT NOT NULL DEFAULT '',
+    responded_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT now(),
+    updated_at TIMESTAMPTZ DEFAULT now(),
+
+    CONSTRAINT fk_quotes_order FOREIGN KEY (order_id) REFERENCES public.orders (id) ON UPDATE NO ACTION ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_quotes_order_id ON public.quotes (order_id, created_at);
+
+CREATE TABLE IF NOT EXISTS public.quote_line_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    quote_id UUID NOT NULL,
+    kind TEXT NOT NULL,
+    description TEXT NOT NULL,
+    quantity INTEGER NOT NULL,
+    unit_price BIGINT NOT NULL,
+    amount BIGINT NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+
+    CONSTRAINT fk_quote_line_items_quote FOREIGN KEY (quote_id) REFERENCES public.quotes (id) ON UPDATE NO ACTION ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS quote_line_items;
+
+DROP TABLE IF EXISTS quotes;
+
+ALTER TABLE public.orders
+DROP COLUMN currency,
+DROP COLUMN quoted_amount;
@@ -0,0 +1,38 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS public.catalog_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    category TEXT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    base_price BIGINT NOT NULL DEFAULT 0,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    sort_order INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ DEFAULT now(),
+    updated_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_catalog_items_category_name ON public.catalog_items (category, lower(name));
+
+-- Seed the catalog with the options already used by existing orders so they keep validating.
+-- Prices start at zero and are set by an admin
+INSERT INTO public.catalog_items (category, name)
+SELECT DISTINCT ON (lower(shoot_type)) 'shoot_type', shoot_type FROM public.orders WHERE shoot_type <> ''
+ON CONFLICT DO NOTHING;
+
+INSERT INTO public.catalog_items (category, name)
+SELECT DISTINCT ON (lower(finish_type)) 'finish_type', finish_type FROM public.orders WHERE finish_type IS NOT NULL AND finish_type <> ''
+ON CONFLICT DO NOTHING;
+
+INSERT INTO public.catalog_items (category, name)
+SELECT DISTINCT ON (lower(shot)) 'shot', shot FROM public.orders, unnest(shots) AS shot WHERE shot <> ''
+ON CONFLICT DO NOTHING;
+
+INSERT INTO public.catalog_items (category, name) VALUES ('delivery_speed', 'Standard')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO public.catalog_items (category, name)
+SELECT DISTINCT ON (lower(delivery_speed)) 'delivery_speed', delivery_speed FROM public.orders WHERE delivery_speed IS NOT NULL AND delivery_speed <> ''
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DROP TABLE IF EXISTS catalog_items;
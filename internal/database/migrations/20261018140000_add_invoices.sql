@@ -0,0 +1,48 @@
+-- +goose Up
+CREATE SEQUENCE IF NOT EXISTS public.invoice_number_seq;
+
+CREATE TABLE IF NOT EXISTS public.invoices (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id UUID NOT NULL,
+    quote_id UUID NOT NULL,
+    invoice_number TEXT NOT NULL UNIQUE,
+    status TEXT NOT NULL DEFAULT 'draft',
+    currency TEXT NOT NULL,
+    notes TEXT NOT NULL DEFAULT '',
+    subtotal BIGINT NOT NULL,
+    discount_amount BIGINT NOT NULL DEFAULT 0,
+    tax_rate_basis_points INTEGER NOT NULL DEFAULT 0,
+    tax_amount BIGINT NOT NULL DEFAULT 0,
+    total BIGINT NOT NULL,
+    issued_at TIMESTAMPTZ,
+    due_at TIMESTAMPTZ,
+    paid_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT now(),
+    updated_at TIMESTAMPTZ DEFAULT now(),
+
+    CONSTRAINT fk_invoices_order FOREIGN KEY (order_id) REFERENCES public.orders (id) ON UPDATE NO ACTION ON DELETE NO ACTION,
+    CONSTRAINT fk_invoices_quote FOREIGN KEY (quote_id) REFERENCES public.quotes (id) ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+
+-- Only one invoice per order may be open at a time
+CREATE UNIQUE INDEX IF NOT EXISTS idx_invoices_open_order ON public.invoices (order_id) WHERE status <> 'void';
+
+CREATE TABLE IF NOT EXISTS public.invoice_line_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    invoice_id UUID NOT NULL,
+    kind TEXT NOT NULL,
+    description TEXT NOT NULL,
+    quantity INTEGER NOT NULL,
+    unit_price BIGINT NOT NULL,
+    amount BIGINT NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+
+    CONSTRAINT fk_invoice_line_items_invoice FOREIGN KEY (invoice_id) REFERENCES public.invoices (id) ON UPDATE NO ACTION ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS invoice_line_items;
+
+DROP TABLE IF EXISTS invoices;
+
+DROP SEQUENCE IF EXISTS invoice_number_seq;
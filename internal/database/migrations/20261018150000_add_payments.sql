@@ -0,0 +1,30 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS public.payments (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    invoice_id UUID NOT NULL,
+    order_id UUID NOT NULL,
+    provider TEXT NOT NULL,
+    reference TEXT NOT NULL UNIQUE,
+    checkout_url TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'pending',
+    currency TEXT NOT NULL,
+    failure_reason TEXT NOT NULL DEFAULT '',
+    amount BIGINT NOT NULL,
+    refunded_amount BIGINT NOT NULL DEFAULT 0,
+    paid_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT now(),
+    updated_at TIMESTAMPTZ DEFAULT now(),
+
+    CONSTRAINT fk_payments_invoice FOREIGN KEY (invoice_id) REFERENCES public.invoices (id) ON UPDATE NO ACTION ON DELETE NO ACTION,
+    CONSTRAINT fk_payments_order FOREIGN KEY (order_id) REFERENCES public.orders (id) ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+
+CREATE INDEX IF NOT EXISTS idx_payments_invoice_id ON public.payments (invoice_id);
+
+INSERT INTO public.order_status_email_settings (status) VALUES ('paid')
+ON CONFLICT (status) DO NOTHING;
+
+-- +goose Down
+DELETE FROM public.order_status_email_settings WHERE status = 'paid';
+
+DROP TABLE IF EXISTS payments;
@@ -0,0 +1,98 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS public.permissions (
+    key TEXT PRIMARY KEY,
+    description TEXT NOT NULL
+);
+
+INSERT INTO public.permissions (key, description) VALUES
+    ('users:read', 'List customers'),
+    ('users:update_membership', 'Change the membership of a customer'),
+    ('orders:read', 'List all orders'),
+    ('orders:update_status', 'Move orders through their lifecycle'),
+    ('quotes:manage', 'Preview and issue quotes'),
+    ('invoices:manage', 'Create, list, issue and void invoices'),
+    ('payments:refund', 'Refund payments'),
+    ('catalog:manage', 'Manage the product catalog'),
+    ('emails:manage', 'Manage order status emails and the email outbox'),
+    ('posts:write', 'Create and edit blog posts'),
+    ('posts:publish', 'Publish blog posts'),
+    ('posts:delete', 'Delete blog posts'),
+    ('gallery:write', 'Create and edit galleries'),
+    ('gallery:delete', 'Delete galleries and gallery images'),
+    ('staff:manage', 'Manage staff accounts and their roles')
+ON CONFLICT (key) DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS public.roles (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name TEXT NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ DEFAULT now(),
+    updated_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS public.role_permissions (
+    role_id UUID NOT NULL,
+    permission TEXT NOT NULL,
+
+    PRIMARY KEY (role_id, permission),
+    CONSTRAINT fk_role_permissions_role FOREIGN KEY (role_id) REFERENCES public.roles (id) ON UPDATE NO ACTION ON DELETE CASCADE,
+    CONSTRAINT fk_role_permissions_permission FOREIGN KEY (permission) REFERENCES public.permissions (key) ON UPDATE NO ACTION ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS public.admin_roles (
+    admin_id UUID NOT NULL,
+    role_id UUID NOT NULL,
+
+    PRIMARY KEY (admin_id, role_id),
+    CONSTRAINT fk_admin_roles_admin FOREIGN KEY (admin_id) REFERENCES public.admins (id) ON UPDATE NO ACTION ON DELETE CASCADE,
+    CONSTRAINT fk_admin_roles_role FOREIGN KEY (role_id) REFERENCES public.roles (id) ON UPDATE NO ACTION ON DELETE CASCADE
+);
+
+INSERT INTO public.roles (name, description) VALUES
+    ('owner', 'Full access to the admin dashboard'),
+    ('account_manager', 'Customers, orders, quotes, invoices and payments'),
+    ('photographer', 'Shoots and galleries'),
+    ('editor', 'Editing, blog posts and galleries')
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO public.role_permissions (role_id, permission)
+SELECT r.id, p.key FROM public.roles r CROSS JOIN public.permissions p WHERE r.name = 'owner'
+ON CONFLICT DO NOTHING;
+
+INSERT INTO public.role_permissions (role_id, permission)
+SELECT r.id, p.permission FROM public.roles r
+JOIN (VALUES
+    ('account_manager', 'users:read'),
+    ('account_manager', 'users:update_membership'),
+    ('account_manager', 'orders:read'),
+    ('account_manager', 'orders:update_status'),
+    ('account_manager', 'quotes:manage'),
+    ('account_manager', 'invoices:manage'),
+    ('account_manager', 'payments:refund'),
+    ('account_manager', 'catalog:manage'),
+    ('account_manager', 'emails:manage'),
+    ('photographer', 'orders:read'),
+    ('photographer', 'orders:update_status'),
+    ('photographer', 'gallery:write'),
+    ('editor', 'orders:read'),
+    ('editor', 'orders:update_status'),
+    ('editor', 'posts:write'),
+    ('editor', 'posts:publish'),
+    ('editor', 'gallery:write'),
+    ('editor', 'gallery:delete')
+) AS p (role, permission) ON p.role = r.name
+ON CONFLICT DO NOTHING;
+
+-- Admins created before roles existed keep full access
+INSERT INTO public.admin_roles (admin_id, role_id)
+SELECT a.id, r.id FROM public.admins a CROSS JOIN public.roles r WHERE r.name = 'owner'
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DROP TABLE IF EXISTS admin_roles;
+
+DROP TABLE IF EXISTS role_permissions;
+
+DROP TABLE IF EXISTS roles;
+
+DROP TABLE IF EXISTS permissions;
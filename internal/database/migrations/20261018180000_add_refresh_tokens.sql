@@ -0,0 +1,39 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS public.refresh_tokens (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    family_id UUID NOT NULL,
+    subject_id TEXT NOT NULL,
+    role TEXT NOT NULL,
+    token_hash TEXT NOT NULL UNIQUE,
+    access_token_id TEXT NOT NULL,
+    access_expires_at TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON public.refresh_tokens (family_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_subject_id ON public.refresh_tokens (subject_id);
+
+CREATE TABLE IF NOT EXISTS public.revoked_tokens (
+    jti TEXT PRIMARY KEY,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_revoked_tokens_expires_at ON public.revoked_tokens (expires_at);
+
+INSERT INTO public.permissions (key, description) VALUES
+    ('sessions:revoke', 'Sign customers out of every device')
+ON CONFLICT (key) DO NOTHING;
+
+INSERT INTO public.role_permissions (role_id, permission)
+SELECT r.id, 'sessions:revoke' FROM public.roles r WHERE r.name IN ('owner', 'account_manager')
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DELETE FROM public.permissions WHERE key = 'sessions:revoke';
+
+DROP TABLE IF EXISTS revoked_tokens;
+
+DROP TABLE IF EXISTS refresh_tokens;
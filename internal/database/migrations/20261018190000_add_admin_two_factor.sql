@@ -0,0 +1,29 @@
+-- +goose Up
+ALTER TABLE public.admins
+    ADD COLUMN IF NOT EXISTS totp_secret TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS totp_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS totp_last_step BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS totp_failed_attempts INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS totp_locked_until TIMESTAMPTZ;
+
+CREATE TABLE IF NOT EXISTS public.admin_recovery_codes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    admin_id UUID NOT NULL,
+    code_hash TEXT NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT now(),
+
+    CONSTRAINT fk_admin_recovery_codes_admin FOREIGN KEY (admin_id) REFERENCES public.admins (id) ON UPDATE NO ACTION ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_admin_recovery_codes_hash ON public.admin_recovery_codes (admin_id, code_hash);
+
+-- +goose Down
+DROP TABLE IF EXISTS admin_recovery_codes;
+
+ALTER TABLE public.admins
+    DROP COLUMN IF EXISTS totp_locked_until,
+    DROP COLUMN IF EXISTS totp_failed_attempts,
+    DROP COLUMN IF EXISTS totp_last_step,
+    DROP COLUMN IF EXISTS totp_enabled,
+    DROP COLUMN IF EXISTS totp_secret;
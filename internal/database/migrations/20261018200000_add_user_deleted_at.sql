@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE public.users
+ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+-- +goose Down
+ALTER TABLE public.users
+DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,52 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS public.organizations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name TEXT NOT NULL,
+    created_by UUID NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT now(),
+    updated_at TIMESTAMPTZ DEFAULT now(),
+
+    CONSTRAINT fk_organizations_created_by FOREIGN KEY (created_by) REFERENCES public.users (id) ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+
+CREATE TABLE IF NOT EXISTS public.organization_members (
+    organization_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    role TEXT NOT NULL CHECK (role IN ('owner', 'member')),
+    created_at TIMESTAMPTZ DEFAULT now(),
+
+    PRIMARY KEY (organization_id, user_id),
+    CONSTRAINT fk_organization_members_organization FOREIGN KEY (organization_id) REFERENCES public.organizations (id) ON UPDATE NO ACTION ON DELETE CASCADE,
+    CONSTRAINT fk_organization_members_user FOREIGN KEY (user_id) REFERENCES public.users (id) ON UPDATE NO ACTION ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_organization_members_user_id ON public.organization_members (user_id);
+
+CREATE TABLE IF NOT EXISTS public.organization_invitations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL,
+    email TEXT NOT NULL,
+    role TEXT NOT NULL CHECK (role IN ('owner', 'member')),
+    token_hash TEXT NOT NULL UNIQUE,
+    invited_by UUID NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    accepted_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ DEFAULT now(),
+
+    CONSTRAINT fk_organization_invitations_organization FOREIGN KEY (organization_id) REFERENCES public.organizations (id) ON UPDATE NO ACTION ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_organization_invitations_organization_id ON public.organization_invitations (organization_id);
+
+ALTER TABLE public.orders
+ADD COLUMN IF NOT EXISTS organization_id UUID REFERENCES public.organizations (id) ON UPDATE NO ACTION ON DELETE NO ACTION;
+
+CREATE INDEX IF NOT EXISTS idx_orders_organization_id ON public.orders (organization_id);
+
+-- +goose Down
+ALTER TABLE public.orders
+DROP COLUMN IF EXISTS organization_id;
+
+DROP TABLE IF EXISTS organization_invitations;
+DROP TABLE IF EXISTS organization_members;
+DROP TABLE IF EXISTS organizations;
@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS public.notification_preferences (
+    user_id UUID PRIMARY KEY,
+    email_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    sms_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    whatsapp_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    order_updates BOOLEAN NOT NULL DEFAULT TRUE,
+    marketing BOOLEAN NOT NULL DEFAULT FALSE,
+    blog BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMPTZ DEFAULT now(),
+
+    CONSTRAINT fk_notification_preferences_user FOREIGN KEY (user_id) REFERENCES public.users (id) ON UPDATE NO ACTION ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE IF EXISTS notification_preferences;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE public.email_outbox
+ADD COLUMN channel TEXT NOT NULL DEFAULT 'email';
+
+-- +goose Down
+ALTER TABLE public.email_outbox
+DROP COLUMN channel;
@@ -0,0 +1,59 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS public.membership_plans (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name TEXT NOT NULL UNIQUE,
+    description TEXT,
+    currency TEXT NOT NULL,
+    monthly_price BIGINT NOT NULL DEFAULT 0 CHECK (monthly_price >= 0),
+    included_shots INTEGER NOT NULL DEFAULT 0 CHECK (included_shots >= 0),
+    discount_percent INTEGER NOT NULL DEFAULT 0 CHECK (discount_percent BETWEEN 0 AND 100),
+    active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMPTZ DEFAULT now(),
+    updated_at TIMESTAMPTZ DEFAULT now()
+);
+
+CREATE TABLE IF NOT EXISTS public.user_subscriptions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL,
+    plan_id UUID NOT NULL,
+    status TEXT NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'cancelled', 'expired')),
+    started_at TIMESTAMPTZ NOT NULL,
+    current_period_start TIMESTAMPTZ NOT NULL,
+    renews_at TIMESTAMPTZ NOT NULL,
+    ended_at TIMESTAMPTZ,
+    cancel_at_period_end BOOLEAN NOT NULL DEFAULT false,
+    shots_used INTEGER NOT NULL DEFAULT 0 CHECK (shots_used >= 0),
+    created_at TIMESTAMPTZ DEFAULT now(),
+    updated_at TIMESTAMPTZ DEFAULT now(),
+
+    CONSTRAINT fk_user_subscriptions_user FOREIGN KEY (user_id) REFERENCES public.users (id) ON UPDATE NO ACTION ON DELETE CASCADE,
+    CONSTRAINT fk_user_subscriptions_plan FOREIGN KEY (plan_id) REFERENCES public.membership_plans (id) ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+
+-- A user has at most one active subscription
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_subscriptions_active_user_id ON public.user_subscriptions (user_id) WHERE status = 'active';
+CREATE INDEX IF NOT EXISTS idx_user_subscriptions_renews_at ON public.user_subscriptions (renews_at) WHERE status = 'active';
+
+ALTER TABLE public.orders
+ADD COLUMN IF NOT EXISTS subscription_id UUID REFERENCES public.user_subscriptions (id) ON UPDATE NO ACTION ON DELETE SET NULL,
+ADD COLUMN IF NOT EXISTS plan_shots INTEGER NOT NULL DEFAULT 0,
+ADD COLUMN IF NOT EXISTS discount_percent INTEGER NOT NULL DEFAULT 0;
+
+INSERT INTO public.permissions (key, description) VALUES
+    ('plans:manage', 'Create and edit membership plans')
+ON CONFLICT (key) DO NOTHING;
+
+INSERT INTO public.role_permissions (role_id, permission)
+SELECT r.id, 'plans:manage' FROM public.roles r WHERE r.name IN ('owner', 'account_manager')
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DELETE FROM public.permissions WHERE key = 'plans:manage';
+
+ALTER TABLE public.orders
+DROP COLUMN IF EXISTS discount_percent,
+DROP COLUMN IF EXISTS plan_shots,
+DROP COLUMN IF EXISTS subscription_id;
+
+DROP TABLE IF EXISTS user_subscriptions;
+DROP TABLE IF EXISTS membership_plans;
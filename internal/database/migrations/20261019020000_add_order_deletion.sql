@@ -0,0 +1,23 @@
+-- +goose Up
+ALTER TABLE public.orders
+ADD COLUMN IF NOT EXISTS cancellation_reason TEXT,
+ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_orders_deleted_at ON public.orders (deleted_at);
+
+INSERT INTO public.permissions (key, description) VALUES
+    ('orders:delete', 'Delete and restore orders')
+ON CONFLICT (key) DO NOTHING;
+
+INSERT INTO public.role_permissions (role_id, permission)
+SELECT r.id, 'orders:delete' FROM public.roles r WHERE r.name = 'owner'
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DELETE FROM public.permissions WHERE key = 'orders:delete';
+
+DROP INDEX IF EXISTS idx_orders_deleted_at;
+
+ALTER TABLE public.orders
+DROP COLUMN IF EXISTS deleted_at,
+DROP COLUMN IF EXISTS cancellation_reason;
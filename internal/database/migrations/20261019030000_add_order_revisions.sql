@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE IF NOT EXISTS public.order_revisions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id UUID NOT NULL,
+    changed_by TEXT NOT NULL,
+    changed_by_role TEXT,
+    changes JSONB NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT now(),
+
+    CONSTRAINT fk_order_revisions_order FOREIGN KEY (order_id) REFERENCES public.orders (id) ON UPDATE NO ACTION ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_revisions_order_id ON public.order_revisions (order_id);
+
+-- +goose Down
+DROP TABLE IF EXISTS order_revisions;
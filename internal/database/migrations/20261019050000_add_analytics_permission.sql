@@ -0,0 +1,11 @@
+-- +goose Up
+INSERT INTO public.permissions (key, description) VALUES
+    ('analytics:read', 'View the dashboard analytics')
+ON CONFLICT (key) DO NOTHING;
+
+INSERT INTO public.role_permissions (role_id, permission)
+SELECT r.id, 'analytics:read' FROM public.roles r WHERE r.name IN ('owner', 'account_manager')
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DELETE FROM public.permissions WHERE key = 'analytics:read';
@@ -0,0 +1,14 @@
+-- +goose Up
+INSERT INTO public.permissions (key, description) VALUES
+    ('users:update', 'Update, anonymize and set the notification preferences of customer accounts'),
+    ('organizations:manage', 'View and manage organizations, their members and invitations')
+ON CONFLICT (key) DO NOTHING;
+
+INSERT INTO public.role_permissions (role_id, permission)
+SELECT r.id, p.key FROM public.roles r
+CROSS JOIN (VALUES ('users:update'), ('organizations:manage')) AS p(key)
+WHERE r.name IN ('owner', 'account_manager')
+ON CONFLICT DO NOTHING;
+
+-- +goose Down
+DELETE FROM public.permissions WHERE key IN ('users:update', 'organizations:manage');
@@ -0,0 +1,11 @@
+-- +goose Up
+ALTER TABLE public.email_outbox
+ADD COLUMN user_id UUID;
+
+CREATE INDEX IF NOT EXISTS idx_email_outbox_user_id ON public.email_outbox (user_id);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_email_outbox_user_id;
+
+ALTER TABLE public.email_outbox
+DROP COLUMN user_id;
@@ -0,0 +1,16 @@
+-- +goose Up
+ALTER TABLE public.email_outbox
+ADD COLUMN sensitive BOOLEAN NOT NULL DEFAULT false;
+
+-- Temporary staff passwords queued before the column existed
+UPDATE public.email_outbox
+SET sensitive = true
+WHERE subject IN ('Your Staff Account - BelvaPhilips Imagery', 'Your Staff Password Was Reset - BelvaPhilips Imagery');
+
+UPDATE public.email_outbox
+SET body = '[removed after delivery]'
+WHERE sensitive AND status <> 'pending';
+
+-- +goose Down
+ALTER TABLE public.email_outbox
+DROP COLUMN sensitive;
@@ -0,0 +1,12 @@
+-- +goose Up
+-- Organization invitations carry a token that accepts the invitation
+UPDATE public.email_outbox
+SET sensitive = true
+WHERE subject LIKE 'You have been invited to % - BelvaPhilips Imagery';
+
+UPDATE public.email_outbox
+SET body = '[removed after delivery]'
+WHERE sensitive AND status <> 'pending';
+
+-- +goose Down
+-- Removed message bodies cannot be restored
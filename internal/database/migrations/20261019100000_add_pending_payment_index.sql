@@ -0,0 +1,16 @@
+-- +goose Up
+-- Keep only the newest pending payment of each invoice
+UPDATE public.payments p
+SET status = 'failed', failure_reason = 'superseded by a newer checkout', updated_at = now()
+WHERE p.status = 'pending'
+AND EXISTS (
+    SELECT 1 FROM public.payments newer
+    WHERE newer.invoice_id = p.invoice_id
+    AND newer.status = 'pending'
+    AND (newer.created_at, newer.id) > (p.created_at, p.id)
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_payments_pending_invoice ON public.payments (invoice_id) WHERE status = 'pending';
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_payments_pending_invoice;
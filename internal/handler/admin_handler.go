@@ -1,12 +1,15 @@
 package handler
 
 import (
+	"errors"
 	"strings"
 
+	"github.com/MogboPython/belvaphilips_backend/internal/auth"
 	"github.com/MogboPython/belvaphilips_backend/internal/service"
 	"github.com/MogboPython/belvaphilips_backend/pkg/model"
 	"github.com/MogboPython/belvaphilips_backend/pkg/validator"
 	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
 )
 
 type AdminHandler struct {
@@ -29,8 +32,10 @@ func NewAdminHandler(adminService service.AdminService) *AdminHandler {
 //	@Accept			json
 //	@Produce		json
 //	@Param			user	body		model.AdminLoginRequest	true	"Login information"
-//	@Success		201		{object}	model.ResponseHTTP{data=map[string]string}
+//	@Success		200		{object}	model.ResponseHTTP{data=model.AdminLoginResponse}
+//	@Success		201		{object}	model.ResponseHTTP{data=model.AdminLoginResponse}
 //	@Failure		400		{object}	model.ResponseHTTP{}
+//	@Failure		403		{object}	model.ResponseHTTP{}
 //	@Failure		500		{object}	model.ResponseHTTP{}
 //	@Router			/api/v1/admin/login [post]
 func (h *AdminHandler) AdminLogin(c *fiber.Ctx) error {
@@ -52,29 +57,23 @@ func (h *AdminHandler) AdminLogin(c *fiber.Ctx) error {
 		})
 	}
 
-	token, err := h.adminService.Login(&payload)
+	login, err := h.adminService.Login(&payload)
 	if err != nil {
-		if strings.Contains(err.Error(), "incorrect username or password") {
-			return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
-				Success: false,
-				Message: "Incorrect username or password",
-				Data:    nil,
-			})
-		}
+		return h.adminError(c, err)
+	}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseHTTP{
-			Success: false,
-			Message: "Internal server error",
-			Data:    nil,
+	if login.TwoFactorRequired {
+		return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+			Success: true,
+			Message: "Two-factor code required, send it with the challenge token to /admin/login/2fa",
+			Data:    *login,
 		})
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(model.ResponseHTTP{
 		Success: true,
 		Message: "Success get access token",
-		Data: map[string]string{
-			"access_token": token,
-		},
+		Data:    *login,
 	})
 }
 
@@ -112,3 +111,275 @@ func (h *AdminHandler) GetAllUsers(c *fiber.Ctx) error {
 		Data:    users,
 	})
 }
+
+// GetAdmins is a function to list staff accounts
+//
+//	@Summary		Get staff accounts (strictly for admin)
+//	@Description	List every admin account, including disabled ones
+//	@Tags			admin
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	model.ResponseHTTP{data=[]model.AdminResponse}
+//	@Failure		500	{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/admin/staff [get]
+func (h *AdminHandler) GetAdmins(c *fiber.Ctx) error {
+	admins, err := h.adminService.GetAdmins()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Internal server error",
+			Data:    nil,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully retrieved staff accounts",
+		Data:    admins,
+	})
+}
+
+// InviteAdmin is a function to create a staff account
+//
+//	@Summary		Invite a staff member (strictly for admin)
+//	@Description	Create an admin account with the given roles and a temporary password that is emailed to the new staff member
+//	@Tags			admin
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		model.AdminInviteRequest	true	"Staff member"
+//	@Success		201		{object}	model.ResponseHTTP{data=model.AdminResponse}
+//	@Failure		400		{object}	model.ResponseHTTP{}
+//	@Failure		500		{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/admin/staff [post]
+func (h *AdminHandler) InviteAdmin(c *fiber.Ctx) error {
+	var payload model.AdminInviteRequest
+
+	if err := c.BodyParser(&payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Invalid request",
+			Data:    nil,
+		})
+	}
+
+	if err := h.validator.Validate(payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	admin, err := h.adminService.InviteAdmin(&payload)
+	if err != nil {
+		return h.adminError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully invited staff member",
+		Data:    *admin,
+	})
+}
+
+// DisableAdmin is a function to stop a staff account from signing in
+//
+//	@Summary		Disable a staff account (strictly for admin)
+//	@Description	Disable an admin account so it can no longer sign in
+//	@Tags			admin
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"Admin ID"
+//	@Success		200	{object}	model.ResponseHTTP{data=model.AdminResponse}
+//	@Failure		400	{object}	model.ResponseHTTP{}
+//	@Failure		404	{object}	model.ResponseHTTP{}
+//	@Failure		500	{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/admin/staff/{id}/disable [post]
+func (h *AdminHandler) DisableAdmin(c *fiber.Ctx) error {
+	return h.setAdminActive(c, false)
+}
+
+// EnableAdmin is a function to let a disabled staff account sign in again
+//
+//	@Summary		Enable a staff account (strictly for admin)
+//	@Description	Re-enable a disabled admin account
+//	@Tags			admin
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"Admin ID"
+//	@Success		200	{object}	model.ResponseHTTP{data=model.AdminResponse}
+//	@Failure		404	{object}	model.ResponseHTTP{}
+//	@Failure		500	{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/admin/staff/{id}/enable [post]
+func (h *AdminHandler) EnableAdmin(c *fiber.Ctx) error {
+	return h.setAdminActive(c, true)
+}
+
+func (h *AdminHandler) setAdminActive(c *fiber.Ctx, active bool) error {
+	id := c.Params("id")
+
+	admin, err := h.adminService.SetAdminActive(id, auth.ActorFromContext(c), active)
+	if err != nil {
+		return h.adminError(c, err)
+	}
+
+	message := "Successfully disabled staff account"
+	if active {
+		message = "Successfully enabled staff account"
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: message,
+		Data:    *admin,
+	})
+}
+
+// ResetAdminPassword is a function to reset the password of a staff account
+//
+//	@Summary		Reset a staff password (strictly for admin)
+//	@Description	Replace the password of an admin account with a temporary one that is emailed to them
+//	@Tags			admin
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"Admin ID"
+//	@Success		200	{object}	model.ResponseHTTP{data=model.AdminResponse}
+//	@Failure		404	{object}	model.ResponseHTTP{}
+//	@Failure		500	{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/admin/staff/{id}/reset-password [post]
+func (h *AdminHandler) ResetAdminPassword(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	admin, err := h.adminService.ResetAdminPassword(id)
+	if err != nil {
+		return h.adminError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully reset staff password",
+		Data:    *admin,
+	})
+}
+
+// ChangePassword is a function for the signed in admin to change their password
+//
+//	@Summary		Change own password (strictly for admin)
+//	@Description	Replace the password of the signed in admin, for example after signing in with a temporary password. Every other session of the admin is signed out
+//	@Tags			admin
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		model.AdminPasswordChangeRequest	true	"Passwords"
+//	@Success		200		{object}	model.ResponseHTTP{}
+//	@Failure		400		{object}	model.ResponseHTTP{}
+//	@Failure		500		{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/admin/me/password [put]
+func (h *AdminHandler) ChangePassword(c *fiber.Ctx) error {
+	var payload model.AdminPasswordChangeRequest
+
+	if err := c.BodyParser(&payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Invalid request",
+			Data:    nil,
+		})
+	}
+
+	if err := h.validator.Validate(payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	if err := h.adminService.ChangePassword(auth.ActorFromContext(c), &payload); err != nil {
+		return h.adminError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully changed password",
+		Data:    nil,
+	})
+}
+
+func (*AdminHandler) adminError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Admin not found",
+			Data:    nil,
+		})
+	case errors.Is(err, service.ErrIncorrectCredentials):
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Incorrect username or password",
+			Data:    nil,
+		})
+	case errors.Is(err, service.ErrAdminDisabled):
+		return c.Status(fiber.StatusForbidden).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "This account has been disabled",
+			Data:    nil,
+		})
+	case errors.Is(err, service.ErrInvalidChallenge), errors.Is(err, service.ErrInvalidTwoFactorCode):
+		return c.Status(fiber.StatusUnauthorized).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	case errors.Is(err, service.ErrTwoFactorLocked):
+		return c.Status(fiber.StatusTooManyRequests).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	case errors.Is(err, service.ErrCannotDisableSelf),
+		errors.Is(err, service.ErrTwoFactorAlreadyEnabled),
+		errors.Is(err, service.ErrTwoFactorNotEnabled),
+		errors.Is(err, service.ErrTwoFactorNotStarted):
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	case strings.Contains(err.Error(), "role not found"):
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "One of the roles does not exist",
+			Data:    nil,
+		})
+	case strings.Contains(err.Error(), "admin already exists"):
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "An admin with this username or email already exists",
+			Data:    nil,
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Internal server error",
+			Data:    nil,
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/MogboPython/belvaphilips_backend/internal/service"
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/gofiber/fiber/v2"
+)
+
+type AnalyticsHandler struct {
+	analyticsService service.AnalyticsService
+}
+
+func NewAnalyticsHandler(analyticsService service.AnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		analyticsService: analyticsService,
+	}
+}
+
+// GetAnalytics is a function to get the figures of the admin dashboard
+//
+//	@Summary		Get dashboard analytics (strictly for admin)
+//	@Description	Get the orders and new users per day, week or month, the orders by shoot type and delivery speed, the average time to complete an order and the top customers over a date range. The range defaults to the last 30 days
+//	@Tags			analytics
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			from		query		string	false	"Start of the range, as a date (2006-01-02) or time (RFC 3339)"
+//	@Param			to			query		string	false	"End of the range, as a date, inclusive, or time"
+//	@Param			interval	query		string	false	"day (default), week or month"
+//	@Success		200			{object}	model.ResponseHTTP{data=model.AnalyticsResponse}
+//	@Failure		400			{object}	model.ResponseHTTP{}
+//	@Failure		500			{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/admin/analytics [get]
+func (h *AnalyticsHandler) GetAnalytics(c *fiber.Ctx) error {
+	var payload model.AnalyticsRequest
+
+	if err := c.QueryParser(&payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Invalid request",
+			Data:    nil,
+		})
+	}
+
+	analytics, err := h.analyticsService.GetAnalytics(&payload)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidAnalyticsRange) {
+			return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+				Success: false,
+				Message: err.Error(),
+				Data:    nil,
+			})
+		}
+
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Internal server error",
+			Data:    nil,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully retrieved analytics",
+		Data:    *analytics,
+	})
+}
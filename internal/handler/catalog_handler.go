@@ -0,0 +1,226 @@
+package handler
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/MogboPython/belvaphilips_backend/internal/service"
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/MogboPython/belvaphilips_backend/pkg/validator"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type CatalogHandler struct {
+	catalogService service.CatalogService
+	validator      *validator.Validator
+}
+
+func NewCatalogHandler(catalogService service.CatalogService) *CatalogHandler {
+	return &CatalogHandler{
+		catalogService: catalogService,
+		validator:      validator.New(),
+	}
+}
+
+// GetCatalog is a function to get the options customers can order
+//
+//	@Summary		Get the product catalog
+//	@Description	Get the active shoot types, finish types, shots and delivery speeds
+//	@Tags			catalog
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	model.ResponseHTTP{data=model.CatalogResponse}
+//	@Failure		500	{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/catalog [get]
+func (h *CatalogHandler) GetCatalog(c *fiber.Ctx) error {
+	return h.getCatalog(c, true)
+}
+
+// GetFullCatalog is a function to get every catalog item, including inactive ones
+//
+//	@Summary		Get the full product catalog (strictly for admin)
+//	@Description	Get every shoot type, finish type, shot and delivery speed, including inactive ones
+//	@Tags			catalog
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	model.ResponseHTTP{data=model.CatalogResponse}
+//	@Failure		500	{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/admin/catalog [get]
+func (h *CatalogHandler) GetFullCatalog(c *fiber.Ctx) error {
+	return h.getCatalog(c, false)
+}
+
+func (h *CatalogHandler) getCatalog(c *fiber.Ctx, activeOnly bool) error {
+	catalog, err := h.catalogService.GetCatalog(activeOnly)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Internal server error",
+			Data:    nil,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully retrieved catalog",
+		Data:    *catalog,
+	})
+}
+
+// CreateCatalogItem is a function to add an option to the catalog
+//
+//	@Summary		Create a catalog item (strictly for admin)
+//	@Description	Add a shoot type, finish type, shot or delivery speed to the catalog
+//	@Tags			catalog
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		model.CatalogItemRequest	true	"Catalog item"
+//	@Success		201		{object}	model.ResponseHTTP{data=model.CatalogItemResponse}
+//	@Failure		400		{object}	model.ResponseHTTP{}
+//	@Failure		500		{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/admin/catalog [post]
+func (h *CatalogHandler) CreateCatalogItem(c *fiber.Ctx) error {
+	var payload model.CatalogItemRequest
+
+	if err := c.BodyParser(&payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Invalid request",
+			Data:    nil,
+		})
+	}
+
+	if err := h.validator.Validate(payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	item, err := h.catalogService.CreateItem(&payload)
+	if err != nil {
+		return h.catalogError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully created catalog item",
+		Data:    *item,
+	})
+}
+
+// UpdateCatalogItem is a function to update an option of the catalog
+//
+//	@Summary		Update a catalog item (strictly for admin)
+//	@Description	Update the name, description, price, sort order or active flag of a catalog item
+//	@Tags			catalog
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string						true	"Catalog item ID"
+//	@Param			request	body		model.CatalogItemRequest	true	"Catalog item"
+//	@Success		200		{object}	model.ResponseHTTP{data=model.CatalogItemResponse}
+//	@Failure		400		{object}	model.ResponseHTTP{}
+//	@Failure		404		{object}	model.ResponseHTTP{}
+//	@Failure		500		{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/admin/catalog/{id} [put]
+func (h *CatalogHandler) UpdateCatalogItem(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var payload model.CatalogItemRequest
+
+	if err := c.BodyParser(&payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Invalid request",
+			Data:    nil,
+		})
+	}
+
+	if err := h.validator.Validate(payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	item, err := h.catalogService.UpdateItem(id, &payload)
+	if err != nil {
+		return h.catalogError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully updated catalog item",
+		Data:    *item,
+	})
+}
+
+// DeleteCatalogItem is a function to remove an option from the catalog
+//
+//	@Summary		Delete a catalog item (strictly for admin)
+//	@Description	Remove an item from the catalog. Existing orders keep the option they were placed with
+//	@Tags			catalog
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"Catalog item ID"
+//	@Success		200	{object}	model.ResponseHTTP{}
+//	@Failure		404	{object}	model.ResponseHTTP{}
+//	@Failure		500	{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/admin/catalog/{id} [delete]
+func (h *CatalogHandler) DeleteCatalogItem(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	if err := h.catalogService.DeleteItem(id); err != nil {
+		return h.catalogError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully deleted catalog item",
+		Data:    nil,
+	})
+}
+
+func (*CatalogHandler) catalogError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Catalog item not found",
+			Data:    nil,
+		})
+	case errors.Is(err, service.ErrCatalogNameRequired):
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	case strings.Contains(err.Error(), "catalog item already exists"):
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "A catalog item with this name already exists in the category",
+			Data:    nil,
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Internal server error",
+			Data:    nil,
+		})
+	}
+}
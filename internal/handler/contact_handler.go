@@ -7,6 +7,18 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
+type ContactHandler struct {
+	contactService service.ContactService
+	validator      *validator.Validator
+}
+
+func NewContactHandler(contactService service.ContactService) *ContactHandler {
+	return &ContactHandler{
+		contactService: contactService,
+		validator:      validator.New(),
+	}
+}
+
 // ContactUs handles the contact us form submissions
 //
 //	@Summary		Submit contact form
@@ -19,11 +31,9 @@ import (
 //	@Failure		400		{object}	model.ResponseHTTP{}
 //	@Failure		500		{object}	model.ResponseHTTP{}
 //	@Router			/api/v1/contact [post]
-func ContactUs(c *fiber.Ctx) error {
+func (h *ContactHandler) ContactUs(c *fiber.Ctx) error {
 	var req model.ContactUsRequest
 
-	v := validator.New()
-
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
 			Success: false,
@@ -32,7 +42,7 @@ func ContactUs(c *fiber.Ctx) error {
 		})
 	}
 
-	if err := v.Validate(req); err != nil {
+	if err := h.validator.Validate(req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
 			Success: false,
 			Message: err.Error(),
@@ -40,7 +50,7 @@ func ContactUs(c *fiber.Ctx) error {
 		})
 	}
 
-	if err := service.SendContactEmail(&req); err != nil {
+	if err := h.contactService.SendContactEmail(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
 			Success: false,
 			Message: "Failed to send contact email",
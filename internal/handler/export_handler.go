@@ -0,0 +1,221 @@
+package handler
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/MogboPython/belvaphilips_backend/internal/auth"
+	"github.com/MogboPython/belvaphilips_backend/internal/service"
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+)
+
+type ExportHandler struct {
+	exportService service.ExportService
+}
+
+func NewExportHandler(exportService service.ExportService) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+	}
+}
+
+// GetUserExport is a function to request an export of everything held about a user
+//
+//	@Summary		Export personal data of a user
+//	@Description	Queue a ZIP archive with the profile and orders of a user as JSON and CSV, or return the one already queued. Poll until the status is ready and download it from download_url
+//	@Tags			users
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"User ID"
+//	@Success		200	{object}	model.ResponseHTTP{data=model.DataExportResponse}
+//	@Success		202	{object}	model.ResponseHTTP{data=model.DataExportResponse}
+//	@Failure		404	{object}	model.ResponseHTTP{}
+//	@Failure		500	{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/users/{id}/export [get]
+func (h *ExportHandler) GetUserExport(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	export, err := h.exportService.RequestUserExport(id, auth.ActorFromContext(c))
+	if err != nil {
+		return h.exportError(c, err)
+	}
+
+	if export.Status != model.DataExportStatusReady {
+		return c.Status(fiber.StatusAccepted).JSON(model.ResponseHTTP{
+			Success: true,
+			Message: "The export is being prepared",
+			Data:    *export,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "The export is ready for download",
+		Data:    *export,
+	})
+}
+
+// DownloadUserExport is a function to download a finished export of a user
+//
+//	@Summary		Download personal data export
+//	@Description	Download the ZIP archive of a finished export. Links expire seven days after the export is ready
+//	@Tags			users
+//
+//	@Security		BearerAuth
+//
+//	@Produce		application/zip
+//	@Param			id			path		string	true	"User ID"
+//	@Param			export_id	path		string	true	"Export ID"
+//	@Success		200			{file}		file
+//	@Failure		404			{object}	model.ResponseHTTP{}
+//	@Failure		409			{object}	model.ResponseHTTP{}
+//	@Failure		500			{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/users/{id}/export/{export_id}/download [get]
+func (h *ExportHandler) DownloadUserExport(c *fiber.Ctx) error {
+	export, err := h.exportService.GetUserExportArchive(c.Params("id"), c.Params("export_id"))
+	if err != nil {
+		return h.exportError(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, "application/zip")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", export.FileName))
+
+	return c.Send(export.Archive)
+}
+
+// ExportOrders is a function to download the orders as a spreadsheet
+//
+//	@Summary		Export orders (strictly for admin)
+//	@Description	Download the orders matching the filters of the order list as CSV or XLSX. Every key of the order details gets its own column. The file is streamed, so if the export fails part way the connection is closed before the end of the response and the download fails instead of stopping short
+//	@Tags			exports
+//
+//	@Security		BearerAuth
+//
+//	@Produce		text/csv
+//	@Produce		application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+//	@Param			format			query		string	false	"csv (default) or xlsx"
+//	@Param			status			query		string	false	"Order status, or a group of them (active, pending, completed or deleted)"
+//	@Param			user_id			query		string	false	"ID of the customer who placed the orders"
+//	@Param			shoot_type		query		string	false	"Shoot type"
+//	@Param			delivery_speed	query		string	false	"Delivery speed"
+//	@Param			membership_type	query		string	false	"Membership type"
+//	@Param			created_from	query		string	false	"Orders created from this date (2006-01-02) or time (RFC 3339)"
+//	@Param			created_to		query		string	false	"Orders created until this date, inclusive, or before this time"
+//	@Param			search			query		string	false	"Search the order name, product name and customer email"
+//	@Param			sort			query		string	false	"created_at (default), updated_at, order_name, product_name, status, quantity or quoted_amount"
+//	@Param			order			query		string	false	"asc or desc (default)"
+//	@Success		200				{file}		file
+//	@Failure		400				{object}	model.ResponseHTTP{}
+//	@Failure		500				{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/admin/exports/orders [get]
+func (h *ExportHandler) ExportOrders(c *fiber.Ctx) error {
+	var filter model.OrderFilterRequest
+
+	if err := c.QueryParser(&filter); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Invalid request",
+			Data:    nil,
+		})
+	}
+
+	spreadsheet, err := h.exportService.ExportOrders(spreadsheetFormat(c), &filter)
+	if err != nil {
+		return h.exportError(c, err)
+	}
+
+	return sendSpreadsheet(c, spreadsheet)
+}
+
+// ExportUsers is a function to download the customers as a spreadsheet
+//
+//	@Summary		Export users (strictly for admin)
+//	@Description	Download every customer as CSV or XLSX. The file is streamed, so if the export fails part way the connection is closed before the end of the response and the download fails instead of stopping short
+//	@Tags			exports
+//
+//	@Security		BearerAuth
+//
+//	@Produce		text/csv
+//	@Produce		application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+//	@Param			format	query		string	false	"csv (default) or xlsx"
+//	@Success		200		{file}		file
+//	@Failure		400		{object}	model.ResponseHTTP{}
+//	@Failure		500		{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/admin/exports/users [get]
+func (h *ExportHandler) ExportUsers(c *fiber.Ctx) error {
+	spreadsheet, err := h.exportService.ExportUsers(spreadsheetFormat(c))
+	if err != nil {
+		return h.exportError(c, err)
+	}
+
+	return sendSpreadsheet(c, spreadsheet)
+}
+
+func spreadsheetFormat(c *fiber.Ctx) string {
+	return strings.ToLower(c.Query("format", model.SpreadsheetFormatCSV))
+}
+
+// sendSpreadsheet streams a spreadsheet to the client as it is written. The status is sent before
+// the first row, so when writing fails part way the connection is closed without ending the
+// chunked body. The client then sees a broken download rather than a complete looking file
+func sendSpreadsheet(c *fiber.Ctx, spreadsheet *model.Spreadsheet) error {
+	c.Set(fiber.HeaderContentType, spreadsheet.ContentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", spreadsheet.FileName))
+
+	conn := c.Context().Conn()
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := spreadsheet.Write(w); err != nil {
+			log.Errorf("Failed to write %s: %v", spreadsheet.FileName, err)
+
+			if err := conn.Close(); err != nil {
+				log.Errorf("Failed to close the connection of %s: %v", spreadsheet.FileName, err)
+			}
+
+			return
+		}
+
+		if err := w.Flush(); err != nil {
+			log.Errorf("Failed to send %s: %v", spreadsheet.FileName, err)
+		}
+	})
+
+	return nil
+}
+
+func (*ExportHandler) exportError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Export not found",
+			Data:    nil,
+		})
+	case errors.Is(err, service.ErrUnknownExportFormat), errors.Is(err, service.ErrInvalidOrderFilter):
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	case errors.Is(err, service.ErrExportNotReady):
+		return c.Status(fiber.StatusConflict).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Internal server error",
+			Data:    nil,
+		})
+	}
+}
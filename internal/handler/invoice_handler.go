@@ -0,0 +1,276 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/MogboPython/belvaphilips_backend/internal/pdf"
+	"github.com/MogboPython/belvaphilips_backend/internal/service"
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/MogboPython/belvaphilips_backend/pkg/validator"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type InvoiceHandler struct {
+	invoiceService service.InvoiceService
+	validator      *validator.Validator
+}
+
+func NewInvoiceHandler(invoiceService service.InvoiceService) *InvoiceHandler {
+	return &InvoiceHandler{
+		invoiceService: invoiceService,
+		validator:      validator.New(),
+	}
+}
+
+// CreateInvoice is a function to draft an invoice for an order
+//
+//	@Summary		Create an invoice for an order (strictly for admin)
+//	@Description	Draft an invoice from the accepted quote of an order, with an optional discount and tax rate
+//	@Tags			invoices
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string					true	"Order ID"
+//	@Param			request	body		model.InvoiceRequest	true	"Invoice adjustments"
+//	@Success		201		{object}	model.ResponseHTTP{data=model.InvoiceResponse}
+//	@Failure		400		{object}	model.ResponseHTTP{}
+//	@Failure		404		{object}	model.ResponseHTTP{}
+//	@Failure		409		{object}	model.ResponseHTTP{}
+//	@Failure		500		{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/orders/{id}/invoice [post]
+func (h *InvoiceHandler) CreateInvoice(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var payload model.InvoiceRequest
+
+	if err := c.BodyParser(&payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Invalid request",
+			Data:    nil,
+		})
+	}
+
+	if err := h.validator.Validate(payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	invoice, err := h.invoiceService.CreateInvoice(id, &payload)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(model.ResponseHTTP{
+				Success: false,
+				Message: "Order not found",
+				Data:    nil,
+			})
+		}
+
+		return h.invoiceError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully created invoice",
+		Data:    *invoice,
+	})
+}
+
+// InvoiceOwner resolves the customer billed by the invoice in the id route parameter and the
+// organization of the order, for middleware.OwnerMemberOrAdmin
+func (h *InvoiceHandler) InvoiceOwner(c *fiber.Ctx) (*model.ResourceOwner, error) {
+	return h.invoiceService.GetInvoiceOwner(c.Params("id"))
+}
+
+// GetAllInvoices is a function to get all invoices
+//
+//	@Summary		Get all invoices (strictly for admin)
+//	@Description	Fetch a paginated list of invoices, optionally filtered by status
+//	@Tags			invoices
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			page	query		int		false	"Page number (default is 1)"
+//	@Param			limit	query		int		false	"Number of invoices per page (default is 10)"
+//	@Param			status	query		string	false	"Invoice status (draft, issued, paid or void)"
+//	@Success		200		{object}	model.ResponseHTTP{data=model.TotalInvoiceResponse}
+//	@Failure		500		{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/invoices [get]
+func (h *InvoiceHandler) GetAllInvoices(c *fiber.Ctx) error {
+	pageStr := c.Query("page", "1")
+	limitStr := c.Query("limit", "10")
+	status := c.Query("status", "")
+
+	invoices, err := h.invoiceService.GetAllInvoices(pageStr, limitStr, status)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Internal server error",
+			Data:    nil,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully retrieved invoices",
+		Data:    invoices,
+	})
+}
+
+// GetInvoiceByID is a function to get an invoice by ID
+//
+//	@Summary		Get invoice by ID
+//	@Description	Get an invoice with its line items
+//	@Tags			invoices
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"Invoice ID"
+//	@Success		200	{object}	model.ResponseHTTP{data=model.InvoiceResponse}
+//	@Failure		404	{object}	model.ResponseHTTP{}
+//	@Failure		500	{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/invoices/{id} [get]
+func (h *InvoiceHandler) GetInvoiceByID(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	invoice, err := h.invoiceService.GetInvoiceByID(id)
+	if err != nil {
+		return h.invoiceError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully found invoice",
+		Data:    *invoice,
+	})
+}
+
+// UpdateInvoiceStatus is a function to issue, settle or void an invoice
+//
+//	@Summary		Update invoice status (strictly for admin)
+//	@Description	Move an invoice from draft to issued, or from issued to paid. Draft and issued invoices can be voided
+//	@Tags			invoices
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string								true	"Invoice ID"
+//	@Param			request	body		model.InvoiceStatusChangeRequest	true	"New status"
+//	@Success		200		{object}	model.ResponseHTTP{data=model.InvoiceResponse}
+//	@Failure		400		{object}	model.ResponseHTTP{}
+//	@Failure		404		{object}	model.ResponseHTTP{}
+//	@Failure		500		{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/invoices/{id}/status [put]
+func (h *InvoiceHandler) UpdateInvoiceStatus(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var payload model.InvoiceStatusChangeRequest
+
+	if err := c.BodyParser(&payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Invalid request",
+			Data:    nil,
+		})
+	}
+
+	if err := h.validator.Validate(payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	invoice, err := h.invoiceService.UpdateInvoiceStatus(id, &payload)
+	if err != nil {
+		return h.invoiceError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully updated invoice status",
+		Data:    *invoice,
+	})
+}
+
+// GetInvoicePDF is a function to download an invoice as a PDF
+//
+//	@Summary		Download invoice PDF
+//	@Description	Render the invoice from templates/invoice.html and return it as a PDF document
+//	@Tags			invoices
+//
+//	@Security		BearerAuth
+//
+//	@Produce		application/pdf
+//	@Param			id	path		string	true	"Invoice ID"
+//	@Success		200	{file}		binary
+//	@Failure		404	{object}	model.ResponseHTTP{}
+//	@Failure		500	{object}	model.ResponseHTTP{}
+//	@Failure		503	{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/invoices/{id}/pdf [get]
+func (h *InvoiceHandler) GetInvoicePDF(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	number, document, err := h.invoiceService.RenderInvoicePDF(c.UserContext(), id)
+	if err != nil {
+		if errors.Is(err, pdf.ErrRendererNotConfigured) {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(model.ResponseHTTP{
+				Success: false,
+				Message: "PDF rendering is not available",
+				Data:    nil,
+			})
+		}
+
+		return h.invoiceError(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, "application/pdf")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("inline; filename=%q", number+".pdf"))
+
+	return c.Status(fiber.StatusOK).Send(document)
+}
+
+func (*InvoiceHandler) invoiceError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Invoice not found",
+			Data:    nil,
+		})
+	case errors.Is(err, service.ErrQuoteNotAccepted),
+		errors.Is(err, service.ErrInvalidInvoiceTransition),
+		errors.Is(err, service.ErrDiscountExceedsSubtotal):
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	case strings.Contains(err.Error(), "order already has an open invoice"):
+		return c.Status(fiber.StatusConflict).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Order already has an open invoice, void it before creating another",
+			Data:    nil,
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Internal server error",
+			Data:    nil,
+		})
+	}
+}
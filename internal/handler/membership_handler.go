@@ -0,0 +1,317 @@
+package handler
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/MogboPython/belvaphilips_backend/internal/service"
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/MogboPython/belvaphilips_backend/pkg/validator"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type MembershipHandler struct {
+	membershipService service.MembershipService
+	validator         *validator.Validator
+}
+
+func NewMembershipHandler(membershipService service.MembershipService) *MembershipHandler {
+	return &MembershipHandler{
+		membershipService: membershipService,
+		validator:         validator.New(),
+	}
+}
+
+// GetPlans is a function to get the membership plans customers can subscribe to
+//
+//	@Summary		Get membership plans
+//	@Description	Get the active membership plans with their monthly price, included shots and discount
+//	@Tags			memberships
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	model.ResponseHTTP{data=[]model.MembershipPlan}
+//	@Failure		500	{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/membership-plans [get]
+func (h *MembershipHandler) GetPlans(c *fiber.Ctx) error {
+	return h.getPlans(c, true)
+}
+
+// GetAllPlans is a function to get every membership plan, including inactive ones
+//
+//	@Summary		Get all membership plans (strictly for admin)
+//	@Description	Get every membership plan, including the ones no longer open to new subscribers
+//	@Tags			memberships
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	model.ResponseHTTP{data=[]model.MembershipPlan}
+//	@Failure		500	{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/admin/membership-plans [get]
+func (h *MembershipHandler) GetAllPlans(c *fiber.Ctx) error {
+	return h.getPlans(c, false)
+}
+
+func (h *MembershipHandler) getPlans(c *fiber.Ctx, activeOnly bool) error {
+	plans, err := h.membershipService.GetPlans(activeOnly)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Internal server error",
+			Data:    nil,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully retrieved membership plans",
+		Data:    plans,
+	})
+}
+
+// CreatePlan is a function to add a membership plan
+//
+//	@Summary		Create a membership plan (strictly for admin)
+//	@Description	Add a monthly plan with the shots it covers per period and the discount it gives on quotes
+//	@Tags			memberships
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		model.MembershipPlanRequest	true	"Membership plan"
+//	@Success		201		{object}	model.ResponseHTTP{data=model.MembershipPlan}
+//	@Failure		400		{object}	model.ResponseHTTP{}
+//	@Failure		500		{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/admin/membership-plans [post]
+func (h *MembershipHandler) CreatePlan(c *fiber.Ctx) error {
+	var payload model.MembershipPlanRequest
+
+	if err := c.BodyParser(&payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Invalid request",
+			Data:    nil,
+		})
+	}
+
+	if err := h.validator.Validate(payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	plan, err := h.membershipService.CreatePlan(&payload)
+	if err != nil {
+		return h.membershipError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully created membership plan",
+		Data:    *plan,
+	})
+}
+
+// UpdatePlan is a function to update a membership plan
+//
+//	@Summary		Update a membership plan (strictly for admin)
+//	@Description	Update a plan for new and current subscribers. An inactive plan keeps its subscribers but takes no new ones
+//	@Tags			memberships
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string						true	"Membership plan ID"
+//	@Param			request	body		model.MembershipPlanRequest	true	"Membership plan"
+//	@Success		200		{object}	model.ResponseHTTP{data=model.MembershipPlan}
+//	@Failure		400		{object}	model.ResponseHTTP{}
+//	@Failure		404		{object}	model.ResponseHTTP{}
+//	@Failure		500		{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/admin/membership-plans/{id} [put]
+func (h *MembershipHandler) UpdatePlan(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var payload model.MembershipPlanRequest
+
+	if err := c.BodyParser(&payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Invalid request",
+			Data:    nil,
+		})
+	}
+
+	if err := h.validator.Validate(payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	plan, err := h.membershipService.UpdatePlan(id, &payload)
+	if err != nil {
+		return h.membershipError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully updated membership plan",
+		Data:    *plan,
+	})
+}
+
+// GetSubscription is a function to get the membership plan a user is on
+//
+//	@Summary		Get the membership of a user
+//	@Description	Get the active subscription of a user with the shots left in the current period
+//	@Tags			memberships
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"User ID"
+//	@Success		200	{object}	model.ResponseHTTP{data=model.SubscriptionResponse}
+//	@Failure		404	{object}	model.ResponseHTTP{}
+//	@Failure		500	{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/users/{id}/membership [get]
+func (h *MembershipHandler) GetSubscription(c *fiber.Ctx) error {
+	subscription, err := h.membershipService.GetSubscription(c.Params("id"))
+	if err != nil {
+		return h.membershipError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully retrieved membership",
+		Data:    *subscription,
+	})
+}
+
+// Subscribe is a function to put a user on a membership plan
+//
+//	@Summary		Subscribe a user to a membership plan (strictly for admin)
+//	@Description	Start a monthly subscription to a plan from now, replacing the plan the user is on
+//	@Tags			memberships
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string						true	"User ID"
+//	@Param			request	body		model.SubscriptionRequest	true	"Membership plan"
+//	@Success		201		{object}	model.ResponseHTTP{data=model.SubscriptionResponse}
+//	@Failure		400		{object}	model.ResponseHTTP{}
+//	@Failure		404		{object}	model.ResponseHTTP{}
+//	@Failure		409		{object}	model.ResponseHTTP{}
+//	@Failure		500		{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/users/{id}/membership [put]
+func (h *MembershipHandler) Subscribe(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var payload model.SubscriptionRequest
+
+	if err := c.BodyParser(&payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Invalid request",
+			Data:    nil,
+		})
+	}
+
+	if err := h.validator.Validate(payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	subscription, err := h.membershipService.Subscribe(id, &payload)
+	if err != nil {
+		return h.membershipError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully updated membership",
+		Data:    *subscription,
+	})
+}
+
+// CancelSubscription is a function to end the membership of a user
+//
+//	@Summary		Cancel the membership of a user (strictly for admin)
+//	@Description	End the subscription of a user when the current period ends, or right away with immediately=true
+//	@Tags			memberships
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id			path		string	true	"User ID"
+//	@Param			immediately	query		bool	false	"End the subscription now instead of at the end of the period"
+//	@Success		200			{object}	model.ResponseHTTP{data=model.SubscriptionResponse}
+//	@Failure		404			{object}	model.ResponseHTTP{}
+//	@Failure		500			{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/users/{id}/membership [delete]
+func (h *MembershipHandler) CancelSubscription(c *fiber.Ctx) error {
+	subscription, err := h.membershipService.CancelSubscription(c.Params("id"), c.QueryBool("immediately"))
+	if err != nil {
+		return h.membershipError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully cancelled membership",
+		Data:    *subscription,
+	})
+}
+
+func (*MembershipHandler) membershipError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Not found",
+			Data:    nil,
+		})
+	case errors.Is(err, service.ErrPlanNotAvailable):
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	case errors.Is(err, service.ErrAlreadySubscribed):
+		return c.Status(fiber.StatusConflict).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	case strings.Contains(err.Error(), "membership plan already exists"):
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "A membership plan with this name already exists",
+			Data:    nil,
+		})
+	case strings.Contains(err.Error(), "subscription was changed concurrently"):
+		return c.Status(fiber.StatusConflict).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "The membership was changed at the same time, please try again",
+			Data:    nil,
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Internal server error",
+			Data:    nil,
+		})
+	}
+}
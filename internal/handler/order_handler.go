@@ -4,6 +4,7 @@ import (
 	"errors"
 	"strings"
 
+	"github.com/MogboPython/belvaphilips_backend/internal/auth"
 	"github.com/MogboPython/belvaphilips_backend/internal/service"
 	"github.com/MogboPython/belvaphilips_backend/pkg/model"
 	"github.com/MogboPython/belvaphilips_backend/pkg/validator"
@@ -36,6 +37,7 @@ func NewOrderHandler(orderService service.OrderService) *OrderHandler {
 //	@Param			request	body		model.OrderRequest	true	"Order information"
 //	@Success		201		{object}	model.ResponseHTTP{data=model.OrderResponse}
 //	@Failure		400		{object}	model.ResponseHTTP{}
+//	@Failure		403		{object}	model.ResponseHTTP{}
 //	@Failure		500		{object}	model.ResponseHTTP{}
 //	@Router			/api/v1/orders [post]
 func (h *OrderHandler) CreateOrder(c *fiber.Ctx) error {
@@ -57,6 +59,14 @@ func (h *OrderHandler) CreateOrder(c *fiber.Ctx) error {
 		})
 	}
 
+	if actor := auth.ActorFromContext(c); actor.Role != model.RoleAdmin && payload.UserID != actor.ID {
+		return c.Status(fiber.StatusForbidden).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Orders can only be placed for your own account",
+			Data:    nil,
+		})
+	}
+
 	order, err := h.orderService.CreateOrder(&payload)
 	if err != nil {
 		if strings.Contains(err.Error(), "failed to find user") {
@@ -67,6 +77,22 @@ func (h *OrderHandler) CreateOrder(c *fiber.Ctx) error {
 			})
 		}
 
+		if errors.Is(err, service.ErrNotOrganizationMember) {
+			return c.Status(fiber.StatusForbidden).JSON(model.ResponseHTTP{
+				Success: false,
+				Message: err.Error(),
+				Data:    nil,
+			})
+		}
+
+		if errors.Is(err, service.ErrUnknownCatalogOption) {
+			return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+				Success: false,
+				Message: err.Error(),
+				Data:    nil,
+			})
+		}
+
 		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseHTTP{
 			Success: false,
 			Message: "Internal server error",
@@ -84,26 +110,53 @@ func (h *OrderHandler) CreateOrder(c *fiber.Ctx) error {
 // GetAllOrders is a function to get all order data from the database
 //
 //	@Summary		Get all orders (strictly for admin)
-//	@Description	Fetch a paginated list of orders from the database
+//	@Description	Fetch a paginated list of orders, filtered, searched and sorted. The counts by status group ignore the status filter
 //	@Tags			orders
 //
 //	@Security		BearerAuth
 //
 //	@Accept			json
 //	@Produce		json
-//	@Param			page	query		int		false	"Page number (default is 1)"
-//	@Param			limit	query		int		false	"Number of orders per page (default is 10)"
-//	@Param			status	query		string	false	"order status (active or pending)"
-//	@Success		200		{array}		model.ResponseHTTP{data=[]model.TotalOrderResponse}
-//	@Failure		500		{object}	model.ResponseHTTP{}
+//	@Param			page			query		int		false	"Page number (default is 1)"
+//	@Param			limit			query		int		false	"Number of orders per page (default is 10)"
+//	@Param			status			query		string	false	"Order status, or a group of them (active, pending, completed or deleted)"
+//	@Param			user_id			query		string	false	"ID of the customer who placed the orders"
+//	@Param			shoot_type		query		string	false	"Shoot type"
+//	@Param			delivery_speed	query		string	false	"Delivery speed"
+//	@Param			membership_type	query		string	false	"Membership type"
+//	@Param			created_from	query		string	false	"Orders created from this date (2006-01-02) or time (RFC 3339)"
+//	@Param			created_to		query		string	false	"Orders created until this date, inclusive, or before this time"
+//	@Param			search			query		string	false	"Search the order name, product name and customer email"
+//	@Param			sort			query		string	false	"created_at (default), updated_at, order_name, product_name, status, quantity or quoted_amount"
+//	@Param			order			query		string	false	"asc or desc (default)"
+//	@Success		200				{array}		model.ResponseHTTP{data=[]model.TotalOrderResponse}
+//	@Failure		400				{object}	model.ResponseHTTP{}
+//	@Failure		500				{object}	model.ResponseHTTP{}
 //	@Router			/api/v1/orders [get]
 func (h *OrderHandler) GetAllOrders(c *fiber.Ctx) error {
 	pageStr := c.Query("page", "1")
 	limitStr := c.Query("limit", "10")
-	status := c.Query("status", "")
 
-	orders, err := h.orderService.GetAllOrders(pageStr, limitStr, status)
+	var filter model.OrderFilterRequest
+
+	if err := c.QueryParser(&filter); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Invalid request",
+			Data:    nil,
+		})
+	}
+
+	orders, err := h.orderService.GetAllOrders(pageStr, limitStr, &filter)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidOrderFilter) {
+			return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+				Success: false,
+				Message: err.Error(),
+				Data:    nil,
+			})
+		}
+
 		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseHTTP{
 			Success: false,
 			Message: "Internal server error",
@@ -160,6 +213,12 @@ func (h *OrderHandler) GetOrderByID(c *fiber.Ctx) error {
 	})
 }
 
+// OrderOwner resolves the user who placed the order in the id route parameter and its
+// organization, for middleware.OwnerMemberOrAdmin
+func (h *OrderHandler) OrderOwner(c *fiber.Ctx) (*model.ResourceOwner, error) {
+	return h.orderService.GetOrderOwner(c.Params("id"))
+}
+
 // GetOrdersByUserID is a function to get orders by a single user
 //
 //	@Summary		Get order by User ID
@@ -198,10 +257,48 @@ func (h *OrderHandler) GetOrdersByUserID(c *fiber.Ctx) error {
 	})
 }
 
+// GetOrdersByOrganizationID is a function to get the orders of an organization
+//
+//	@Summary		Get orders by organization ID
+//	@Description	Get the orders any member placed for an organization
+//	@Tags			orders
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string	true	"Organization ID"
+//	@Param			page	query		int		false	"Page number (default is 1)"
+//	@Param			limit	query		int		false	"Number of orders per page (default is 10)"
+//	@Success		200		{object}	model.ResponseHTTP{data=[]model.OrderResponse}
+//	@Failure		403		{object}	model.ResponseHTTP{}
+//	@Failure		500		{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/orders/organization/{id} [get]
+func (h *OrderHandler) GetOrdersByOrganizationID(c *fiber.Ctx) error {
+	organizationID := c.Params("id")
+	pageStr := c.Query("page", "1")
+	limitStr := c.Query("limit", "10")
+
+	orders, err := h.orderService.GetOrdersByOrganizationID(organizationID, pageStr, limitStr)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Internal server error",
+			Data:    nil,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully retrieved orders.",
+		Data:    orders,
+	})
+}
+
 // UpdateOrderStatus is a function to update an order status
 //
 //	@Summary		Update the status of an order (strictly for admin)
-//	@Description	Update the status of an order
+//	@Description	Move an order to the next status of its lifecycle (quote_received, quoted, accepted, product_received, shooting, editing, delivered, mark_completed) or cancel it. Orders are marked paid by their payment only
 //	@Tags			orders
 //
 //	@Security		BearerAuth
@@ -211,6 +308,7 @@ func (h *OrderHandler) GetOrdersByUserID(c *fiber.Ctx) error {
 //	@Param			id		path		string							true	"Order ID"
 //	@Param			status	body		model.OrderStatusChangeRequest	true	"Status update"
 //	@Success		200		{object}	model.ResponseHTTP{data=model.OrderResponse}
+//	@Failure		400		{object}	model.ResponseHTTP{}
 //	@Failure		404		{object}	model.ResponseHTTP{}
 //	@Failure		500		{object}	model.ResponseHTTP{}
 //	@Router			/api/v1/orders/{order_id}/status [put]
@@ -235,7 +333,7 @@ func (h *OrderHandler) UpdateOrderStatus(c *fiber.Ctx) error {
 		})
 	}
 
-	order, err := h.orderService.UpdateOrderStatus(id, &payload)
+	order, err := h.orderService.UpdateOrderStatus(id, auth.ActorFromContext(c), &payload)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return c.Status(fiber.StatusNotFound).JSON(model.ResponseHTTP{
@@ -245,6 +343,22 @@ func (h *OrderHandler) UpdateOrderStatus(c *fiber.Ctx) error {
 			})
 		}
 
+		if errors.Is(err, service.ErrInvalidOrderStatus) || errors.Is(err, service.ErrInvalidStatusTransition) {
+			return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+				Success: false,
+				Message: err.Error(),
+				Data:    nil,
+			})
+		}
+
+		if strings.Contains(err.Error(), "order status was changed concurrently") {
+			return c.Status(fiber.StatusConflict).JSON(model.ResponseHTTP{
+				Success: false,
+				Message: "Order status was changed by another request, please retry",
+				Data:    nil,
+			})
+		}
+
 		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseHTTP{
 			Success: false,
 			Message: "Internal server error",
@@ -258,3 +372,574 @@ func (h *OrderHandler) UpdateOrderStatus(c *fiber.Ctx) error {
 		Data:    *order,
 	})
 }
+
+// GetOrderStatusHistory is a function to get the status changes of an order
+//
+//	@Summary		Get the status history of an order
+//	@Description	Get every status change of an order, oldest first
+//	@Tags			orders
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"Order ID"
+//	@Success		200	{object}	model.ResponseHTTP{data=[]model.OrderStatusHistoryResponse}
+//	@Failure		404	{object}	model.ResponseHTTP{}
+//	@Failure		500	{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/orders/{id}/history [get]
+func (h *OrderHandler) GetOrderStatusHistory(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	history, err := h.orderService.GetOrderStatusHistory(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(model.ResponseHTTP{
+				Success: false,
+				Message: "Order not found",
+				Data:    nil,
+			})
+		}
+
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Internal server error",
+			Data:    nil,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully retrieved order history",
+		Data:    history,
+	})
+}
+
+// GetStatusEmailSettings is a function to list which order statuses email the customer
+//
+//	@Summary		Get order status email settings (strictly for admin)
+//	@Description	List, per order status, whether customers are emailed when their order enters it
+//	@Tags			admin
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	model.ResponseHTTP{data=[]model.OrderStatusEmailSetting}
+//	@Failure		500	{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/admin/order-status-emails [get]
+func (h *OrderHandler) GetStatusEmailSettings(c *fiber.Ctx) error {
+	settings, err := h.orderService.GetStatusEmailSettings()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Internal server error",
+			Data:    nil,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully retrieved status email settings",
+		Data:    settings,
+	})
+}
+
+// UpdateStatusEmailSetting is a function to enable or disable customer emails for an order status
+//
+//	@Summary		Update an order status email setting (strictly for admin)
+//	@Description	Enable or disable the email sent to customers when their order enters a status
+//	@Tags			admin
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			status	path		string									true	"Order status"
+//	@Param			request	body		model.OrderStatusEmailSettingRequest	true	"Email setting"
+//	@Success		200		{object}	model.ResponseHTTP{data=model.OrderStatusEmailSetting}
+//	@Failure		400		{object}	model.ResponseHTTP{}
+//	@Failure		500		{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/admin/order-status-emails/{status} [put]
+func (h *OrderHandler) UpdateStatusEmailSetting(c *fiber.Ctx) error {
+	status := c.Params("status")
+
+	var payload model.OrderStatusEmailSettingRequest
+
+	if err := c.BodyParser(&payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Invalid request",
+			Data:    nil,
+		})
+	}
+
+	if err := h.validator.Validate(payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	setting, err := h.orderService.UpdateStatusEmailSetting(status, &payload)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidOrderStatus) {
+			return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+				Success: false,
+				Message: err.Error(),
+				Data:    nil,
+			})
+		}
+
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Internal server error",
+			Data:    nil,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully updated status email setting",
+		Data:    *setting,
+	})
+}
+
+// PreviewQuote is a function to price an order from its options without issuing a quote
+//
+//	@Summary		Preview the quote of an order (strictly for admin)
+//	@Description	Derive quote line items from the shoot type, finish type, shots, quantity and delivery speed of an order
+//	@Tags			quotes
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"Order ID"
+//	@Success		200	{object}	model.ResponseHTTP{data=model.QuoteResponse}
+//	@Failure		404	{object}	model.ResponseHTTP{}
+//	@Failure		500	{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/orders/{id}/quote/preview [get]
+func (h *OrderHandler) PreviewQuote(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	quote, err := h.orderService.PreviewQuote(id)
+	if err != nil {
+		return h.quoteError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully priced order",
+		Data:    *quote,
+	})
+}
+
+// IssueQuote is a function to send a quote for an order to the customer
+//
+//	@Summary		Issue a quote for an order (strictly for admin)
+//	@Description	Issue a quote for an order in quote_received. Line items are derived from the order options unless provided
+//	@Tags			quotes
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string				true	"Order ID"
+//	@Param			request	body		model.QuoteRequest	true	"Quote information"
+//	@Success		201		{object}	model.ResponseHTTP{data=model.QuoteResponse}
+//	@Failure		400		{object}	model.ResponseHTTP{}
+//	@Failure		404		{object}	model.ResponseHTTP{}
+//	@Failure		500		{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/orders/{id}/quote [post]
+func (h *OrderHandler) IssueQuote(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var payload model.QuoteRequest
+
+	if err := c.BodyParser(&payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Invalid request",
+			Data:    nil,
+		})
+	}
+
+	if err := h.validator.Validate(payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	quote, err := h.orderService.IssueQuote(id, auth.ActorFromContext(c), &payload)
+	if err != nil {
+		return h.quoteError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully issued quote",
+		Data:    *quote,
+	})
+}
+
+// GetQuote is a function to get the latest quote of an order
+//
+//	@Summary		Get the quote of an order
+//	@Description	Get the most recent quote issued for an order
+//	@Tags			quotes
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"Order ID"
+//	@Success		200	{object}	model.ResponseHTTP{data=model.QuoteResponse}
+//	@Failure		404	{object}	model.ResponseHTTP{}
+//	@Failure		500	{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/orders/{id}/quote [get]
+func (h *OrderHandler) GetQuote(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	quote, err := h.orderService.GetQuote(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(model.ResponseHTTP{
+				Success: false,
+				Message: "Quote not found",
+				Data:    nil,
+			})
+		}
+
+		return h.quoteError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully found quote",
+		Data:    *quote,
+	})
+}
+
+// AcceptQuote is a function for the customer to accept the pending quote of an order
+//
+//	@Summary		Accept the quote of an order
+//	@Description	Accept the pending quote, moving the order to accepted. Only the customer who placed the order and members of its organization can do this
+//	@Tags			quotes
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"Order ID"
+//	@Success		200	{object}	model.ResponseHTTP{data=model.OrderResponse}
+//	@Failure		400	{object}	model.ResponseHTTP{}
+//	@Failure		404	{object}	model.ResponseHTTP{}
+//	@Failure		500	{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/orders/{id}/quote/accept [post]
+func (h *OrderHandler) AcceptQuote(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	order, err := h.orderService.AcceptQuote(id, auth.ActorFromContext(c))
+	if err != nil {
+		return h.quoteError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully accepted quote",
+		Data:    *order,
+	})
+}
+
+// RejectQuote is a function for the customer to reject the pending quote of an order
+//
+//	@Summary		Reject the quote of an order
+//	@Description	Reject the pending quote with a reason, sending the order back to quote_received for a new quote. Only the customer who placed the order and members of its organization can do this
+//	@Tags			quotes
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string						true	"Order ID"
+//	@Param			request	body		model.QuoteRejectRequest	true	"Rejection reason"
+//	@Success		200		{object}	model.ResponseHTTP{data=model.OrderResponse}
+//	@Failure		400		{object}	model.ResponseHTTP{}
+//	@Failure		404		{object}	model.ResponseHTTP{}
+//	@Failure		500		{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/orders/{id}/quote/reject [post]
+func (h *OrderHandler) RejectQuote(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var payload model.QuoteRejectRequest
+
+	if err := c.BodyParser(&payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Invalid request",
+			Data:    nil,
+		})
+	}
+
+	if err := h.validator.Validate(payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	order, err := h.orderService.RejectQuote(id, auth.ActorFromContext(c), &payload)
+	if err != nil {
+		return h.quoteError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully rejected quote",
+		Data:    *order,
+	})
+}
+
+// CancelOrder is a function for the customer to cancel an order
+//
+//	@Summary		Cancel an order
+//	@Description	Cancel an order with a reason. Orders can be cancelled until the shoot starts. Only the customer who placed the order and members of its organization can do this
+//	@Tags			orders
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string						true	"Order ID"
+//	@Param			request	body		model.OrderCancelRequest	true	"Cancellation reason"
+//	@Success		200		{object}	model.ResponseHTTP{data=model.OrderResponse}
+//	@Failure		400		{object}	model.ResponseHTTP{}
+//	@Failure		404		{object}	model.ResponseHTTP{}
+//	@Failure		409		{object}	model.ResponseHTTP{}
+//	@Failure		500		{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/orders/{id}/cancel [post]
+func (h *OrderHandler) CancelOrder(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var payload model.OrderCancelRequest
+
+	if err := c.BodyParser(&payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Invalid request",
+			Data:    nil,
+		})
+	}
+
+	if err := h.validator.Validate(payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	order, err := h.orderService.CancelOrder(id, auth.ActorFromContext(c), &payload)
+	if err != nil {
+		return h.orderError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully cancelled order",
+		Data:    *order,
+	})
+}
+
+// DeleteOrder is a function to hide an order from customers and staff
+//
+//	@Summary		Delete an order (strictly for admin)
+//	@Description	Soft delete an order that is not in progress. Deleted orders can be listed with status=deleted and restored
+//	@Tags			orders
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"Order ID"
+//	@Success		200	{object}	model.ResponseHTTP{}
+//	@Failure		400	{object}	model.ResponseHTTP{}
+//	@Failure		404	{object}	model.ResponseHTTP{}
+//	@Failure		500	{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/orders/{id} [delete]
+func (h *OrderHandler) DeleteOrder(c *fiber.Ctx) error {
+	if err := h.orderService.DeleteOrder(c.Params("id")); err != nil {
+		return h.orderError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully deleted order",
+		Data:    nil,
+	})
+}
+
+// RestoreOrder is a function to bring back a deleted order
+//
+//	@Summary		Restore a deleted order (strictly for admin)
+//	@Description	Restore a soft deleted order in the status it was deleted in
+//	@Tags			orders
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"Order ID"
+//	@Success		200	{object}	model.ResponseHTTP{data=model.OrderResponse}
+//	@Failure		404	{object}	model.ResponseHTTP{}
+//	@Failure		500	{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/orders/{id}/restore [post]
+func (h *OrderHandler) RestoreOrder(c *fiber.Ctx) error {
+	order, err := h.orderService.RestoreOrder(c.Params("id"))
+	if err != nil {
+		return h.orderError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully restored order",
+		Data:    *order,
+	})
+}
+
+// UpdateOrder is a function for the customer to change an order before it is quoted
+//
+//	@Summary		Update an order
+//	@Description	Change the quantity, shots, finish type or details of an order that was not quoted yet. Only the fields sent are changed. Only the customer who placed the order and members of its organization can do this
+//	@Tags			orders
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string						true	"Order ID"
+//	@Param			request	body		model.OrderUpdateRequest	true	"Order changes"
+//	@Success		200		{object}	model.ResponseHTTP{data=model.OrderResponse}
+//	@Failure		400		{object}	model.ResponseHTTP{}
+//	@Failure		404		{object}	model.ResponseHTTP{}
+//	@Failure		409		{object}	model.ResponseHTTP{}
+//	@Failure		500		{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/orders/{id} [patch]
+func (h *OrderHandler) UpdateOrder(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var payload model.OrderUpdateRequest
+
+	if err := c.BodyParser(&payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Invalid request",
+			Data:    nil,
+		})
+	}
+
+	if err := h.validator.Validate(payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	order, err := h.orderService.UpdateOrder(id, auth.ActorFromContext(c), &payload)
+	if err != nil {
+		return h.orderError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully updated order",
+		Data:    *order,
+	})
+}
+
+// GetOrderRevisions is a function to get the changes made to an order
+//
+//	@Summary		Get order revisions
+//	@Description	Get the changes made to an order before it was quoted, oldest first
+//	@Tags			orders
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"Order ID"
+//	@Success		200	{object}	model.ResponseHTTP{data=[]model.OrderRevision}
+//	@Failure		404	{object}	model.ResponseHTTP{}
+//	@Failure		500	{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/orders/{id}/revisions [get]
+func (h *OrderHandler) GetOrderRevisions(c *fiber.Ctx) error {
+	revisions, err := h.orderService.GetOrderRevisions(c.Params("id"))
+	if err != nil {
+		return h.orderError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully retrieved order revisions",
+		Data:    revisions,
+	})
+}
+
+func (h *OrderHandler) orderError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, service.ErrOrderNotCancellable),
+		errors.Is(err, service.ErrOrderInProgress),
+		errors.Is(err, service.ErrOrderNotEditable),
+		errors.Is(err, service.ErrUnknownCatalogOption):
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	case errors.Is(err, service.ErrPlanShotsExhausted):
+		return c.Status(fiber.StatusConflict).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	default:
+		return h.quoteError(c, err)
+	}
+}
+
+func (*OrderHandler) quoteError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Order not found",
+			Data:    nil,
+		})
+	case errors.Is(err, service.ErrInvalidStatusTransition),
+		errors.Is(err, service.ErrNoPendingQuote),
+		errors.Is(err, service.ErrMissingPrice):
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	case strings.Contains(err.Error(), "order status was changed concurrently"):
+		return c.Status(fiber.StatusConflict).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Order status was changed by another request, please retry",
+			Data:    nil,
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Internal server error",
+			Data:    nil,
+		})
+	}
+}
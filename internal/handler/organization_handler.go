@@ -0,0 +1,480 @@
+package handler
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/MogboPython/belvaphilips_backend/internal/auth"
+	"github.com/MogboPython/belvaphilips_backend/internal/service"
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/MogboPython/belvaphilips_backend/pkg/validator"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type OrganizationHandler struct {
+	organizationService service.OrganizationService
+	validator           *validator.Validator
+}
+
+func NewOrganizationHandler(organizationService service.OrganizationService) *OrganizationHandler {
+	return &OrganizationHandler{
+		organizationService: organizationService,
+		validator:           validator.New(),
+	}
+}
+
+// CreateOrganization is a function to create a company account
+//
+//	@Summary		Create an organization
+//	@Description	Create a company account. The user who creates it becomes its first owner
+//	@Tags			organizations
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		model.OrganizationRequest	true	"Organization details"
+//	@Success		201		{object}	model.ResponseHTTP{data=model.OrganizationResponse}
+//	@Failure		400		{object}	model.ResponseHTTP{}
+//	@Failure		404		{object}	model.ResponseHTTP{}
+//	@Failure		500		{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/organizations [post]
+func (h *OrganizationHandler) CreateOrganization(c *fiber.Ctx) error {
+	var payload model.OrganizationRequest
+
+	if err := c.BodyParser(&payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Invalid request",
+			Data:    nil,
+		})
+	}
+
+	if err := h.validator.Validate(payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	organization, err := h.organizationService.CreateOrganization(auth.ActorFromContext(c), &payload)
+	if err != nil {
+		return h.organizationError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully created organization",
+		Data:    *organization,
+	})
+}
+
+// GetOrganizations is a function to list the organizations of the caller
+//
+//	@Summary		Get my organizations
+//	@Description	Get the organizations the caller is a member of and the role they hold in each
+//	@Tags			organizations
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	model.ResponseHTTP{data=[]model.OrganizationResponse}
+//	@Failure		500	{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/organizations [get]
+func (h *OrganizationHandler) GetOrganizations(c *fiber.Ctx) error {
+	organizations, err := h.organizationService.GetOrganizations(auth.ActorFromContext(c))
+	if err != nil {
+		return h.organizationError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully retrieved organizations",
+		Data:    organizations,
+	})
+}
+
+// GetOrganization is a function to get an organization by ID
+//
+//	@Summary		Get organization by ID
+//	@Description	Get an organization the caller is a member of
+//	@Tags			organizations
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"Organization ID"
+//	@Success		200	{object}	model.ResponseHTTP{data=model.OrganizationResponse}
+//	@Failure		403	{object}	model.ResponseHTTP{}
+//	@Failure		404	{object}	model.ResponseHTTP{}
+//	@Failure		500	{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/organizations/{id} [get]
+func (h *OrganizationHandler) GetOrganization(c *fiber.Ctx) error {
+	organization, err := h.organizationService.GetOrganization(c.Params("id"), auth.ActorFromContext(c))
+	if err != nil {
+		return h.organizationError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully found organization",
+		Data:    *organization,
+	})
+}
+
+// UpdateOrganization is a function to rename an organization
+//
+//	@Summary		Update organization
+//	@Description	Rename an organization. Only owners can do this
+//	@Tags			organizations
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string						true	"Organization ID"
+//	@Param			request	body		model.OrganizationRequest	true	"Organization details"
+//	@Success		200		{object}	model.ResponseHTTP{data=model.OrganizationResponse}
+//	@Failure		400		{object}	model.ResponseHTTP{}
+//	@Failure		403		{object}	model.ResponseHTTP{}
+//	@Failure		404		{object}	model.ResponseHTTP{}
+//	@Failure		500		{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/organizations/{id} [put]
+func (h *OrganizationHandler) UpdateOrganization(c *fiber.Ctx) error {
+	var payload model.OrganizationRequest
+
+	if err := c.BodyParser(&payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Invalid request",
+			Data:    nil,
+		})
+	}
+
+	if err := h.validator.Validate(payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	organization, err := h.organizationService.UpdateOrganization(c.Params("id"), auth.ActorFromContext(c), &payload)
+	if err != nil {
+		return h.organizationError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully updated organization",
+		Data:    *organization,
+	})
+}
+
+// GetMembers is a function to list the members of an organization
+//
+//	@Summary		Get organization members
+//	@Description	Get the members of an organization and their roles
+//	@Tags			organizations
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"Organization ID"
+//	@Success		200	{object}	model.ResponseHTTP{data=[]model.OrganizationMemberResponse}
+//	@Failure		403	{object}	model.ResponseHTTP{}
+//	@Failure		500	{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/organizations/{id}/members [get]
+func (h *OrganizationHandler) GetMembers(c *fiber.Ctx) error {
+	members, err := h.organizationService.GetMembers(c.Params("id"))
+	if err != nil {
+		return h.organizationError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully retrieved members",
+		Data:    members,
+	})
+}
+
+// UpdateMemberRole is a function to change the role of a member
+//
+//	@Summary		Change member role
+//	@Description	Make a member an owner or a regular member. Only owners can do this and an organization always keeps at least one owner
+//	@Tags			organizations
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string								true	"Organization ID"
+//	@Param			user_id	path		string								true	"User ID of the member"
+//	@Param			request	body		model.OrganizationMemberRoleRequest	true	"New role"
+//	@Success		200		{object}	model.ResponseHTTP{}
+//	@Failure		400		{object}	model.ResponseHTTP{}
+//	@Failure		403		{object}	model.ResponseHTTP{}
+//	@Failure		404		{object}	model.ResponseHTTP{}
+//	@Failure		500		{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/organizations/{id}/members/{user_id} [put]
+func (h *OrganizationHandler) UpdateMemberRole(c *fiber.Ctx) error {
+	var payload model.OrganizationMemberRoleRequest
+
+	if err := c.BodyParser(&payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Invalid request",
+			Data:    nil,
+		})
+	}
+
+	if err := h.validator.Validate(payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	if err := h.organizationService.UpdateMemberRole(c.Params("id"), c.Params("user_id"), auth.ActorFromContext(c), &payload); err != nil {
+		return h.organizationError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully updated member",
+		Data:    nil,
+	})
+}
+
+// RemoveMember is a function to remove a member from an organization
+//
+//	@Summary		Remove member
+//	@Description	Remove a member from an organization. Owners can remove anyone and members can leave on their own. Orders the member placed stay with the organization
+//	@Tags			organizations
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string	true	"Organization ID"
+//	@Param			user_id	path		string	true	"User ID of the member"
+//	@Success		200		{object}	model.ResponseHTTP{}
+//	@Failure		400		{object}	model.ResponseHTTP{}
+//	@Failure		403		{object}	model.ResponseHTTP{}
+//	@Failure		404		{object}	model.ResponseHTTP{}
+//	@Failure		500		{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/organizations/{id}/members/{user_id} [delete]
+func (h *OrganizationHandler) RemoveMember(c *fiber.Ctx) error {
+	if err := h.organizationService.RemoveMember(c.Params("id"), c.Params("user_id"), auth.ActorFromContext(c)); err != nil {
+		return h.organizationError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully removed member",
+		Data:    nil,
+	})
+}
+
+// InviteMember is a function to invite someone to an organization
+//
+//	@Summary		Invite member
+//	@Description	Email an invitation code to join an organization. Only owners can do this and invitations expire after seven days
+//	@Tags			organizations
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string							true	"Organization ID"
+//	@Param			request	body		model.OrganizationInviteRequest	true	"Invitation"
+//	@Success		201		{object}	model.ResponseHTTP{data=model.OrganizationInvitationResponse}
+//	@Failure		400		{object}	model.ResponseHTTP{}
+//	@Failure		403		{object}	model.ResponseHTTP{}
+//	@Failure		404		{object}	model.ResponseHTTP{}
+//	@Failure		500		{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/organizations/{id}/invitations [post]
+func (h *OrganizationHandler) InviteMember(c *fiber.Ctx) error {
+	var payload model.OrganizationInviteRequest
+
+	if err := c.BodyParser(&payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Invalid request",
+			Data:    nil,
+		})
+	}
+
+	if err := h.validator.Validate(payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	invitation, err := h.organizationService.InviteMember(c.Params("id"), auth.ActorFromContext(c), &payload)
+	if err != nil {
+		return h.organizationError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully sent invitation",
+		Data:    *invitation,
+	})
+}
+
+// GetInvitations is a function to list the pending invitations of an organization
+//
+//	@Summary		Get pending invitations
+//	@Description	Get the invitations of an organization that were not accepted and have not expired. Only owners can do this
+//	@Tags			organizations
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"Organization ID"
+//	@Success		200	{object}	model.ResponseHTTP{data=[]model.OrganizationInvitationResponse}
+//	@Failure		403	{object}	model.ResponseHTTP{}
+//	@Failure		500	{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/organizations/{id}/invitations [get]
+func (h *OrganizationHandler) GetInvitations(c *fiber.Ctx) error {
+	invitations, err := h.organizationService.GetInvitations(c.Params("id"), auth.ActorFromContext(c))
+	if err != nil {
+		return h.organizationError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully retrieved invitations",
+		Data:    invitations,
+	})
+}
+
+// RevokeInvitation is a function to cancel a pending invitation
+//
+//	@Summary		Revoke invitation
+//	@Description	Cancel an invitation that was not accepted yet. Only owners can do this
+//	@Tags			organizations
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id				path		string	true	"Organization ID"
+//	@Param			invitation_id	path		string	true	"Invitation ID"
+//	@Success		200				{object}	model.ResponseHTTP{}
+//	@Failure		403				{object}	model.ResponseHTTP{}
+//	@Failure		404				{object}	model.ResponseHTTP{}
+//	@Failure		500				{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/organizations/{id}/invitations/{invitation_id} [delete]
+func (h *OrganizationHandler) RevokeInvitation(c *fiber.Ctx) error {
+	if err := h.organizationService.RevokeInvitation(c.Params("id"), c.Params("invitation_id"), auth.ActorFromContext(c)); err != nil {
+		return h.organizationError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully revoked invitation",
+		Data:    nil,
+	})
+}
+
+// AcceptInvitation is a function to join an organization with an invitation code
+//
+//	@Summary		Accept invitation
+//	@Description	Join the organization of an invitation that was sent to the email address of the caller
+//	@Tags			organizations
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		model.AcceptInvitationRequest	true	"Invitation code"
+//	@Success		200		{object}	model.ResponseHTTP{data=model.OrganizationResponse}
+//	@Failure		400		{object}	model.ResponseHTTP{}
+//	@Failure		403		{object}	model.ResponseHTTP{}
+//	@Failure		409		{object}	model.ResponseHTTP{}
+//	@Failure		500		{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/organizations/invitations/accept [post]
+func (h *OrganizationHandler) AcceptInvitation(c *fiber.Ctx) error {
+	var payload model.AcceptInvitationRequest
+
+	if err := c.BodyParser(&payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Invalid request",
+			Data:    nil,
+		})
+	}
+
+	if err := h.validator.Validate(payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	organization, err := h.organizationService.AcceptInvitation(auth.ActorFromContext(c), &payload)
+	if err != nil {
+		return h.organizationError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully joined organization",
+		Data:    *organization,
+	})
+}
+
+func (*OrganizationHandler) organizationError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Not found",
+			Data:    nil,
+		})
+	case errors.Is(err, service.ErrNotOrganizationMember),
+		errors.Is(err, service.ErrNotOrganizationOwner),
+		errors.Is(err, service.ErrInvitationEmailMismatch):
+		return c.Status(fiber.StatusForbidden).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	case errors.Is(err, service.ErrAlreadyOrganizationMember):
+		return c.Status(fiber.StatusConflict).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	case errors.Is(err, service.ErrInvalidInvitation):
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	case strings.Contains(err.Error(), "without an owner"):
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "An organization needs at least one owner",
+			Data:    nil,
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Internal server error",
+			Data:    nil,
+		})
+	}
+}
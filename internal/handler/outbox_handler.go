@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/MogboPython/belvaphilips_backend/internal/service"
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type OutboxHandler struct {
+	outboxService service.OutboxService
+}
+
+func NewOutboxHandler(outboxService service.OutboxService) *OutboxHandler {
+	return &OutboxHandler{
+		outboxService: outboxService,
+	}
+}
+
+// GetEmails is a function to list queued, sent and failed emails
+//
+//	@Summary		Get outbox emails (strictly for admin)
+//	@Description	Fetch a paginated list of emails from the outbox, newest first
+//	@Tags			admin
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			page	query		int		false	"Page number (default is 1)"
+//	@Param			limit	query		int		false	"Number of emails per page (default is 10)"
+//	@Param			status	query		string	false	"Email status (pending, sent or dead)"
+//	@Success		200		{object}	model.ResponseHTTP{data=model.TotalEmailOutboxResponse}
+//	@Failure		500		{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/admin/emails [get]
+func (h *OutboxHandler) GetEmails(c *fiber.Ctx) error {
+	pageStr := c.Query("page", "1")
+	limitStr := c.Query("limit", "10")
+	status := c.Query("status", "")
+
+	emails, err := h.outboxService.GetEmails(pageStr, limitStr, status)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Internal server error",
+			Data:    nil,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully retrieved emails",
+		Data:    emails,
+	})
+}
+
+// ResendEmail is a function to queue a failed email for delivery again
+//
+//	@Summary		Resend a failed email (strictly for admin)
+//	@Description	Move a dead-lettered email back to the outbox with a fresh set of attempts. Messages that held a password or invitation link cannot be resent
+//	@Tags			admin
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"Email ID"
+//	@Success		200	{object}	model.ResponseHTTP{data=model.EmailOutboxResponse}
+//	@Failure		400	{object}	model.ResponseHTTP{}
+//	@Failure		404	{object}	model.ResponseHTTP{}
+//	@Failure		500	{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/admin/emails/{id}/resend [post]
+func (h *OutboxHandler) ResendEmail(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	email, err := h.outboxService.ResendEmail(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(model.ResponseHTTP{
+				Success: false,
+				Message: "Email not found",
+				Data:    nil,
+			})
+		}
+
+		if strings.Contains(err.Error(), "only failed emails can be resent") {
+			return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+				Success: false,
+				Message: "Only failed emails can be resent",
+				Data:    nil,
+			})
+		}
+
+		if strings.Contains(err.Error(), "message content was removed") {
+			return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+				Success: false,
+				Message: "This message held a password or invitation link and cannot be resent, issue a new one instead",
+				Data:    nil,
+			})
+		}
+
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Internal server error",
+			Data:    nil,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Email queued for delivery",
+		Data:    *email,
+	})
+}
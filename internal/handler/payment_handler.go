@@ -0,0 +1,236 @@
+package handler
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/MogboPython/belvaphilips_backend/internal/payment"
+	"github.com/MogboPython/belvaphilips_backend/internal/service"
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/MogboPython/belvaphilips_backend/pkg/validator"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+)
+
+type PaymentHandler struct {
+	paymentService service.PaymentService
+	validator      *validator.Validator
+}
+
+func NewPaymentHandler(paymentService service.PaymentService) *PaymentHandler {
+	return &PaymentHandler{
+		paymentService: paymentService,
+		validator:      validator.New(),
+	}
+}
+
+// PayInvoice is a function to start paying an invoice
+//
+//	@Summary		Pay an invoice
+//	@Description	Open a checkout session with the payment provider for the total of an issued invoice. While a checkout is open it is returned instead of opening another
+//	@Tags			payments
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"Invoice ID"
+//	@Success		201	{object}	model.ResponseHTTP{data=model.PaymentResponse}
+//	@Failure		400	{object}	model.ResponseHTTP{}
+//	@Failure		404	{object}	model.ResponseHTTP{}
+//	@Failure		409	{object}	model.ResponseHTTP{}
+//	@Failure		500	{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/invoices/{id}/pay [post]
+func (h *PaymentHandler) PayInvoice(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	p, err := h.paymentService.PayInvoice(c.UserContext(), id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(model.ResponseHTTP{
+				Success: false,
+				Message: "Invoice not found",
+				Data:    nil,
+			})
+		}
+
+		return h.paymentError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully created checkout",
+		Data:    *p,
+	})
+}
+
+// GetInvoicePayments is a function to list the payments made against an invoice
+//
+//	@Summary		Get the payments of an invoice
+//	@Description	List every payment attempt for an invoice, newest first
+//	@Tags			payments
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"Invoice ID"
+//	@Success		200	{object}	model.ResponseHTTP{data=[]model.PaymentResponse}
+//	@Failure		500	{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/invoices/{id}/payments [get]
+func (h *PaymentHandler) GetInvoicePayments(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	payments, err := h.paymentService.GetInvoicePayments(id)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Internal server error",
+			Data:    nil,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully retrieved payments",
+		Data:    payments,
+	})
+}
+
+// RefundPayment is a function to refund a successful payment
+//
+//	@Summary		Refund a payment (strictly for admin)
+//	@Description	Refund part or all of what is left on a successful payment. An amount of 0 refunds everything left, which also voids the invoice
+//	@Tags			payments
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string						true	"Payment ID"
+//	@Param			request	body		model.PaymentRefundRequest	true	"Refund amount"
+//	@Success		200		{object}	model.ResponseHTTP{data=model.PaymentResponse}
+//	@Failure		400		{object}	model.ResponseHTTP{}
+//	@Failure		404		{object}	model.ResponseHTTP{}
+//	@Failure		409		{object}	model.ResponseHTTP{}
+//	@Failure		500		{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/admin/payments/{id}/refund [post]
+func (h *PaymentHandler) RefundPayment(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var payload model.PaymentRefundRequest
+
+	if err := c.BodyParser(&payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Invalid request",
+			Data:    nil,
+		})
+	}
+
+	if err := h.validator.Validate(payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	p, err := h.paymentService.RefundPayment(c.UserContext(), id, &payload)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(model.ResponseHTTP{
+				Success: false,
+				Message: "Payment not found",
+				Data:    nil,
+			})
+		}
+
+		return h.paymentError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully refunded payment",
+		Data:    *p,
+	})
+}
+
+// PaymentWebhook is a function to receive payment notifications from the payment provider
+//
+//	@Summary		Payment provider webhook
+//	@Description	Receive a signed notification from the payment provider. Successful payments mark the invoice paid and move the order to paid
+//	@Tags			payments
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	model.ResponseHTTP{}
+//	@Failure		401	{object}	model.ResponseHTTP{}
+//	@Failure		500	{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/payments/webhook [post]
+func (h *PaymentHandler) PaymentWebhook(c *fiber.Ctx) error {
+	signature := c.Get(h.paymentService.WebhookSignatureHeader())
+
+	if err := h.paymentService.HandleWebhook(c.Body(), signature); err != nil {
+		if errors.Is(err, payment.ErrInvalidSignature) {
+			return c.Status(fiber.StatusUnauthorized).JSON(model.ResponseHTTP{
+				Success: false,
+				Message: "Invalid signature",
+				Data:    nil,
+			})
+		}
+
+		log.Errorf("Failed to handle payment webhook: %v", err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Internal server error",
+			Data:    nil,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Webhook received",
+		Data:    nil,
+	})
+}
+
+func (*PaymentHandler) paymentError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, service.ErrInvoiceNotPayable),
+		errors.Is(err, service.ErrOrderCancelled),
+		errors.Is(err, service.ErrPaymentNotRefundable),
+		errors.Is(err, service.ErrRefundExceedsPayment):
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	case errors.Is(err, service.ErrPaymentInProgress),
+		strings.Contains(err.Error(), "invoice already has a pending payment"):
+		return c.Status(fiber.StatusConflict).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "A checkout for this invoice is already being opened, please retry",
+			Data:    nil,
+		})
+	case strings.Contains(err.Error(), "payment was refunded concurrently"):
+		return c.Status(fiber.StatusConflict).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Payment was refunded by another request, please retry",
+			Data:    nil,
+		})
+	case errors.Is(err, payment.ErrProviderNotConfigured):
+		return c.Status(fiber.StatusServiceUnavailable).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Payments are not available",
+			Data:    nil,
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Internal server error",
+			Data:    nil,
+		})
+	}
+}
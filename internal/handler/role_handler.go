@@ -0,0 +1,330 @@
+package handler
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/MogboPython/belvaphilips_backend/internal/auth"
+	"github.com/MogboPython/belvaphilips_backend/internal/service"
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/MogboPython/belvaphilips_backend/pkg/validator"
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+type RoleHandler struct {
+	roleService service.RoleService
+	validator   *validator.Validator
+}
+
+func NewRoleHandler(roleService service.RoleService) *RoleHandler {
+	return &RoleHandler{
+		roleService: roleService,
+		validator:   validator.New(),
+	}
+}
+
+// GetPermissions is a function to list the permissions roles can grant
+//
+//	@Summary		Get all permissions (strictly for admin)
+//	@Description	List every permission that can be granted to a role
+//	@Tags			roles
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	model.ResponseHTTP{data=[]model.Permission}
+//	@Failure		500	{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/admin/permissions [get]
+func (h *RoleHandler) GetPermissions(c *fiber.Ctx) error {
+	permissions, err := h.roleService.GetPermissions()
+	if err != nil {
+		return h.roleError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully retrieved permissions",
+		Data:    permissions,
+	})
+}
+
+// GetRoles is a function to list the staff roles
+//
+//	@Summary		Get all roles (strictly for admin)
+//	@Description	List every staff role with the permissions it grants
+//	@Tags			roles
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	model.ResponseHTTP{data=[]model.RoleResponse}
+//	@Failure		500	{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/admin/roles [get]
+func (h *RoleHandler) GetRoles(c *fiber.Ctx) error {
+	roles, err := h.roleService.GetRoles()
+	if err != nil {
+		return h.roleError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully retrieved roles",
+		Data:    roles,
+	})
+}
+
+// CreateRole is a function to create a staff role
+//
+//	@Summary		Create a role (strictly for admin)
+//	@Description	Create a staff role that grants the given permissions
+//	@Tags			roles
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		model.RoleRequest	true	"Role"
+//	@Success		201		{object}	model.ResponseHTTP{data=model.RoleResponse}
+//	@Failure		400		{object}	model.ResponseHTTP{}
+//	@Failure		409		{object}	model.ResponseHTTP{}
+//	@Failure		500		{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/admin/roles [post]
+func (h *RoleHandler) CreateRole(c *fiber.Ctx) error {
+	var payload model.RoleRequest
+
+	if err := c.BodyParser(&payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Invalid request",
+			Data:    nil,
+		})
+	}
+
+	if err := h.validator.Validate(payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	role, err := h.roleService.CreateRole(&payload)
+	if err != nil {
+		return h.roleError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully created role",
+		Data:    *role,
+	})
+}
+
+// UpdateRole is a function to change a staff role
+//
+//	@Summary		Update a role (strictly for admin)
+//	@Description	Rename a staff role and replace the permissions it grants. The owner role cannot be changed
+//	@Tags			roles
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string				true	"Role ID"
+//	@Param			request	body		model.RoleRequest	true	"Role"
+//	@Success		200		{object}	model.ResponseHTTP{data=model.RoleResponse}
+//	@Failure		400		{object}	model.ResponseHTTP{}
+//	@Failure		404		{object}	model.ResponseHTTP{}
+//	@Failure		409		{object}	model.ResponseHTTP{}
+//	@Failure		500		{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/admin/roles/{id} [put]
+func (h *RoleHandler) UpdateRole(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var payload model.RoleRequest
+
+	if err := c.BodyParser(&payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Invalid request",
+			Data:    nil,
+		})
+	}
+
+	if err := h.validator.Validate(payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	role, err := h.roleService.UpdateRole(id, &payload)
+	if err != nil {
+		return h.roleError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully updated role",
+		Data:    *role,
+	})
+}
+
+// DeleteRole is a function to delete a staff role
+//
+//	@Summary		Delete a role (strictly for admin)
+//	@Description	Delete a staff role and remove it from every admin. The owner role cannot be deleted
+//	@Tags			roles
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"Role ID"
+//	@Success		200	{object}	model.ResponseHTTP{}
+//	@Failure		400	{object}	model.ResponseHTTP{}
+//	@Failure		404	{object}	model.ResponseHTTP{}
+//	@Failure		409	{object}	model.ResponseHTTP{}
+//	@Failure		500	{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/admin/roles/{id} [delete]
+func (h *RoleHandler) DeleteRole(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	if err := h.roleService.DeleteRole(id); err != nil {
+		return h.roleError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully deleted role",
+		Data:    nil,
+	})
+}
+
+// GetAdminRoles is a function to list the roles of a staff account
+//
+//	@Summary		Get the roles of a staff member (strictly for admin)
+//	@Description	List the roles assigned to an admin account
+//	@Tags			roles
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"Admin ID"
+//	@Success		200	{object}	model.ResponseHTTP{data=[]model.RoleResponse}
+//	@Failure		404	{object}	model.ResponseHTTP{}
+//	@Failure		500	{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/admin/staff/{id}/roles [get]
+func (h *RoleHandler) GetAdminRoles(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	roles, err := h.roleService.GetAdminRoles(id)
+	if err != nil {
+		return h.roleError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully retrieved staff roles",
+		Data:    roles,
+	})
+}
+
+// SetAdminRoles is a function to assign roles to a staff account
+//
+//	@Summary		Set the roles of a staff member (strictly for admin)
+//	@Description	Replace the roles assigned to an admin account. Admins cannot remove their own staff:manage permission
+//	@Tags			roles
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string					true	"Admin ID"
+//	@Param			request	body		model.AdminRolesRequest	true	"Roles"
+//	@Success		200		{object}	model.ResponseHTTP{data=[]model.RoleResponse}
+//	@Failure		400		{object}	model.ResponseHTTP{}
+//	@Failure		404		{object}	model.ResponseHTTP{}
+//	@Failure		409		{object}	model.ResponseHTTP{}
+//	@Failure		500		{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/admin/staff/{id}/roles [put]
+func (h *RoleHandler) SetAdminRoles(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var payload model.AdminRolesRequest
+
+	if err := c.BodyParser(&payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Invalid request",
+			Data:    nil,
+		})
+	}
+
+	if err := h.validator.Validate(payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	roles, err := h.roleService.SetAdminRoles(id, auth.ActorFromContext(c), &payload)
+	if err != nil {
+		return h.roleError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully updated staff roles",
+		Data:    roles,
+	})
+}
+
+func (*RoleHandler) roleError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Role or admin not found",
+			Data:    nil,
+		})
+	case errors.Is(err, service.ErrUnknownPermission),
+		errors.Is(err, service.ErrOwnerRoleReadOnly),
+		errors.Is(err, service.ErrCannotRevokeOwnStaffAccess):
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	case strings.Contains(err.Error(), "role not found"):
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "One of the roles does not exist",
+			Data:    nil,
+		})
+	case strings.Contains(err.Error(), "role already exists"):
+		return c.Status(fiber.StatusConflict).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "A role with this name already exists",
+			Data:    nil,
+		})
+	case strings.Contains(err.Error(), "no active admin would be left to manage staff"):
+		return c.Status(fiber.StatusConflict).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "At least one active admin must keep the staff:manage permission",
+			Data:    nil,
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Internal server error",
+			Data:    nil,
+		})
+	}
+}
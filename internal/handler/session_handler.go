@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/MogboPython/belvaphilips_backend/internal/service"
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/MogboPython/belvaphilips_backend/pkg/validator"
+	"github.com/gofiber/fiber/v2"
+)
+
+type SessionHandler struct {
+	sessionService service.SessionService
+	validator      *validator.Validator
+}
+
+func NewSessionHandler(sessionService service.SessionService) *SessionHandler {
+	return &SessionHandler{
+		sessionService: sessionService,
+		validator:      validator.New(),
+	}
+}
+
+// RefreshSession is a function to renew an access token
+//
+//	@Summary		Refresh an access token
+//	@Description	Trade a refresh token for a new access token and a new refresh token. Each refresh token works once
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		model.RefreshTokenRequest	true	"Refresh token"
+//	@Success		200		{object}	model.ResponseHTTP{data=model.TokenResponse}
+//	@Failure		400		{object}	model.ResponseHTTP{}
+//	@Failure		401		{object}	model.ResponseHTTP{}
+//	@Failure		500		{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/auth/refresh [post]
+func (h *SessionHandler) RefreshSession(c *fiber.Ctx) error {
+	var payload model.RefreshTokenRequest
+
+	if err := c.BodyParser(&payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Invalid request",
+			Data:    nil,
+		})
+	}
+
+	if err := h.validator.Validate(payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	tokens, err := h.sessionService.Refresh(payload.RefreshToken)
+	if err != nil {
+		return h.sessionError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully refreshed access token",
+		Data:    *tokens,
+	})
+}
+
+// Logout is a function to end a session
+//
+//	@Summary		Log out
+//	@Description	Revoke the session of a refresh token, including its current access token
+//	@Tags			auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		model.RefreshTokenRequest	true	"Refresh token"
+//	@Success		200		{object}	model.ResponseHTTP{}
+//	@Failure		400		{object}	model.ResponseHTTP{}
+//	@Failure		401		{object}	model.ResponseHTTP{}
+//	@Failure		500		{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/auth/logout [post]
+func (h *SessionHandler) Logout(c *fiber.Ctx) error {
+	var payload model.RefreshTokenRequest
+
+	if err := c.BodyParser(&payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Invalid request",
+			Data:    nil,
+		})
+	}
+
+	if err := h.validator.Validate(payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	if err := h.sessionService.Logout(payload.RefreshToken); err != nil {
+		return h.sessionError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully logged out",
+		Data:    nil,
+	})
+}
+
+// RevokeSessions is a function to sign a user or staff member out of every device
+//
+//	@Summary		Revoke all sessions (strictly for admin)
+//	@Description	Revoke every refresh token of a user or admin and the access tokens issued with them
+//	@Tags			auth
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"User or admin ID"
+//	@Success		200	{object}	model.ResponseHTTP{}
+//	@Failure		500	{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/admin/users/{id}/sessions [delete]
+//	@Router			/api/v1/admin/staff/{id}/sessions [delete]
+func (h *SessionHandler) RevokeSessions(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	if err := h.sessionService.RevokeSessions(id); err != nil {
+		return h.sessionError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully revoked sessions",
+		Data:    nil,
+	})
+}
+
+func (*SessionHandler) sessionError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, service.ErrInvalidRefreshToken) {
+		return c.Status(fiber.StatusUnauthorized).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseHTTP{
+		Success: false,
+		Message: "Internal server error",
+		Data:    nil,
+	})
+}
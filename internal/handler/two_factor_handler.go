@@ -0,0 +1,252 @@
+package handler
+
+import (
+	"github.com/MogboPython/belvaphilips_backend/internal/auth"
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/gofiber/fiber/v2"
+)
+
+// AdminTwoFactorLogin is a handler for finishing an admin sign in with a two-factor code
+//
+//	@Summary		Finish admin sign in with a two-factor code
+//	@Description	Exchange the challenge token from /admin/login and a TOTP or recovery code for access tokens
+//	@Tags			admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		model.AdminTwoFactorLoginRequest	true	"Challenge and code"
+//	@Success		201		{object}	model.ResponseHTTP{data=model.AdminLoginResponse}
+//	@Failure		400		{object}	model.ResponseHTTP{}
+//	@Failure		401		{object}	model.ResponseHTTP{}
+//	@Failure		403		{object}	model.ResponseHTTP{}
+//	@Failure		429		{object}	model.ResponseHTTP{}
+//	@Failure		500		{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/admin/login/2fa [post]
+func (h *AdminHandler) AdminTwoFactorLogin(c *fiber.Ctx) error {
+	var payload model.AdminTwoFactorLoginRequest
+
+	if err := c.BodyParser(&payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Invalid request",
+			Data:    nil,
+		})
+	}
+
+	if err := h.validator.Validate(payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	login, err := h.adminService.VerifyTwoFactorLogin(&payload)
+	if err != nil {
+		return h.adminError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Success get access token",
+		Data:    *login,
+	})
+}
+
+// SetupTwoFactor is a function to start two-factor enrollment for the signed in admin
+//
+//	@Summary		Start two-factor enrollment (strictly for admin)
+//	@Description	Generate a TOTP secret and the otpauth:// URI to show as a QR code. Two-factor sign in stays off until it is enabled with a code
+//	@Tags			admin
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	model.ResponseHTTP{data=model.TwoFactorSetupResponse}
+//	@Failure		400	{object}	model.ResponseHTTP{}
+//	@Failure		500	{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/admin/me/2fa/setup [post]
+func (h *AdminHandler) SetupTwoFactor(c *fiber.Ctx) error {
+	setup, err := h.adminService.SetupTwoFactor(auth.ActorFromContext(c))
+	if err != nil {
+		return h.adminError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Scan the code with an authenticator app and confirm it to enable two-factor sign in",
+		Data:    *setup,
+	})
+}
+
+// EnableTwoFactor is a function to turn on two-factor sign in for the signed in admin
+//
+//	@Summary		Enable two-factor sign in (strictly for admin)
+//	@Description	Confirm the authenticator app with a code. The response holds recovery codes that are only shown once
+//	@Tags			admin
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		model.TwoFactorCodeRequest	true	"Code from the authenticator app"
+//	@Success		200		{object}	model.ResponseHTTP{data=model.RecoveryCodesResponse}
+//	@Failure		400		{object}	model.ResponseHTTP{}
+//	@Failure		401		{object}	model.ResponseHTTP{}
+//	@Failure		500		{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/admin/me/2fa/enable [post]
+func (h *AdminHandler) EnableTwoFactor(c *fiber.Ctx) error {
+	var payload model.TwoFactorCodeRequest
+
+	if err := c.BodyParser(&payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Invalid request",
+			Data:    nil,
+		})
+	}
+
+	if err := h.validator.Validate(payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	codes, err := h.adminService.EnableTwoFactor(auth.ActorFromContext(c), &payload)
+	if err != nil {
+		return h.adminError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully enabled two-factor sign in",
+		Data:    *codes,
+	})
+}
+
+// DisableTwoFactor is a function to turn off two-factor sign in for the signed in admin
+//
+//	@Summary		Disable two-factor sign in (strictly for admin)
+//	@Description	Turn off two-factor sign in after confirming the password and a TOTP or recovery code. Every other session of the admin is signed out
+//	@Tags			admin
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		model.TwoFactorDisableRequest	true	"Password and code"
+//	@Success		200		{object}	model.ResponseHTTP{}
+//	@Failure		400		{object}	model.ResponseHTTP{}
+//	@Failure		401		{object}	model.ResponseHTTP{}
+//	@Failure		429		{object}	model.ResponseHTTP{}
+//	@Failure		500		{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/admin/me/2fa/disable [post]
+func (h *AdminHandler) DisableTwoFactor(c *fiber.Ctx) error {
+	var payload model.TwoFactorDisableRequest
+
+	if err := c.BodyParser(&payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Invalid request",
+			Data:    nil,
+		})
+	}
+
+	if err := h.validator.Validate(payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	if err := h.adminService.DisableTwoFactor(auth.ActorFromContext(c), &payload); err != nil {
+		return h.adminError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully disabled two-factor sign in",
+		Data:    nil,
+	})
+}
+
+// RegenerateRecoveryCodes is a function to replace the recovery codes of the signed in admin
+//
+//	@Summary		Regenerate recovery codes (strictly for admin)
+//	@Description	Replace every recovery code with a new set after confirming a TOTP or recovery code
+//	@Tags			admin
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		model.TwoFactorCodeRequest	true	"Code from the authenticator app"
+//	@Success		200		{object}	model.ResponseHTTP{data=model.RecoveryCodesResponse}
+//	@Failure		400		{object}	model.ResponseHTTP{}
+//	@Failure		401		{object}	model.ResponseHTTP{}
+//	@Failure		429		{object}	model.ResponseHTTP{}
+//	@Failure		500		{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/admin/me/2fa/recovery-codes [post]
+func (h *AdminHandler) RegenerateRecoveryCodes(c *fiber.Ctx) error {
+	var payload model.TwoFactorCodeRequest
+
+	if err := c.BodyParser(&payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Invalid request",
+			Data:    nil,
+		})
+	}
+
+	if err := h.validator.Validate(payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	}
+
+	codes, err := h.adminService.RegenerateRecoveryCodes(auth.ActorFromContext(c), &payload)
+	if err != nil {
+		return h.adminError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully regenerated recovery codes",
+		Data:    *codes,
+	})
+}
+
+// ResetTwoFactor is a function to turn off two-factor sign in for a staff account
+//
+//	@Summary		Reset two-factor sign in of a staff account (strictly for admin)
+//	@Description	Turn off two-factor sign in for a staff member who lost their authenticator and recovery codes. Their sessions are signed out
+//	@Tags			admin
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"Admin ID"
+//	@Success		200	{object}	model.ResponseHTTP{data=model.AdminResponse}
+//	@Failure		404	{object}	model.ResponseHTTP{}
+//	@Failure		500	{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/admin/staff/{id}/2fa/reset [post]
+func (h *AdminHandler) ResetTwoFactor(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	admin, err := h.adminService.ResetTwoFactor(id)
+	if err != nil {
+		return h.adminError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully reset two-factor sign in",
+		Data:    *admin,
+	})
+}
@@ -4,11 +4,12 @@ import (
 	"errors"
 	"strings"
 
+	"github.com/MogboPython/belvaphilips_backend/internal/auth"
 	"github.com/MogboPython/belvaphilips_backend/internal/service"
 	"github.com/MogboPython/belvaphilips_backend/pkg/model"
-	"github.com/MogboPython/belvaphilips_backend/pkg/utils"
 	"github.com/MogboPython/belvaphilips_backend/pkg/validator"
 	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
 	"gorm.io/gorm"
 )
 
@@ -24,6 +25,21 @@ func NewUserHandler(userService service.UserService) *UserHandler {
 	}
 }
 
+// CreateUserAccessToken exchanges an identity provider access token for an API token
+//
+//	@Summary		Get an access token
+//	@Description	Exchange a verified access token from the identity provider for a short-lived API token of the user in its subject and a refresh token
+//	@Tags			users
+//	@Accept			json
+//	@Produce		json
+//	@Param			request	body		model.TokenRequestPayload	true	"Identity provider access token"
+//	@Success		201		{object}	model.ResponseHTTP{data=model.TokenResponse}
+//	@Failure		400		{object}	model.ResponseHTTP{}
+//	@Failure		401		{object}	model.ResponseHTTP{}
+//	@Failure		403		{object}	model.ResponseHTTP{}
+//	@Failure		500		{object}	model.ResponseHTTP{}
+//	@Failure		503		{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/token [post]
 func (h *UserHandler) CreateUserAccessToken(c *fiber.Ctx) error {
 	var payload model.TokenRequestPayload
 
@@ -43,8 +59,35 @@ func (h *UserHandler) CreateUserAccessToken(c *fiber.Ctx) error {
 		})
 	}
 
-	token, err := utils.GenerateToken(payload.UserSessionID, "authenticated")
+	tokens, err := h.userService.CreateAccessToken(payload.AccessToken)
 	if err != nil {
+		if errors.Is(err, service.ErrUserDeleted) {
+			return c.Status(fiber.StatusForbidden).JSON(model.ResponseHTTP{
+				Success: false,
+				Message: "This account has been deleted",
+				Data:    nil,
+			})
+		}
+
+		if errors.Is(err, auth.ErrVerifierNotConfigured) {
+			return c.Status(fiber.StatusServiceUnavailable).JSON(model.ResponseHTTP{
+				Success: false,
+				Message: "Sign in is not available",
+				Data:    nil,
+			})
+		}
+
+		if errors.Is(err, jwt.ErrTokenMalformed) ||
+			errors.Is(err, jwt.ErrTokenUnverifiable) ||
+			errors.Is(err, jwt.ErrTokenSignatureInvalid) ||
+			errors.Is(err, jwt.ErrTokenInvalidClaims) {
+			return c.Status(fiber.StatusUnauthorized).JSON(model.ResponseHTTP{
+				Success: false,
+				Message: "Invalid or expired access token",
+				Data:    nil,
+			})
+		}
+
 		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseHTTP{
 			Success: false,
 			Message: "Error generating access token",
@@ -55,9 +98,7 @@ func (h *UserHandler) CreateUserAccessToken(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusCreated).JSON(model.ResponseHTTP{
 		Success: true,
 		Message: "Success get access token",
-		Data: map[string]string{
-			"access_token": token,
-		},
+		Data:    *tokens,
 	})
 }
 
@@ -74,6 +115,7 @@ func (h *UserHandler) CreateUserAccessToken(c *fiber.Ctx) error {
 //	@Param			request	body		model.CreateUserRequest	true	"User information"
 //	@Success		201		{object}	model.ResponseHTTP{data=model.UserResponse}
 //	@Failure		400		{object}	model.ResponseHTTP{}
+//	@Failure		403		{object}	model.ResponseHTTP{}
 //	@Failure		500		{object}	model.ResponseHTTP{}
 //	@Router			/api/v1/users [post]
 func (h *UserHandler) CreateUser(c *fiber.Ctx) error {
@@ -95,6 +137,14 @@ func (h *UserHandler) CreateUser(c *fiber.Ctx) error {
 		})
 	}
 
+	if actor := auth.ActorFromContext(c); actor.Role != model.RoleAdmin && payload.ID != actor.ID {
+		return c.Status(fiber.StatusForbidden).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Users can only register their own account",
+			Data:    nil,
+		})
+	}
+
 	user, err := h.userService.CreateUser(&payload)
 	if err != nil {
 		if strings.Contains(err.Error(), "duplicate key value violates unique constraint") {
@@ -161,31 +211,33 @@ func (h *UserHandler) GetUserByID(c *fiber.Ctx) error {
 	})
 }
 
-// UpdateMembershipStatus is a function to update a user's membership status
+// UpdateUser is a function to update the profile of a user
 //
-//	@Summary		Update the membership status of a user
-//	@Description	Update the membership status of a user
+//	@Summary		Update user profile
+//	@Description	Change the name, company name or phone number of a user. Fields left out are not changed
 //	@Tags			users
 //
 //	@Security		BearerAuth
 //
 //	@Accept			json
 //	@Produce		json
-//	@Param			id		path		string							true	"User ID"
-//	@Param			request	body		model.OrderStatusChangeRequest	true	"Status update"
-//	@Success		200		{object}	model.ResponseHTTP{data=model.MembershipStatusChangeRequest}
+//	@Param			id		path		string					true	"User ID"
+//	@Param			request	body		model.UpdateUserRequest	true	"Profile fields"
+//	@Success		200		{object}	model.ResponseHTTP{data=model.UserResponse}
+//	@Failure		400		{object}	model.ResponseHTTP{}
 //	@Failure		404		{object}	model.ResponseHTTP{}
+//	@Failure		410		{object}	model.ResponseHTTP{}
 //	@Failure		500		{object}	model.ResponseHTTP{}
-//	@Router			/api/v1/users/{id}/membership [put]
-func (h *UserHandler) UpdateMembershipStatus(c *fiber.Ctx) error {
+//	@Router			/api/v1/users/{id} [put]
+func (h *UserHandler) UpdateUser(c *fiber.Ctx) error {
 	id := c.Params("id")
 
-	var payload model.MembershipStatusChangeRequest
+	var payload model.UpdateUserRequest
 
 	if err := c.BodyParser(&payload); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
 			Success: false,
-			Message: err.Error(),
+			Message: "Invalid request",
 			Data:    nil,
 		})
 	}
@@ -198,26 +250,143 @@ func (h *UserHandler) UpdateMembershipStatus(c *fiber.Ctx) error {
 		})
 	}
 
-	user, err := h.userService.UpdateUserMembershipStatusChange(id, &payload)
+	user, err := h.userService.UpdateUser(id, &payload)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return c.Status(fiber.StatusNotFound).JSON(model.ResponseHTTP{
-				Success: false,
-				Message: "User not found",
-				Data:    nil,
-			})
-		}
+		return h.userError(c, err)
+	}
 
-		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseHTTP{
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully updated user",
+		Data:    *user,
+	})
+}
+
+// DeleteUser is a function to delete the account of a user
+//
+//	@Summary		Delete user account
+//	@Description	Anonymize the personal data of a user and sign them out everywhere. Orders and invoices are kept for accounting
+//	@Tags			users
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"User ID"
+//	@Success		200	{object}	model.ResponseHTTP{}
+//	@Failure		404	{object}	model.ResponseHTTP{}
+//	@Failure		410	{object}	model.ResponseHTTP{}
+//	@Failure		500	{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/users/{id} [delete]
+func (h *UserHandler) DeleteUser(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	if err := h.userService.DeleteUser(id); err != nil {
+		return h.userError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully deleted user",
+		Data:    nil,
+	})
+}
+
+// GetNotificationPreferences is a function to get the notification preferences of a user
+//
+//	@Summary		Get notification preferences
+//	@Description	Get the channels (email, SMS, WhatsApp) a user can be reached on and the notifications (order updates, marketing, blog) they want
+//	@Tags			users
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		string	true	"User ID"
+//	@Success		200	{object}	model.ResponseHTTP{data=model.NotificationPreference}
+//	@Failure		404	{object}	model.ResponseHTTP{}
+//	@Failure		500	{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/users/{id}/notification-preferences [get]
+func (h *UserHandler) GetNotificationPreferences(c *fiber.Ctx) error {
+	preference, err := h.userService.GetNotificationPreferences(c.Params("id"))
+	if err != nil {
+		return h.userError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
+		Success: true,
+		Message: "Successfully found notification preferences",
+		Data:    *preference,
+	})
+}
+
+// UpdateNotificationPreferences is a function to change the notification preferences of a user
+//
+//	@Summary		Update notification preferences
+//	@Description	Switch notification channels and categories on or off. A notification is only sent when both its channel and its category are on. Fields left out are not changed
+//	@Tags			users
+//
+//	@Security		BearerAuth
+//
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		string								true	"User ID"
+//	@Param			request	body		model.NotificationPreferenceRequest	true	"Preferences to change"
+//	@Success		200		{object}	model.ResponseHTTP{data=model.NotificationPreference}
+//	@Failure		400		{object}	model.ResponseHTTP{}
+//	@Failure		404		{object}	model.ResponseHTTP{}
+//	@Failure		410		{object}	model.ResponseHTTP{}
+//	@Failure		500		{object}	model.ResponseHTTP{}
+//	@Router			/api/v1/users/{id}/notification-preferences [put]
+func (h *UserHandler) UpdateNotificationPreferences(c *fiber.Ctx) error {
+	var payload model.NotificationPreferenceRequest
+
+	if err := c.BodyParser(&payload); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
 			Success: false,
-			Message: "Internal server error",
+			Message: "Invalid request",
 			Data:    nil,
 		})
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(model.ResponseHTTP{
+	preference, err := h.userService.UpdateNotificationPreferences(c.Params("id"), &payload)
+	if err != nil {
+		return h.userError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(model.ResponseHTTP{
 		Success: true,
-		Message: "Successfully updated user membership status",
-		Data:    *user,
+		Message: "Successfully updated notification preferences",
+		Data:    *preference,
 	})
 }
+
+func (*UserHandler) userError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "User not found",
+			Data:    nil,
+		})
+	case errors.Is(err, service.ErrUserDeleted):
+		return c.Status(fiber.StatusGone).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "This account has been deleted",
+			Data:    nil,
+		})
+	case errors.Is(err, service.ErrUserNameRequired),
+		errors.Is(err, service.ErrPhoneNumberRequired):
+		return c.Status(fiber.StatusBadRequest).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(model.ResponseHTTP{
+			Success: false,
+			Message: "Internal server error",
+			Data:    nil,
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package mailer
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/MogboPython/belvaphilips_backend/internal/config"
+)
+
+const defaultDropDir = "tmp/mail"
+
+// Message is an HTML email to be delivered by a Mailer
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers emails
+type Mailer interface {
+	Send(msg *Message) error
+}
+
+// New returns the Mailer selected by MAIL_TRANSPORT: "smtp" (default), "file" or "memory"
+func New() (Mailer, error) {
+	from := config.Config("PLUNK_EMAIL")
+
+	switch transport := config.Config("MAIL_TRANSPORT"); transport {
+	case "", "smtp":
+		port, err := strconv.Atoi(config.Config("MAIL_PORT"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid MAIL_PORT: %w", err)
+		}
+
+		return NewSMTPMailer(config.Config("MAIL_HOST"), port, config.Config("PLUNK_USERNAME"), config.Config("PLUNK_API_KEY"), from), nil
+	case "file":
+		dir := config.Config("MAIL_DROP_DIR")
+		if dir == "" {
+			dir = defaultDropDir
+		}
+
+		return NewFileMailer(dir, from), nil
+	case "memory":
+		return NewMemoryMailer(), nil
+	default:
+		return nil, fmt.Errorf("unknown MAIL_TRANSPORT %q", transport)
+	}
+}
@@ -0,0 +1,57 @@
+package mailer
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileMailer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Should write the email as an .eml file", func(t *testing.T) {
+		t.Parallel()
+
+		dir := t.TempDir()
+		m := NewFileMailer(dir, "studio@example.com")
+
+		err := m.Send(&Message{To: "client@example.com", Subject: "Hello", Body: "<p>Hi there</p>"})
+		assert.NoError(t, err)
+
+		files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+		assert.NoError(t, err)
+		assert.Len(t, files, 1)
+
+		content, err := os.ReadFile(files[0])
+		assert.NoError(t, err)
+		assert.True(t, strings.Contains(string(content), "To: client@example.com"))
+		assert.True(t, strings.Contains(string(content), "Subject: Hello"))
+	})
+}
+
+func TestMemoryMailer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Should record sent emails", func(t *testing.T) {
+		t.Parallel()
+
+		m := NewMemoryMailer()
+
+		assert.NoError(t, m.Send(&Message{To: "client@example.com", Subject: "Hello"}))
+		assert.Equal(t, []Message{{To: "client@example.com", Subject: "Hello"}}, m.Messages())
+	})
+
+	t.Run("Should return the configured error", func(t *testing.T) {
+		t.Parallel()
+
+		m := NewMemoryMailer()
+		m.FailWith(errors.New("smtp down"))
+
+		assert.Error(t, m.Send(&Message{To: "client@example.com"}))
+		assert.Empty(t, m.Messages())
+	})
+}
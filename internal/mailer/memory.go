@@ -0,0 +1,43 @@
+package mailer
+
+import "sync"
+
+// MemoryMailer records emails instead of sending them, for tests
+type MemoryMailer struct {
+	err      error
+	messages []Message
+	mu       sync.Mutex
+}
+
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+func (m *MemoryMailer) Send(msg *Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.err != nil {
+		return m.err
+	}
+
+	m.messages = append(m.messages, *msg)
+
+	return nil
+}
+
+// Messages returns a copy of every email sent so far
+func (m *MemoryMailer) Messages() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]Message(nil), m.messages...)
+}
+
+// FailWith makes every following Send return err, or succeed again when err is nil
+func (m *MemoryMailer) FailWith(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.err = err
+}
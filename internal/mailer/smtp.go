@@ -0,0 +1,32 @@
+package mailer
+
+import (
+	gomail "gopkg.in/mail.v2"
+)
+
+type smtpMailer struct {
+	dialer *gomail.Dialer
+	from   string
+}
+
+// NewSMTPMailer returns a Mailer that sends emails through an SMTP server
+func NewSMTPMailer(host string, port int, username, password, from string) Mailer {
+	return &smtpMailer{
+		dialer: gomail.NewDialer(host, port, username, password),
+		from:   from,
+	}
+}
+
+func (m *smtpMailer) Send(msg *Message) error {
+	return m.dialer.DialAndSend(newGomailMessage(m.from, msg))
+}
+
+func newGomailMessage(from string, msg *Message) *gomail.Message {
+	message := gomail.NewMessage()
+	message.SetHeader("From", from)
+	message.SetHeader("To", msg.To)
+	message.SetHeader("Subject", msg.Subject)
+	message.SetBody("text/html", msg.Body)
+
+	return message
+}
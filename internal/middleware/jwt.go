@@ -1,20 +1,116 @@
 package middleware
 
 import (
+	"github.com/MogboPython/belvaphilips_backend/internal/auth"
 	"github.com/MogboPython/belvaphilips_backend/internal/config"
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
 
 	jwtware "github.com/gofiber/contrib/jwt"
 	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func Protected() fiber.Handler {
+// RevocationChecker reports whether an access token was revoked before it expired
+type RevocationChecker interface {
+	IsRevoked(jti string) (bool, error)
+}
+
+// AccountChecker reports whether the account of a customer was deleted
+type AccountChecker interface {
+	IsDeleted(userID string) (bool, error)
+}
+
+// Protected accepts the tokens this API signs with JWT_SECRET unless they were revoked and, when
+// the verifier has a JWKS, access tokens issued by the identity provider. Tokens of customers
+// whose account was deleted are refused, whoever issued them
+func Protected(verifier *auth.Verifier, revocations RevocationChecker, accounts AccountChecker) fiber.Handler {
+	secret := []byte(config.Config("JWT_SECRET"))
+
 	return jwtware.New(jwtware.Config{
-		SigningKey:   jwtware.SigningKey{Key: []byte(config.Config("JWT_SECRET"))},
+		KeyFunc: func(token *jwt.Token) (any, error) {
+			if auth.IsUpstream(token) {
+				return verifier.Keyfunc(token)
+			}
+
+			if token.Method != jwt.SigningMethodHS256 {
+				return nil, jwtware.ErrJWTAlg
+			}
+
+			return secret, nil
+		},
+		SuccessHandler: func(c *fiber.Ctx) error {
+			return checkRevoked(c, revocations, accounts)
+		},
 		ErrorHandler: jwtError,
 	})
 }
 
+// checkRevoked refuses tokens of sessions that were signed out. The identity provider revokes
+// its own tokens
+func checkRevoked(c *fiber.Ctx, revocations RevocationChecker, accounts AccountChecker) error {
+	token, ok := c.Locals("user").(*jwt.Token)
+	if !ok {
+		return c.Next()
+	}
+
+	if auth.IsUpstream(token) {
+		return checkDeleted(c, accounts)
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return jwtError(c, jwt.ErrTokenInvalidClaims)
+	}
+
+	// Tokens signed before sessions existed have no jti and cannot be revoked, so they are refused
+	jti, _ := claims["jti"].(string)
+	if jti == "" {
+		return jwtError(c, jwt.ErrTokenInvalidClaims)
+	}
+
+	revoked, err := revocations.IsRevoked(jti)
+	if err != nil {
+		log.Errorf("Failed to check revocation of token %s: %v", jti, err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Internal server error",
+		})
+	}
+
+	if revoked {
+		return jwtError(c, jwt.ErrTokenInvalidClaims)
+	}
+
+	return checkDeleted(c, accounts)
+}
+
+// checkDeleted refuses customers whose account was deleted. Staff accounts are disabled instead
+// and checked with their permissions
+func checkDeleted(c *fiber.Ctx, accounts AccountChecker) error {
+	actor := auth.ActorFromContext(c)
+	if actor.Role == model.RoleAdmin || actor.ID == "" {
+		return c.Next()
+	}
+
+	deleted, err := accounts.IsDeleted(actor.ID)
+	if err != nil {
+		log.Errorf("Failed to check account of user %s: %v", actor.ID, err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Internal server error",
+		})
+	}
+
+	if deleted {
+		return jwtError(c, jwt.ErrTokenInvalidClaims)
+	}
+
+	return c.Next()
+}
+
 func jwtError(c *fiber.Ctx, err error) error {
 	if err.Error() == "Missing or malformed JWT" {
 		return c.Status(fiber.StatusBadRequest).
@@ -24,53 +120,3 @@ func jwtError(c *fiber.Ctx, err error) error {
 	return c.Status(fiber.StatusUnauthorized).
 		JSON(fiber.Map{"status": "error", "message": "Invalid or expired JWT", "data": nil})
 }
-
-func AdminRole() fiber.Handler {
-	return func(c *fiber.Ctx) error {
-		// Get the token from the context (already validated by JWT middleware)
-		userToken := c.Locals("user")
-		if userToken == nil {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"status":  "error",
-				"message": "Unauthorized: missing token",
-			})
-		}
-
-		// Extract claims from the token
-		token, ok := userToken.(*jwt.Token)
-		if !ok {
-			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-				"status":  "error",
-				"message": "Access denied: invalid token",
-			})
-		}
-
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-				"status":  "error",
-				"message": "Access denied: invalid token",
-			})
-		}
-
-		// Check if the "role" claim exists
-		role, ok := claims["role"].(string)
-		if !ok {
-			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-				"status":  "error",
-				"message": "Access denied: invalid token",
-			})
-		}
-
-		// Allow access only if the role is "admin"
-		if role != "admin" {
-			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-				"status":  "error",
-				"message": "Access denied: insufficient permissions",
-			})
-		}
-
-		// Role is admin, proceed to the next handler
-		return c.Next()
-	}
-}
@@ -0,0 +1,169 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/MogboPython/belvaphilips_backend/internal/auth"
+	"github.com/MogboPython/belvaphilips_backend/internal/auth/authtest"
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/MogboPython/belvaphilips_backend/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubRevocations lists revoked-jti as the only revoked access token
+type stubRevocations struct{}
+
+func (stubRevocations) IsRevoked(jti string) (bool, error) {
+	return jti == "revoked-jti", nil
+}
+
+// stubAccounts lists deleted-user as the only deleted account
+type stubAccounts struct{}
+
+func (stubAccounts) IsDeleted(userID string) (bool, error) {
+	return userID == "deleted-user", nil
+}
+
+// newUpstreamToken signs a provider token and returns it with a verifier for the JWKS served locally
+func newUpstreamToken(t *testing.T, claims jwt.MapClaims) (string, *auth.Verifier) {
+	t.Helper()
+
+	jwks := authtest.NewJWKS(t, "key-1")
+
+	verifier, err := auth.NewVerifier(jwks.URL(), "", "")
+	assert.NoError(t, err)
+	t.Cleanup(verifier.Close)
+
+	return jwks.Sign(t, "key-1", claims), verifier
+}
+
+func TestProtectedUpstreamTokens(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Should accept provider tokens as the user in their subject", func(t *testing.T) {
+		t.Parallel()
+
+		// Provider roles such as service_role must never turn into staff access
+		token, verifier := newUpstreamToken(t, jwt.MapClaims{
+			"sub":  "user-1",
+			"role": model.RoleAdmin,
+			"exp":  time.Now().Add(time.Hour).Unix(),
+		})
+
+		var actor *model.Actor
+
+		app := fiber.New()
+		app.Get("/me", Protected(verifier, stubRevocations{}, stubAccounts{}), func(c *fiber.Ctx) error {
+			actor = auth.ActorFromContext(c)
+			return c.SendStatus(fiber.StatusOK)
+		})
+
+		req := httptest.NewRequest(fiber.MethodGet, "/me", nil)
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, &model.Actor{ID: "user-1", Role: model.RoleAuthenticated}, actor)
+	})
+
+	t.Run("Should refuse provider tokens of deleted accounts", func(t *testing.T) {
+		t.Parallel()
+
+		token, verifier := newUpstreamToken(t, jwt.MapClaims{"sub": "deleted-user", "exp": time.Now().Add(time.Hour).Unix()})
+
+		app := fiber.New()
+		app.Get("/me", Protected(verifier, stubRevocations{}, stubAccounts{}), func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusOK)
+		})
+
+		req := httptest.NewRequest(fiber.MethodGet, "/me", nil)
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("Should reject provider tokens when no JWKS is configured", func(t *testing.T) {
+		t.Parallel()
+
+		token, _ := newUpstreamToken(t, jwt.MapClaims{"sub": "user-1", "exp": time.Now().Add(time.Hour).Unix()})
+		verifier, err := auth.NewVerifier("", "", "")
+		assert.NoError(t, err)
+
+		app := fiber.New()
+		app.Get("/me", Protected(verifier, stubRevocations{}, stubAccounts{}), func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusOK)
+		})
+
+		req := httptest.NewRequest(fiber.MethodGet, "/me", nil)
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+
+		resp, err := app.Test(req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+	})
+}
+
+func TestProtectedRevokedTokens(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	verifier, err := auth.NewVerifier("", "", "")
+	assert.NoError(t, err)
+
+	app := fiber.New()
+	app.Get("/me", Protected(verifier, stubRevocations{}, stubAccounts{}), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	legacy, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sessionId": "user-1",
+		"role":      model.RoleAuthenticated,
+		"exp":       time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte("test-secret"))
+	assert.NoError(t, err)
+
+	cases := []struct {
+		name   string
+		user   string
+		jti    string
+		token  string
+		status int
+	}{
+		{name: "Should accept tokens of open sessions", jti: "open-jti", status: fiber.StatusOK},
+		{name: "Should refuse tokens of deleted accounts", user: "deleted-user", jti: "open-jti", status: fiber.StatusUnauthorized},
+		{name: "Should refuse revoked tokens", jti: "revoked-jti", status: fiber.StatusUnauthorized},
+		{name: "Should refuse tokens without a jti", token: legacy, status: fiber.StatusUnauthorized},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			user := tc.user
+			if user == "" {
+				user = "user-1"
+			}
+
+			token := tc.token
+			if token == "" {
+				token, err = utils.GenerateToken(user, model.RoleAuthenticated, tc.jti)
+				assert.NoError(t, err)
+			}
+
+			req := httptest.NewRequest(fiber.MethodGet, "/me", nil)
+			req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+
+			resp, err := app.Test(req)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.status, resp.StatusCode)
+		})
+	}
+}
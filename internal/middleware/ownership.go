@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"errors"
+
+	"github.com/MogboPython/belvaphilips_backend/internal/auth"
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+	"gorm.io/gorm"
+)
+
+// OwnerResolver returns the ID of the user who owns the resource addressed by a request
+type OwnerResolver func(c *fiber.Ctx) (string, error)
+
+// ParamOwner resolves the owner from a route parameter that holds the user ID itself
+func ParamOwner(param string) OwnerResolver {
+	return func(c *fiber.Ctx) (string, error) {
+		return c.Params(param), nil
+	}
+}
+
+// SharedOwnerResolver returns the user who owns the resource addressed by a request and the
+// organization it is shared with, if any
+type SharedOwnerResolver func(c *fiber.Ctx) (*model.ResourceOwner, error)
+
+// MembershipChecker reports whether a user is a member of an organization
+type MembershipChecker interface {
+	IsMember(organizationID, userID string) (bool, error)
+}
+
+// ParamOrganization resolves the organization from a route parameter that holds its ID
+func ParamOrganization(param string) SharedOwnerResolver {
+	return func(c *fiber.Ctx) (*model.ResourceOwner, error) {
+		return &model.ResourceOwner{OrganizationID: c.Params(param)}, nil
+	}
+}
+
+// OwnerOrAdmin lets through staff whose roles grant all of the permissions and otherwise only the
+// user whose sessionId matches the owner of the resource. It must run after Protected
+func OwnerOrAdmin(resolve OwnerResolver, checker PermissionChecker, permissions ...string) fiber.Handler {
+	return OwnerMemberOrAdmin(func(c *fiber.Ctx) (*model.ResourceOwner, error) {
+		ownerID, err := resolve(c)
+		if err != nil {
+			return nil, err
+		}
+
+		return &model.ResourceOwner{UserID: ownerID}, nil
+	}, nil, checker, permissions...)
+}
+
+// OwnerMemberOrAdmin lets through staff whose roles grant all of the permissions, the user who
+// owns the resource and the members of the organization the resource is shared with. It must run
+// after Protected
+func OwnerMemberOrAdmin(resolve SharedOwnerResolver, members MembershipChecker, checker PermissionChecker, permissions ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		actor := auth.ActorFromContext(c)
+
+		// Staff never own resources, so they need the permissions of the route and an active account
+		if actor.ID != "" && actor.Role == model.RoleAdmin {
+			return checkPermissions(c, checker, permissions)
+		}
+
+		return checkOwnership(c, actor, resolve, members)
+	}
+}
+
+// OwnerOrMember lets through only the user who owns the resource and the members of the
+// organization it is shared with. Staff are refused, as the route acts for the customer, such as
+// accepting a quote. It must run after Protected
+func OwnerOrMember(resolve SharedOwnerResolver, members MembershipChecker) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		actor := auth.ActorFromContext(c)
+
+		if actor.ID != "" && actor.Role == model.RoleAdmin {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Access denied: only the customer can do this",
+			})
+		}
+
+		return checkOwnership(c, actor, resolve, members)
+	}
+}
+
+// checkOwnership lets the request through when actor owns the resource or is a member of the
+// organization it is shared with
+func checkOwnership(c *fiber.Ctx, actor *model.Actor, resolve SharedOwnerResolver, members MembershipChecker) error {
+	if actor.ID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Unauthorized: missing token",
+		})
+	}
+
+	owner, err := resolve(c)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Resource not found",
+			})
+		}
+
+		log.Errorf("Failed to resolve owner of %s: %v", c.Path(), err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Internal server error",
+		})
+	}
+
+	if owner.UserID != "" && owner.UserID == actor.ID {
+		return c.Next()
+	}
+
+	if owner.OrganizationID != "" && members != nil {
+		member, err := members.IsMember(owner.OrganizationID, actor.ID)
+		if err != nil {
+			log.Errorf("Failed to check membership of %s in organization %s: %v", actor.ID, owner.OrganizationID, err)
+
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"status":  "error",
+				"message": "Internal server error",
+			})
+		}
+
+		if member {
+			return c.Next()
+		}
+	}
+
+	return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+		"status":  "error",
+		"message": "Access denied: resource belongs to another user",
+	})
+}
@@ -0,0 +1,165 @@
+package middleware
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// newOwnershipApp serves GET /users/:id behind OwnerOrAdmin, authenticated as the given caller.
+// Staff need posts:write, which stubPermissionChecker only grants to admin-1
+func newOwnershipApp(sessionID, role string, resolve OwnerResolver) *fiber.App {
+	app := fiber.New()
+
+	app.Get("/users/:id", func(c *fiber.Ctx) error {
+		c.Locals("user", jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"sessionId": sessionID,
+			"role":      role,
+		}))
+
+		return c.Next()
+	}, OwnerOrAdmin(resolve, stubPermissionChecker{}, model.PermissionPostsWrite), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	return app
+}
+
+func TestOwnerOrAdmin(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		resolve OwnerResolver
+		name    string
+		session string
+		role    string
+		status  int
+	}{
+		{name: "Should let users read their own resources", session: "user-1", role: model.RoleAuthenticated, resolve: ParamOwner("id"), status: fiber.StatusOK},
+		{name: "Should stop users reading other users' resources", session: "user-2", role: model.RoleAuthenticated, resolve: ParamOwner("id"), status: fiber.StatusForbidden},
+		{name: "Should let staff with the permission read any resource", session: "admin-1", role: model.RoleAdmin, resolve: ParamOwner("id"), status: fiber.StatusOK},
+		{name: "Should stop staff without the permission", session: "admin-2", role: model.RoleAdmin, resolve: ParamOwner("id"), status: fiber.StatusForbidden},
+		{name: "Should reject tokens without a session", session: "", role: model.RoleAuthenticated, resolve: ParamOwner("id"), status: fiber.StatusUnauthorized},
+		{
+			name: "Should report resources that do not exist", session: "user-1", role: model.RoleAuthenticated, status: fiber.StatusNotFound,
+			resolve: func(*fiber.Ctx) (string, error) { return "", gorm.ErrRecordNotFound },
+		},
+		{
+			name: "Should fail closed when the owner cannot be looked up", session: "user-1", role: model.RoleAuthenticated, status: fiber.StatusInternalServerError,
+			resolve: func(*fiber.Ctx) (string, error) { return "", errors.New("connection refused") },
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			resp, err := newOwnershipApp(tc.session, tc.role, tc.resolve).Test(httptest.NewRequest(fiber.MethodGet, "/users/user-1", nil))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.status, resp.StatusCode)
+		})
+	}
+}
+
+// stubMembers reports membership from a set of "organization/user" pairs
+type stubMembers map[string]bool
+
+func (m stubMembers) IsMember(organizationID, userID string) (bool, error) {
+	if organizationID == "broken" {
+		return false, errors.New("connection refused")
+	}
+
+	return m[organizationID+"/"+userID], nil
+}
+
+func TestOwnerMemberOrAdmin(t *testing.T) {
+	t.Parallel()
+
+	members := stubMembers{"org-1/user-2": true}
+
+	cases := []struct {
+		owner   *model.ResourceOwner
+		name    string
+		session string
+		status  int
+	}{
+		{name: "Should let the owner through", session: "user-1", owner: &model.ResourceOwner{UserID: "user-1", OrganizationID: "org-1"}, status: fiber.StatusOK},
+		{name: "Should let members of the organization through", session: "user-2", owner: &model.ResourceOwner{UserID: "user-1", OrganizationID: "org-1"}, status: fiber.StatusOK},
+		{name: "Should stop users outside the organization", session: "user-3", owner: &model.ResourceOwner{UserID: "user-1", OrganizationID: "org-1"}, status: fiber.StatusForbidden},
+		{name: "Should stop members on resources that are not shared", session: "user-2", owner: &model.ResourceOwner{UserID: "user-1"}, status: fiber.StatusForbidden},
+		{name: "Should fail closed when membership cannot be checked", session: "user-2", owner: &model.ResourceOwner{OrganizationID: "broken"}, status: fiber.StatusInternalServerError},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			app := fiber.New()
+			app.Get("/orders/:id", func(c *fiber.Ctx) error {
+				c.Locals("user", jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+					"sessionId": tc.session,
+					"role":      model.RoleAuthenticated,
+				}))
+
+				return c.Next()
+			}, OwnerMemberOrAdmin(func(*fiber.Ctx) (*model.ResourceOwner, error) { return tc.owner, nil }, members, stubPermissionChecker{}, model.PermissionPostsWrite), func(c *fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/orders/order-1", nil))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.status, resp.StatusCode)
+		})
+	}
+}
+
+func TestOwnerOrMember(t *testing.T) {
+	t.Parallel()
+
+	members := stubMembers{"org-1/user-2": true}
+	owner := &model.ResourceOwner{UserID: "user-1", OrganizationID: "org-1"}
+
+	cases := []struct {
+		name    string
+		session string
+		role    string
+		status  int
+	}{
+		{name: "Should let the owner through", session: "user-1", role: model.RoleAuthenticated, status: fiber.StatusOK},
+		{name: "Should let members of the organization through", session: "user-2", role: model.RoleAuthenticated, status: fiber.StatusOK},
+		{name: "Should stop other users", session: "user-3", role: model.RoleAuthenticated, status: fiber.StatusForbidden},
+		{name: "Should stop staff whatever their permissions", session: "admin-1", role: model.RoleAdmin, status: fiber.StatusForbidden},
+		{name: "Should reject tokens without a session", session: "", role: model.RoleAuthenticated, status: fiber.StatusUnauthorized},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			app := fiber.New()
+			app.Post("/orders/:id/cancel", func(c *fiber.Ctx) error {
+				c.Locals("user", jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+					"sessionId": tc.session,
+					"role":      tc.role,
+				}))
+
+				return c.Next()
+			}, OwnerOrMember(func(*fiber.Ctx) (*model.ResourceOwner, error) { return owner, nil }, members), func(c *fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodPost, "/orders/order-1/cancel", nil))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.status, resp.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"github.com/MogboPython/belvaphilips_backend/internal/auth"
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+// PermissionChecker reports whether an active staff account holds every one of the permissions
+// through its roles. Without permissions it only checks that the account is active.
+// MustChangePassword reports whether the account still has to replace a temporary password
+type PermissionChecker interface {
+	HasPermissions(adminID string, permissions ...string) (bool, error)
+	MustChangePassword(adminID string) (bool, error)
+}
+
+// RequirePermission lets through staff whose roles grant all of the permissions. Without
+// permissions any active staff member is let through. It must run after Protected
+func RequirePermission(checker PermissionChecker, permissions ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return checkPermissions(c, checker, permissions)
+	}
+}
+
+// RequirePasswordChange lets any active staff member through, including those who must still
+// replace a temporary password and are refused everywhere else. It is only for changing the
+// password itself
+func RequirePasswordChange(checker PermissionChecker) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return authorizeStaff(c, checker, nil, true)
+	}
+}
+
+// RequirePermissionWhen only checks the permissions for requests matched by when, such as
+// posts that are saved as published
+func RequirePermissionWhen(checker PermissionChecker, when func(c *fiber.Ctx) bool, permissions ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !when(c) {
+			return c.Next()
+		}
+
+		return checkPermissions(c, checker, permissions)
+	}
+}
+
+func checkPermissions(c *fiber.Ctx, checker PermissionChecker, permissions []string) error {
+	return authorizeStaff(c, checker, permissions, false)
+}
+
+func authorizeStaff(c *fiber.Ctx, checker PermissionChecker, permissions []string, passwordChange bool) error {
+	actor := auth.ActorFromContext(c)
+	if actor.ID == "" {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Unauthorized: missing token",
+		})
+	}
+
+	if actor.Role != model.RoleAdmin {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Access denied: insufficient permissions",
+		})
+	}
+
+	// Permissions are looked up on every request so role changes and disabled accounts take effect
+	// without waiting for tokens to expire
+	allowed, err := checker.HasPermissions(actor.ID, permissions...)
+	if err != nil {
+		log.Errorf("Failed to check permissions of admin %s: %v", actor.ID, err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Internal server error",
+		})
+	}
+
+	if !allowed {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Access denied: insufficient permissions",
+		})
+	}
+
+	if passwordChange {
+		return c.Next()
+	}
+
+	mustChange, err := checker.MustChangePassword(actor.ID)
+	if err != nil {
+		log.Errorf("Failed to check password of admin %s: %v", actor.ID, err)
+
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Internal server error",
+		})
+	}
+
+	if mustChange {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"status":  "error",
+			"message": "Access denied: change your temporary password first",
+		})
+	}
+
+	return c.Next()
+}
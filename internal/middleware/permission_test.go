@@ -0,0 +1,150 @@
+package middleware
+
+import (
+	"errors"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubPermissionChecker grants admin-1 the editor permissions and nothing to anyone else.
+// admin-3 holds them too but must still change its temporary password
+type stubPermissionChecker struct {
+	err error
+}
+
+func (s stubPermissionChecker) MustChangePassword(adminID string) (bool, error) {
+	return adminID == "admin-3", s.err
+}
+
+func (s stubPermissionChecker) HasPermissions(adminID string, permissions ...string) (bool, error) {
+	if s.err != nil {
+		return false, s.err
+	}
+
+	if adminID != "admin-1" && adminID != "admin-3" {
+		return false, nil
+	}
+
+	granted := []string{model.PermissionPostsWrite, model.PermissionGalleryWrite}
+	for _, permission := range permissions {
+		if !slices.Contains(granted, permission) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// newPermissionApp serves POST /posts behind the handlers, authenticated as the given caller
+func newPermissionApp(sessionID, role string, handlers ...fiber.Handler) *fiber.App {
+	app := fiber.New()
+
+	handlers = append([]fiber.Handler{func(c *fiber.Ctx) error {
+		c.Locals("user", jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"sessionId": sessionID,
+			"role":      role,
+		}))
+
+		return c.Next()
+	}}, handlers...)
+
+	app.Post("/posts", append(handlers, func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})...)
+
+	return app
+}
+
+func TestRequirePermission(t *testing.T) {
+	t.Parallel()
+
+	checker := stubPermissionChecker{}
+
+	cases := []struct {
+		checker     PermissionChecker
+		name        string
+		session     string
+		role        string
+		permissions []string
+		status      int
+	}{
+		{name: "Should let staff with the permission through", session: "admin-1", role: model.RoleAdmin, checker: checker, permissions: []string{model.PermissionPostsWrite}, status: fiber.StatusOK},
+		{name: "Should require every permission", session: "admin-1", role: model.RoleAdmin, checker: checker, permissions: []string{model.PermissionPostsWrite, model.PermissionPostsPublish}, status: fiber.StatusForbidden},
+		{name: "Should stop staff without the permission", session: "admin-2", role: model.RoleAdmin, checker: checker, permissions: []string{model.PermissionPostsWrite}, status: fiber.StatusForbidden},
+		{name: "Should stop customers before looking up permissions", session: "admin-1", role: model.RoleAuthenticated, checker: checker, permissions: []string{model.PermissionPostsWrite}, status: fiber.StatusForbidden},
+		{name: "Should reject tokens without a session", session: "", role: model.RoleAdmin, checker: checker, status: fiber.StatusUnauthorized},
+		{name: "Should stop staff who must change their temporary password", session: "admin-3", role: model.RoleAdmin, checker: checker, permissions: []string{model.PermissionPostsWrite}, status: fiber.StatusForbidden},
+		{name: "Should fail closed when permissions cannot be looked up", session: "admin-1", role: model.RoleAdmin, checker: stubPermissionChecker{err: errors.New("connection refused")}, status: fiber.StatusInternalServerError},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			app := newPermissionApp(tc.session, tc.role, RequirePermission(tc.checker, tc.permissions...))
+
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodPost, "/posts", nil))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.status, resp.StatusCode)
+		})
+	}
+}
+
+func TestRequirePermissionWhen(t *testing.T) {
+	t.Parallel()
+
+	publishing := func(c *fiber.Ctx) bool { return c.Query("status") == "published" }
+	app := newPermissionApp("admin-1", model.RoleAdmin, RequirePermissionWhen(stubPermissionChecker{}, publishing, model.PermissionPostsPublish))
+
+	t.Run("Should skip the check for requests that do not match", func(t *testing.T) {
+		t.Parallel()
+
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodPost, "/posts?status=draft", nil))
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("Should check the permissions for requests that match", func(t *testing.T) {
+		t.Parallel()
+
+		resp, err := app.Test(httptest.NewRequest(fiber.MethodPost, "/posts?status=published", nil))
+
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+	})
+}
+
+func TestRequirePasswordChange(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		session string
+		status  int
+	}{
+		{name: "Should let staff who must change their password through", session: "admin-3", status: fiber.StatusOK},
+		{name: "Should let other active staff through", session: "admin-1", status: fiber.StatusOK},
+		{name: "Should stop staff who are not active", session: "admin-2", status: fiber.StatusForbidden},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			app := newPermissionApp(tc.session, model.RoleAdmin, RequirePasswordChange(stubPermissionChecker{}))
+
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodPost, "/posts", nil))
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.status, resp.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package notifier
+
+import "sync"
+
+// FakeNotifier records text messages instead of sending them, for tests and local development
+type FakeNotifier struct {
+	err      error
+	messages []Message
+	mu       sync.Mutex
+}
+
+func NewFakeNotifier() *FakeNotifier {
+	return &FakeNotifier{}
+}
+
+func (n *FakeNotifier) Send(msg *Message) error {
+	if err := validChannel(msg.Channel); err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.err != nil {
+		return n.err
+	}
+
+	n.messages = append(n.messages, *msg)
+
+	return nil
+}
+
+// Messages returns a copy of every message sent so far
+func (n *FakeNotifier) Messages() []Message {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return append([]Message(nil), n.messages...)
+}
+
+// FailWith makes every following Send return err, or succeed again when err is nil
+func (n *FakeNotifier) FailWith(err error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.err = err
+}
@@ -0,0 +1,73 @@
+package notifier
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/MogboPython/belvaphilips_backend/internal/config"
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+var (
+	ErrNotConfigured      = errors.New("text message provider is not configured")
+	ErrUnsupportedChannel = errors.New("channel is not supported by the text message provider")
+)
+
+// Message is a text message to be delivered by a Notifier. Channel is model.NotificationChannelSMS
+// or model.NotificationChannelWhatsApp and To is a phone number in international format
+type Message struct {
+	Channel string
+	To      string
+	Body    string
+}
+
+// Notifier delivers SMS and WhatsApp messages
+type Notifier interface {
+	Send(msg *Message) error
+}
+
+// New returns the Notifier selected by NOTIFIER_PROVIDER: "termii", "twilio" or "fake". A provider
+// chosen without its credentials fails to start. When none is chosen Termii is used if
+// TERMII_API_KEY is set, and otherwise the fake notifier keeps text messages from being delivered
+func New() (Notifier, error) {
+	switch provider := config.Config("NOTIFIER_PROVIDER"); provider {
+	case "":
+		if config.Config("TERMII_API_KEY") == "" {
+			log.Warn("NOTIFIER_PROVIDER and TERMII_API_KEY are not set, text messages will not be delivered")
+			return NewFakeNotifier(), nil
+		}
+
+		return NewTermiiNotifier(config.Config("TERMII_API_KEY"), config.Config("TERMII_SENDER_ID"), config.Config("TERMII_BASE_URL")), nil
+	case "termii":
+		if config.Config("TERMII_API_KEY") == "" {
+			return nil, fmt.Errorf("%w: TERMII_API_KEY is not set", ErrNotConfigured)
+		}
+
+		return NewTermiiNotifier(config.Config("TERMII_API_KEY"), config.Config("TERMII_SENDER_ID"), config.Config("TERMII_BASE_URL")), nil
+	case "twilio":
+		if config.Config("TWILIO_ACCOUNT_SID") == "" || config.Config("TWILIO_AUTH_TOKEN") == "" {
+			return nil, fmt.Errorf("%w: TWILIO_ACCOUNT_SID and TWILIO_AUTH_TOKEN must be set", ErrNotConfigured)
+		}
+
+		return NewTwilioNotifier(
+			config.Config("TWILIO_ACCOUNT_SID"),
+			config.Config("TWILIO_AUTH_TOKEN"),
+			config.Config("TWILIO_SMS_FROM"),
+			config.Config("TWILIO_WHATSAPP_FROM"),
+			config.Config("TWILIO_BASE_URL"),
+		), nil
+	case "fake":
+		return NewFakeNotifier(), nil
+	default:
+		return nil, fmt.Errorf("unknown NOTIFIER_PROVIDER %q", provider)
+	}
+}
+
+func validChannel(channel string) error {
+	if channel != model.NotificationChannelSMS && channel != model.NotificationChannelWhatsApp {
+		return fmt.Errorf("%w: %q", ErrUnsupportedChannel, channel)
+	}
+
+	return nil
+}
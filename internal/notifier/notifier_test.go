@@ -0,0 +1,182 @@
+package notifier
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTermiiNotifier(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Should send WhatsApp messages on the whatsapp channel", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/api/sms/send", r.URL.Path)
+
+			var body map[string]string
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, "tl_key", body["api_key"])
+			assert.Equal(t, "2348012345678", body["to"])
+			assert.Equal(t, "Belva", body["from"])
+			assert.Equal(t, "whatsapp", body["channel"])
+			assert.Equal(t, "Your photos are ready", body["sms"])
+
+			_, _ = w.Write([]byte(`{"message_id":"1","message":"Successfully Sent"}`))
+		}))
+		defer server.Close()
+
+		err := NewTermiiNotifier("tl_key", "Belva", server.URL).Send(&Message{
+			Channel: model.NotificationChannelWhatsApp,
+			To:      "+2348012345678",
+			Body:    "Your photos are ready",
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Should surface the error message of a failed request", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"message":"Insufficient balance"}`))
+		}))
+		defer server.Close()
+
+		err := NewTermiiNotifier("tl_key", "Belva", server.URL).Send(&Message{Channel: model.NotificationChannelSMS, To: "+2348012345678"})
+
+		assert.ErrorContains(t, err, "Insufficient balance")
+	})
+
+	t.Run("Should refuse to send without an API key", func(t *testing.T) {
+		t.Parallel()
+
+		err := NewTermiiNotifier("", "Belva", "").Send(&Message{Channel: model.NotificationChannelSMS, To: "+2348012345678"})
+
+		assert.ErrorIs(t, err, ErrNotConfigured)
+	})
+}
+
+func TestTwilioNotifier(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Should prefix WhatsApp numbers and authenticate with the account", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/2010-04-01/Accounts/AC123/Messages.json", r.URL.Path)
+
+			user, pass, ok := r.BasicAuth()
+			assert.True(t, ok)
+			assert.Equal(t, "AC123", user)
+			assert.Equal(t, "secret", pass)
+
+			assert.NoError(t, r.ParseForm())
+			assert.Equal(t, "whatsapp:+14155238886", r.PostForm.Get("From"))
+			assert.Equal(t, "whatsapp:+2348012345678", r.PostForm.Get("To"))
+
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"sid":"SM1","status":"queued"}`))
+		}))
+		defer server.Close()
+
+		err := NewTwilioNotifier("AC123", "secret", "+15005550006", "+14155238886", server.URL).Send(&Message{
+			Channel: model.NotificationChannelWhatsApp,
+			To:      "+2348012345678",
+			Body:    "Your photos are ready",
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Should surface the error of a rejected message", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":21211,"message":"The 'To' number is not a valid phone number."}`))
+		}))
+		defer server.Close()
+
+		err := NewTwilioNotifier("AC123", "secret", "+15005550006", "", server.URL).Send(&Message{Channel: model.NotificationChannelSMS, To: "123"})
+
+		assert.ErrorContains(t, err, "not a valid phone number")
+	})
+
+	t.Run("Should need a WhatsApp sender for WhatsApp messages", func(t *testing.T) {
+		t.Parallel()
+
+		err := NewTwilioNotifier("AC123", "secret", "+15005550006", "", "").Send(&Message{Channel: model.NotificationChannelWhatsApp, To: "+2348012345678"})
+
+		assert.ErrorIs(t, err, ErrNotConfigured)
+	})
+}
+
+func TestFakeNotifier(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Should record sent messages", func(t *testing.T) {
+		t.Parallel()
+
+		n := NewFakeNotifier()
+		msg := Message{Channel: model.NotificationChannelSMS, To: "+2348012345678", Body: "Hello"}
+
+		assert.NoError(t, n.Send(&msg))
+		assert.Equal(t, []Message{msg}, n.Messages())
+	})
+
+	t.Run("Should reject channels that are not text messages", func(t *testing.T) {
+		t.Parallel()
+
+		n := NewFakeNotifier()
+
+		assert.ErrorIs(t, n.Send(&Message{Channel: model.NotificationChannelEmail}), ErrUnsupportedChannel)
+
+		n.FailWith(errors.New("provider down"))
+		assert.Error(t, n.Send(&Message{Channel: model.NotificationChannelSMS}))
+		assert.Empty(t, n.Messages())
+	})
+}
+
+func TestNew(t *testing.T) {
+	t.Setenv("FLY_APP_NAME", "test")
+	t.Setenv("TERMII_API_KEY", "")
+	t.Setenv("TWILIO_ACCOUNT_SID", "")
+	t.Setenv("TWILIO_AUTH_TOKEN", "")
+
+	t.Run("Should not deliver text messages when no provider is configured", func(t *testing.T) {
+		t.Setenv("NOTIFIER_PROVIDER", "")
+
+		n, err := New()
+
+		assert.NoError(t, err)
+		assert.IsType(t, &FakeNotifier{}, n)
+	})
+
+	t.Run("Should use Termii when only its API key is set", func(t *testing.T) {
+		t.Setenv("NOTIFIER_PROVIDER", "")
+		t.Setenv("TERMII_API_KEY", "tl_key")
+
+		n, err := New()
+
+		assert.NoError(t, err)
+		assert.IsType(t, &termiiNotifier{}, n)
+	})
+
+	t.Run("Should refuse to start a chosen provider without its credentials", func(t *testing.T) {
+		for _, provider := range []string{"termii", "twilio"} {
+			t.Setenv("NOTIFIER_PROVIDER", provider)
+
+			_, err := New()
+
+			assert.ErrorIs(t, err, ErrNotConfigured, provider)
+		}
+	})
+}
@@ -0,0 +1,92 @@
+package notifier
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+)
+
+const (
+	defaultTermiiBaseURL = "https://api.ng.termii.com"
+	termiiTimeout        = 30 * time.Second
+)
+
+type termiiNotifier struct {
+	client   *http.Client
+	apiKey   string
+	senderID string
+	baseURL  string
+}
+
+// NewTermiiNotifier returns a Notifier backed by the Termii messaging API, which sends SMS and
+// WhatsApp messages from the same sender ID
+func NewTermiiNotifier(apiKey, senderID, baseURL string) Notifier {
+	if baseURL == "" {
+		baseURL = defaultTermiiBaseURL
+	}
+
+	return &termiiNotifier{
+		client:   &http.Client{Timeout: termiiTimeout},
+		apiKey:   apiKey,
+		senderID: senderID,
+		baseURL:  strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+func (n *termiiNotifier) Send(msg *Message) error {
+	if n.apiKey == "" {
+		return ErrNotConfigured
+	}
+
+	if err := validChannel(msg.Channel); err != nil {
+		return err
+	}
+
+	channel := "generic"
+	if msg.Channel == model.NotificationChannelWhatsApp {
+		channel = "whatsapp"
+	}
+
+	payload, err := json.Marshal(map[string]string{
+		"api_key": n.apiKey,
+		"to":      strings.TrimPrefix(msg.To, "+"),
+		"from":    n.senderID,
+		"sms":     msg.Body,
+		"type":    "plain",
+		"channel": channel,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, n.baseURL+"/api/sms/send", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach termii: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		var body struct {
+			Message string `json:"message"`
+		}
+
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body)
+
+		return fmt.Errorf("termii returned %d: %s", resp.StatusCode, body.Message)
+	}
+
+	return nil
+}
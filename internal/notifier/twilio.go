@@ -0,0 +1,97 @@
+package notifier
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+)
+
+const (
+	defaultTwilioBaseURL = "https://api.twilio.com"
+	twilioTimeout        = 30 * time.Second
+)
+
+type twilioNotifier struct {
+	client       *http.Client
+	accountSID   string
+	authToken    string
+	smsFrom      string
+	whatsAppFrom string
+	baseURL      string
+}
+
+// NewTwilioNotifier returns a Notifier backed by the Twilio Messages API. SMS are sent from
+// smsFrom and WhatsApp messages from whatsAppFrom, both phone numbers in international format
+func NewTwilioNotifier(accountSID, authToken, smsFrom, whatsAppFrom, baseURL string) Notifier {
+	if baseURL == "" {
+		baseURL = defaultTwilioBaseURL
+	}
+
+	return &twilioNotifier{
+		client:       &http.Client{Timeout: twilioTimeout},
+		accountSID:   accountSID,
+		authToken:    authToken,
+		smsFrom:      smsFrom,
+		whatsAppFrom: whatsAppFrom,
+		baseURL:      strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+func (n *twilioNotifier) Send(msg *Message) error {
+	if n.accountSID == "" || n.authToken == "" {
+		return ErrNotConfigured
+	}
+
+	if err := validChannel(msg.Channel); err != nil {
+		return err
+	}
+
+	from, to := n.smsFrom, msg.To
+	if msg.Channel == model.NotificationChannelWhatsApp {
+		from, to = "whatsapp:"+n.whatsAppFrom, "whatsapp:"+msg.To
+	}
+
+	if from == "" || from == "whatsapp:" {
+		return fmt.Errorf("%w: no sender for %s", ErrNotConfigured, msg.Channel)
+	}
+
+	form := url.Values{}
+	form.Set("From", from)
+	form.Set("To", to)
+	form.Set("Body", msg.Body)
+
+	endpoint := fmt.Sprintf("%s/2010-04-01/Accounts/%s/Messages.json", n.baseURL, url.PathEscape(n.accountSID))
+
+	req, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+
+	req.SetBasicAuth(n.accountSID, n.authToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach twilio: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		var body struct {
+			Message string `json:"message"`
+			Code    int    `json:"code"`
+		}
+
+		_ = json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body)
+
+		return fmt.Errorf("twilio returned %d (code %d): %s", resp.StatusCode, body.Code, body.Message)
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+const defaultFakeSecret = "fake-webhook-secret"
+
+// FakeEvent is the webhook body understood by FakeProvider
+type FakeEvent struct {
+	Type      string `json:"type"`
+	Reference string `json:"reference"`
+	Currency  string `json:"currency"`
+	Amount    int64  `json:"amount"`
+}
+
+// FakeProvider records checkouts and refunds instead of calling a gateway, for tests and local
+// development. Webhooks are signed with an HMAC-SHA256 of the body, see Sign
+type FakeProvider struct {
+	secret    string
+	checkouts []CheckoutRequest
+	refunds   []Refund
+	mu        sync.Mutex
+}
+
+func NewFakeProvider(secret string) *FakeProvider {
+	if secret == "" {
+		secret = defaultFakeSecret
+	}
+
+	return &FakeProvider{secret: secret}
+}
+
+func (*FakeProvider) Name() string {
+	return "fake"
+}
+
+func (*FakeProvider) SignatureHeader() string {
+	return "X-Fake-Signature"
+}
+
+func (p *FakeProvider) CreateCheckoutSession(_ context.Context, req *CheckoutRequest) (*CheckoutSession, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.checkouts = append(p.checkouts, *req)
+
+	return &CheckoutSession{
+		Reference:   req.Reference,
+		CheckoutURL: "https://checkout.fake.local/" + req.Reference,
+	}, nil
+}
+
+func (p *FakeProvider) VerifyWebhook(payload []byte, signature string) (*Event, error) {
+	if !hmac.Equal([]byte(p.Sign(payload)), []byte(signature)) {
+		return nil, ErrInvalidSignature
+	}
+
+	var event FakeEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("invalid fake webhook payload: %w", err)
+	}
+
+	return &Event{
+		Type:      event.Type,
+		Reference: event.Reference,
+		Currency:  event.Currency,
+		Amount:    event.Amount,
+	}, nil
+}
+
+func (p *FakeProvider) Refund(_ context.Context, reference string, amount int64) (*Refund, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	refund := Refund{
+		ID:        fmt.Sprintf("fake-refund-%d", len(p.refunds)+1),
+		Reference: reference,
+		Status:    "processed",
+		Amount:    amount,
+	}
+
+	p.refunds = append(p.refunds, refund)
+
+	return &refund, nil
+}
+
+// Sign returns the signature FakeProvider expects for a webhook body
+func (p *FakeProvider) Sign(payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(p.secret))
+	mac.Write(payload)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Checkouts returns a copy of every checkout session created so far
+func (p *FakeProvider) Checkouts() []CheckoutRequest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]CheckoutRequest(nil), p.checkouts...)
+}
+
+// Refunds returns a copy of every refund issued so far
+func (p *FakeProvider) Refunds() []Refund {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]Refund(nil), p.refunds...)
+}
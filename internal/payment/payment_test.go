@@ -0,0 +1,132 @@
+package payment
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func signPaystack(secret string, payload []byte) string {
+	mac := hmac.New(sha512.New, []byte(secret))
+	mac.Write(payload)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestPaystackProvider(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Should initialize a transaction and return the checkout URL", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/transaction/initialize", r.URL.Path)
+			assert.Equal(t, "Bearer sk_test", r.Header.Get("Authorization"))
+
+			var body map[string]any
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, "BELVA-PAY-1", body["reference"])
+			assert.InDelta(t, 150000, body["amount"], 0)
+
+			_, _ = w.Write([]byte(`{"status":true,"message":"ok","data":{"authorization_url":"https://checkout.paystack.com/abc","reference":"BELVA-PAY-1"}}`))
+		}))
+		defer server.Close()
+
+		session, err := NewPaystackProvider("sk_test", server.URL).CreateCheckoutSession(context.Background(), &CheckoutRequest{
+			Reference: "BELVA-PAY-1",
+			Email:     "client@example.com",
+			Currency:  "NGN",
+			Amount:    150000,
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "https://checkout.paystack.com/abc", session.CheckoutURL)
+	})
+
+	t.Run("Should surface the error message of a failed request", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"status":false,"message":"Transaction has been fully reversed"}`))
+		}))
+		defer server.Close()
+
+		_, err := NewPaystackProvider("sk_test", server.URL).Refund(context.Background(), "BELVA-PAY-1", 1000)
+
+		assert.ErrorContains(t, err, "Transaction has been fully reversed")
+	})
+
+	t.Run("Should verify a signed charge.success webhook", func(t *testing.T) {
+		t.Parallel()
+
+		payload := []byte(`{"event":"charge.success","data":{"reference":"BELVA-PAY-1","currency":"NGN","amount":150000}}`)
+
+		event, err := NewPaystackProvider("sk_test", "").VerifyWebhook(payload, signPaystack("sk_test", payload))
+
+		assert.NoError(t, err)
+		assert.Equal(t, EventPaymentSucceeded, event.Type)
+		assert.Equal(t, "BELVA-PAY-1", event.Reference)
+		assert.Equal(t, int64(150000), event.Amount)
+	})
+
+	t.Run("Should reject a webhook signed with another key", func(t *testing.T) {
+		t.Parallel()
+
+		payload := []byte(`{"event":"charge.success","data":{"reference":"BELVA-PAY-1"}}`)
+
+		_, err := NewPaystackProvider("sk_test", "").VerifyWebhook(payload, signPaystack("sk_other", payload))
+
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("Should not call paystack without a secret key", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewPaystackProvider("", "").CreateCheckoutSession(context.Background(), &CheckoutRequest{})
+
+		assert.ErrorIs(t, err, ErrProviderNotConfigured)
+	})
+}
+
+func TestFakeProvider(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Should accept webhooks it signed", func(t *testing.T) {
+		t.Parallel()
+
+		provider := NewFakeProvider("secret")
+		payload, _ := json.Marshal(FakeEvent{Type: EventPaymentSucceeded, Reference: "BELVA-PAY-1", Currency: "NGN", Amount: 500})
+
+		event, err := provider.VerifyWebhook(payload, provider.Sign(payload))
+
+		assert.NoError(t, err)
+		assert.Equal(t, EventPaymentSucceeded, event.Type)
+		assert.Equal(t, int64(500), event.Amount)
+
+		_, err = provider.VerifyWebhook(payload, NewFakeProvider("other").Sign(payload))
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+
+	t.Run("Should record checkouts and refunds", func(t *testing.T) {
+		t.Parallel()
+
+		provider := NewFakeProvider("")
+
+		_, err := provider.CreateCheckoutSession(context.Background(), &CheckoutRequest{Reference: "BELVA-PAY-1", Amount: 500})
+		assert.NoError(t, err)
+
+		_, err = provider.Refund(context.Background(), "BELVA-PAY-1", 200)
+		assert.NoError(t, err)
+
+		assert.Len(t, provider.Checkouts(), 1)
+		assert.Equal(t, int64(200), provider.Refunds()[0].Amount)
+	})
+}
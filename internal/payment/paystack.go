@@ -0,0 +1,176 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPaystackBaseURL = "https://api.paystack.co"
+	paystackTimeout        = 30 * time.Second
+)
+
+type paystackProvider struct {
+	client    *http.Client
+	secretKey string
+	baseURL   string
+}
+
+// NewPaystackProvider returns a Provider backed by the Paystack API. Paystack signs webhooks
+// with an HMAC-SHA512 of the body keyed by the secret key
+func NewPaystackProvider(secretKey, baseURL string) Provider {
+	if baseURL == "" {
+		baseURL = defaultPaystackBaseURL
+	}
+
+	return &paystackProvider{
+		client:    &http.Client{Timeout: paystackTimeout},
+		secretKey: secretKey,
+		baseURL:   strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+func (*paystackProvider) Name() string {
+	return "paystack"
+}
+
+func (*paystackProvider) SignatureHeader() string {
+	return "X-Paystack-Signature"
+}
+
+func (p *paystackProvider) CreateCheckoutSession(ctx context.Context, req *CheckoutRequest) (*CheckoutSession, error) {
+	body := map[string]any{
+		"email":     req.Email,
+		"amount":    req.Amount,
+		"currency":  req.Currency,
+		"reference": req.Reference,
+	}
+
+	if req.CallbackURL != "" {
+		body["callback_url"] = req.CallbackURL
+	}
+
+	var data struct {
+		AuthorizationURL string `json:"authorization_url"`
+		Reference        string `json:"reference"`
+	}
+
+	if err := p.do(ctx, "/transaction/initialize", body, &data); err != nil {
+		return nil, err
+	}
+
+	return &CheckoutSession{
+		Reference:   data.Reference,
+		CheckoutURL: data.AuthorizationURL,
+	}, nil
+}
+
+func (p *paystackProvider) VerifyWebhook(payload []byte, signature string) (*Event, error) {
+	if p.secretKey == "" {
+		return nil, ErrInvalidSignature
+	}
+
+	mac := hmac.New(sha512.New, []byte(p.secretKey))
+	mac.Write(payload)
+
+	expected := hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(signature))) {
+		return nil, ErrInvalidSignature
+	}
+
+	var notification struct {
+		Event string `json:"event"`
+		Data  struct {
+			Reference string `json:"reference"`
+			Currency  string `json:"currency"`
+			Amount    int64  `json:"amount"`
+		} `json:"data"`
+	}
+
+	if err := json.Unmarshal(payload, &notification); err != nil {
+		return nil, fmt.Errorf("invalid paystack webhook payload: %w", err)
+	}
+
+	event := &Event{
+		Reference: notification.Data.Reference,
+		Currency:  notification.Data.Currency,
+		Amount:    notification.Data.Amount,
+	}
+
+	if notification.Event == "charge.success" {
+		event.Type = EventPaymentSucceeded
+	}
+
+	return event, nil
+}
+
+func (p *paystackProvider) Refund(ctx context.Context, reference string, amount int64) (*Refund, error) {
+	var data struct {
+		Status string `json:"status"`
+		ID     int64  `json:"id"`
+		Amount int64  `json:"amount"`
+	}
+
+	if err := p.do(ctx, "/refund", map[string]any{"transaction": reference, "amount": amount}, &data); err != nil {
+		return nil, err
+	}
+
+	return &Refund{
+		ID:        strconv.FormatInt(data.ID, 10),
+		Reference: reference,
+		Status:    data.Status,
+		Amount:    data.Amount,
+	}, nil
+}
+
+// do posts body to a Paystack endpoint and decodes the data field of the response into out
+func (p *paystackProvider) do(ctx context.Context, path string, body, out any) error {
+	if p.secretKey == "" {
+		return ErrProviderNotConfigured
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+p.secretKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach paystack: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var envelope struct {
+		Message string          `json:"message"`
+		Data    json.RawMessage `json:"data"`
+		Status  bool            `json:"status"`
+	}
+
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&envelope); err != nil {
+		return fmt.Errorf("paystack returned %d with an unreadable body: %w", resp.StatusCode, err)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest || !envelope.Status {
+		return fmt.Errorf("paystack returned %d: %s", resp.StatusCode, envelope.Message)
+	}
+
+	return json.Unmarshal(envelope.Data, out)
+}
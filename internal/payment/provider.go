@@ -0,0 +1,73 @@
+package payment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/MogboPython/belvaphilips_backend/internal/config"
+)
+
+var (
+	ErrInvalidSignature      = errors.New("invalid webhook signature")
+	ErrProviderNotConfigured = errors.New("payment provider is not configured")
+)
+
+const (
+	EventPaymentSucceeded = "payment.succeeded"
+	EventPaymentFailed    = "payment.failed"
+)
+
+// CheckoutRequest describes a payment to collect. Amount is in minor currency units
+type CheckoutRequest struct {
+	Reference   string
+	Email       string
+	Currency    string
+	CallbackURL string
+	Amount      int64
+}
+
+// CheckoutSession is a hosted page where the customer completes a payment
+type CheckoutSession struct {
+	Reference   string
+	CheckoutURL string
+}
+
+// Event is a verified webhook notification about a payment. Type is empty for
+// notifications the application does not act on
+type Event struct {
+	Type      string
+	Reference string
+	Currency  string
+	Amount    int64
+}
+
+// Refund is a refund accepted by the provider
+type Refund struct {
+	ID        string
+	Reference string
+	Status    string
+	Amount    int64
+}
+
+// Provider collects and refunds payments through a payment gateway
+type Provider interface {
+	Name() string
+	// SignatureHeader is the request header carrying the webhook signature
+	SignatureHeader() string
+	CreateCheckoutSession(ctx context.Context, req *CheckoutRequest) (*CheckoutSession, error)
+	VerifyWebhook(payload []byte, signature string) (*Event, error)
+	Refund(ctx context.Context, reference string, amount int64) (*Refund, error)
+}
+
+// New returns the Provider selected by PAYMENT_PROVIDER: "paystack" (default) or "fake"
+func New() (Provider, error) {
+	switch provider := config.Config("PAYMENT_PROVIDER"); provider {
+	case "", "paystack":
+		return NewPaystackProvider(config.Config("PAYSTACK_SECRET_KEY"), config.Config("PAYSTACK_BASE_URL")), nil
+	case "fake":
+		return NewFakeProvider(config.Config("PAYMENT_WEBHOOK_SECRET")), nil
+	default:
+		return nil, fmt.Errorf("unknown PAYMENT_PROVIDER %q", provider)
+	}
+}
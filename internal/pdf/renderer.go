@@ -0,0 +1,86 @@
+package pdf
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/MogboPython/belvaphilips_backend/internal/config"
+)
+
+var ErrRendererNotConfigured = errors.New("pdf renderer is not configured")
+
+const renderTimeout = 30 * time.Second
+
+// Renderer turns an HTML document into a PDF
+type Renderer interface {
+	RenderHTML(ctx context.Context, html string) ([]byte, error)
+}
+
+type gotenbergRenderer struct {
+	client  *http.Client
+	baseURL string
+}
+
+// New returns a Renderer backed by the Gotenberg server at PDF_RENDERER_URL
+func New() Renderer {
+	return NewGotenbergRenderer(config.Config("PDF_RENDERER_URL"))
+}
+
+// NewGotenbergRenderer returns a Renderer that converts HTML with a Gotenberg server's Chromium module
+func NewGotenbergRenderer(baseURL string) Renderer {
+	return &gotenbergRenderer{
+		client:  &http.Client{Timeout: renderTimeout},
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+func (r *gotenbergRenderer) RenderHTML(ctx context.Context, html string) ([]byte, error) {
+	if r.baseURL == "" {
+		return nil, ErrRendererNotConfigured
+	}
+
+	var body bytes.Buffer
+
+	writer := multipart.NewWriter(&body)
+
+	// Gotenberg expects the document as a file named index.html
+	part, err := writer.CreateFormFile("files", "index.html")
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := io.WriteString(part, html); err != nil {
+		return nil, err
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.baseURL+"/forms/chromium/convert/html", &body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach pdf renderer: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("pdf renderer returned %d: %s", resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,46 @@
+package pdf
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGotenbergRenderer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Should post the HTML as index.html and return the PDF", func(t *testing.T) {
+		t.Parallel()
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/forms/chromium/convert/html", r.URL.Path)
+
+			file, header, err := r.FormFile("files")
+			assert.NoError(t, err)
+			assert.Equal(t, "index.html", header.Filename)
+
+			content, _ := io.ReadAll(file)
+			assert.Equal(t, "<h1>Invoice</h1>", string(content))
+
+			_, _ = w.Write([]byte("%PDF-1.7"))
+		}))
+		defer server.Close()
+
+		out, err := NewGotenbergRenderer(server.URL).RenderHTML(context.Background(), "<h1>Invoice</h1>")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "%PDF-1.7", string(out))
+	})
+
+	t.Run("Should fail when no renderer URL is configured", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewGotenbergRenderer("").RenderHTML(context.Background(), "<h1>Invoice</h1>")
+
+		assert.ErrorIs(t, err, ErrRendererNotConfigured)
+	})
+}
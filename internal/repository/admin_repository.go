@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"gorm.io/gorm"
+)
+
+type AdminRepository interface {
+	Create(admin *model.Admin, roleIDs []string, emails []*model.EmailOutbox) error
+	GetByID(id string) (*model.Admin, error)
+	GetByUsername(username string) (*model.Admin, error)
+	GetAll() ([]*model.Admin, error)
+	Count() (int64, error)
+	Update(admin *model.Admin, emails []*model.EmailOutbox, columns ...string) error
+	RecordLogin(adminID string, at time.Time) error
+	ConsumeTOTPStep(adminID string, step int64) error
+	RecordTOTPFailure(adminID string, maxAttempts int, lockedUntil time.Time) error
+	SetRecoveryCodes(adminID string, codeHashes []string) error
+	UseRecoveryCode(adminID, codeHash string) error
+}
+
+type adminRepository struct {
+	db *gorm.DB
+}
+
+func NewAdminRepository(db *gorm.DB) AdminRepository {
+	return &adminRepository{
+		db: db,
+	}
+}
+
+// Create saves a new staff account with its roles and queues its invitation emails
+func (r *adminRepository) Create(admin *model.Admin, roleIDs []string, emails []*model.EmailOutbox) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(admin).Error; err != nil {
+			if isDuplicateError(err) {
+				return errors.New("admin already exists")
+			}
+
+			return err
+		}
+
+		if err := assignRoles(tx, admin.ID, roleIDs); err != nil {
+			return err
+		}
+
+		return enqueueEmails(tx, emails)
+	})
+}
+
+func (r *adminRepository) GetByID(id string) (*model.Admin, error) {
+	var admin model.Admin
+
+	if err := r.db.Where("id = ?", id).First(&admin).Error; err != nil {
+		return nil, err
+	}
+
+	return &admin, nil
+}
+
+func (r *adminRepository) GetByUsername(username string) (*model.Admin, error) {
+	var admin model.Admin
+
+	if err := r.db.Where("lower(username) = lower(?)", username).First(&admin).Error; err != nil {
+		return nil, err
+	}
+
+	return &admin, nil
+}
+
+func (r *adminRepository) GetAll() ([]*model.Admin, error) {
+	var admins []*model.Admin
+
+	if err := r.db.Order("created_at ASC").Find(&admins).Error; err != nil {
+		return nil, err
+	}
+
+	return admins, nil
+}
+
+func (r *adminRepository) Count() (int64, error) {
+	var count int64
+
+	if err := r.db.Model(&model.Admin{}).Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// Update writes the given columns of a staff account together with any emails queued for it.
+// Other columns are left alone, so changes made meanwhile by another request are kept
+func (r *adminRepository) Update(admin *model.Admin, emails []*model.EmailOutbox, columns ...string) error {
+	if len(columns) == 0 {
+		return errors.New("no admin columns to update")
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(admin).Select(append(columns, "updated_at")).Updates(admin)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return enqueueEmails(tx, emails)
+	})
+}
+
+// RecordLogin sets when a staff account last signed in
+func (r *adminRepository) RecordLogin(adminID string, at time.Time) error {
+	return r.db.Model(&model.Admin{ID: adminID}).UpdateColumn("last_login_at", at).Error
+}
+
+// ConsumeTOTPStep records the time step of an accepted TOTP code so the same code cannot be
+// replayed, and clears earlier failed attempts
+func (r *adminRepository) ConsumeTOTPStep(adminID string, step int64) error {
+	result := r.db.Model(&model.Admin{}).
+		Where("id = ? AND totp_last_step < ?", adminID, step).
+		Updates(map[string]any{
+			"totp_last_step":       step,
+			"totp_failed_attempts": 0,
+			"totp_locked_until":    nil,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("two-factor code was already used")
+	}
+
+	return nil
+}
+
+// RecordTOTPFailure counts a wrong two-factor code and locks two-factor sign in until lockedUntil
+// once maxAttempts codes in a row were wrong
+func (r *adminRepository) RecordTOTPFailure(adminID string, maxAttempts int, lockedUntil time.Time) error {
+	return r.db.Model(&model.Admin{}).
+		Where("id = ?", adminID).
+		Updates(map[string]any{
+			"totp_failed_attempts": gorm.Expr("CASE WHEN totp_failed_attempts + 1 >= ? THEN 0 ELSE totp_failed_attempts + 1 END", maxAttempts),
+			"totp_locked_until":    gorm.Expr("CASE WHEN totp_failed_attempts + 1 >= ? THEN ?::timestamptz ELSE totp_locked_until END", maxAttempts, lockedUntil),
+		}).Error
+}
+
+// SetRecoveryCodes replaces the recovery codes of an admin. No hashes removes them all
+func (r *adminRepository) SetRecoveryCodes(adminID string, codeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("admin_id = ?", adminID).Delete(&model.AdminRecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		if len(codeHashes) == 0 {
+			return nil
+		}
+
+		codes := make([]*model.AdminRecoveryCode, len(codeHashes))
+		for i, hash := range codeHashes {
+			codes[i] = &model.AdminRecoveryCode{AdminID: adminID, CodeHash: hash}
+		}
+
+		return tx.Create(&codes).Error
+	})
+}
+
+// UseRecoveryCode spends a recovery code and clears earlier failed attempts
+func (r *adminRepository) UseRecoveryCode(adminID, codeHash string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.AdminRecoveryCode{}).
+			Where("admin_id = ? AND code_hash = ? AND used_at IS NULL", adminID, codeHash).
+			Update("used_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return errors.New("recovery code not found")
+		}
+
+		return tx.Model(&model.Admin{}).
+			Where("id = ?", adminID).
+			Updates(map[string]any{"totp_failed_attempts": 0, "totp_locked_until": nil}).Error
+	})
+}
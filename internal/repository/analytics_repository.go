@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"gorm.io/gorm"
+)
+
+// AnalyticsRepository aggregates orders and users in the database. Every method counts what
+// happened from from until to, excluding to, and leaves deleted orders out
+type AnalyticsRepository interface {
+	CountOrdersByPeriod(from, to time.Time, interval string) ([]model.AnalyticsPeriod, error)
+	CountUsersByPeriod(from, to time.Time, interval string) ([]model.AnalyticsPeriod, error)
+	CountOrdersByShootType(from, to time.Time) ([]model.AnalyticsBreakdown, error)
+	CountOrdersByDeliverySpeed(from, to time.Time) ([]model.AnalyticsBreakdown, error)
+	GetCompletionTime(from, to time.Time) (*model.OrderCompletionTime, error)
+	GetTopCustomers(from, to time.Time, limit int) ([]model.TopCustomer, error)
+}
+
+type analyticsRepository struct {
+	db *gorm.DB
+}
+
+func NewAnalyticsRepository(db *gorm.DB) AnalyticsRepository {
+	return &analyticsRepository{
+		db: db,
+	}
+}
+
+func (r *analyticsRepository) CountOrdersByPeriod(from, to time.Time, interval string) ([]model.AnalyticsPeriod, error) {
+	return r.countByPeriod("orders", "AND t.deleted_at IS NULL", from, to, interval)
+}
+
+func (r *analyticsRepository) CountUsersByPeriod(from, to time.Time, interval string) ([]model.AnalyticsPeriod, error) {
+	return r.countByPeriod("users", "", from, to, interval)
+}
+
+// countByPeriod counts the rows of table created in every interval of the range, including the
+// intervals without any. table and condition never come from a request
+func (r *analyticsRepository) countByPeriod(table, condition string, from, to time.Time, interval string) ([]model.AnalyticsPeriod, error) {
+	var periods []model.AnalyticsPeriod
+
+	query := fmt.Sprintf(`
+		SELECT periods.start, COUNT(t.id) AS count
+		FROM generate_series(
+			date_trunc(@interval, CAST(@from AS timestamptz)),
+			CAST(@to AS timestamptz) - interval '1 microsecond',
+			CAST('1 ' || @interval AS interval)
+		) AS periods(start)
+		LEFT JOIN %s t ON date_trunc(@interval, t.created_at) = periods.start
+			AND t.created_at >= @from AND t.created_at < @to %s
+		GROUP BY periods.start
+		ORDER BY periods.start`, table, condition)
+
+	if err := r.db.Raw(query, map[string]any{"interval": interval, "from": from, "to": to}).Scan(&periods).Error; err != nil {
+		return nil, err
+	}
+
+	return periods, nil
+}
+
+func (r *analyticsRepository) CountOrdersByShootType(from, to time.Time) ([]model.AnalyticsBreakdown, error) {
+	return r.countOrdersBy("shoot_type", from, to)
+}
+
+func (r *analyticsRepository) CountOrdersByDeliverySpeed(from, to time.Time) ([]model.AnalyticsBreakdown, error) {
+	return r.countOrdersBy("delivery_speed", from, to)
+}
+
+// countOrdersBy counts the orders placed in the range for every value of column, most common first.
+// column never comes from a request
+func (r *analyticsRepository) countOrdersBy(column string, from, to time.Time) ([]model.AnalyticsBreakdown, error) {
+	var breakdown []model.AnalyticsBreakdown
+
+	if err := r.db.Model(&model.Order{}).
+		Select(fmt.Sprintf("COALESCE(orders.%s, '') AS value, COUNT(*) AS count", column)).
+		Where("orders.created_at >= ? AND orders.created_at < ?", from, to).
+		Group("value").
+		Order("count DESC, value").
+		Scan(&breakdown).Error; err != nil {
+		return nil, err
+	}
+
+	return breakdown, nil
+}
+
+// GetCompletionTime averages the time from placing an order, when it enters quote_received, until
+// it was first marked completed, over the orders first marked completed in the range. Orders that
+// were reopened and completed again are counted once
+func (r *analyticsRepository) GetCompletionTime(from, to time.Time) (*model.OrderCompletionTime, error) {
+	var result struct {
+		AverageSeconds  *float64
+		CompletedOrders int64
+	}
+
+	completions := r.db.Table("order_status_history").
+		Select("order_id, MIN(created_at) AS completed_at").
+		Where("to_status = ?", model.OrderStatusMarkCompleted).
+		Group("order_id")
+
+	if err := r.db.Table("(?) AS c", completions).
+		Select("AVG(EXTRACT(EPOCH FROM c.completed_at - orders.created_at)) AS average_seconds, COUNT(*) AS completed_orders").
+		Joins("JOIN orders ON orders.id = c.order_id AND orders.deleted_at IS NULL").
+		Where("c.completed_at >= ? AND c.completed_at < ?", from, to).
+		Scan(&result).Error; err != nil {
+		return nil, err
+	}
+
+	completion := &model.OrderCompletionTime{CompletedOrders: result.CompletedOrders}
+
+	if result.AverageSeconds != nil {
+		hours := *result.AverageSeconds / time.Hour.Seconds()
+		completion.AverageHours = &hours
+	}
+
+	return completion, nil
+}
+
+// GetTopCustomers returns the customers who placed the most orders in the range
+func (r *analyticsRepository) GetTopCustomers(from, to time.Time, limit int) ([]model.TopCustomer, error) {
+	var customers []model.TopCustomer
+
+	if err := r.db.Model(&model.Order{}).
+		Select("users.id AS user_id, users.name, users.email, users.company_name, COUNT(orders.id) AS order_count").
+		Joins("JOIN users ON users.id = orders.user_id").
+		Where("orders.created_at >= ? AND orders.created_at < ?", from, to).
+		Group("users.id").
+		Order("order_count DESC, users.name").
+		Limit(limit).
+		Scan(&customers).Error; err != nil {
+		return nil, err
+	}
+
+	return customers, nil
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"gorm.io/gorm"
+)
+
+type CatalogRepository interface {
+	Create(item *model.CatalogItem) error
+	GetByID(id string) (*model.CatalogItem, error)
+	GetAll(activeOnly bool) ([]*model.CatalogItem, error)
+	GetActiveByName(category, name string) (*model.CatalogItem, error)
+	Update(item *model.CatalogItem) error
+	Delete(id string) error
+}
+
+type catalogRepository struct {
+	db *gorm.DB
+}
+
+func NewCatalogRepository(db *gorm.DB) CatalogRepository {
+	return &catalogRepository{
+		db: db,
+	}
+}
+
+func (r *catalogRepository) Create(item *model.CatalogItem) error {
+	err := r.db.Create(item).Error
+	if isDuplicateError(err) {
+		return errors.New("catalog item already exists")
+	}
+
+	return err
+}
+
+func (r *catalogRepository) GetByID(id string) (*model.CatalogItem, error) {
+	var item model.CatalogItem
+
+	if err := r.db.Where("id = ?", id).First(&item).Error; err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+func (r *catalogRepository) GetAll(activeOnly bool) ([]*model.CatalogItem, error) {
+	var items []*model.CatalogItem
+
+	tx := r.db.Model(&model.CatalogItem{})
+	if activeOnly {
+		tx = tx.Where("active = ?", true)
+	}
+
+	if err := tx.Order("category ASC, sort_order ASC, name ASC").Find(&items).Error; err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// GetActiveByName finds an active catalog item, ignoring the case of its name
+func (r *catalogRepository) GetActiveByName(category, name string) (*model.CatalogItem, error) {
+	var item model.CatalogItem
+
+	if err := r.db.Where("category = ? AND lower(name) = lower(?) AND active = ?", category, name, true).
+		First(&item).Error; err != nil {
+		return nil, err
+	}
+
+	return &item, nil
+}
+
+func (r *catalogRepository) Update(item *model.CatalogItem) error {
+	err := r.db.Save(item).Error
+	if isDuplicateError(err) {
+		return errors.New("catalog item already exists")
+	}
+
+	return err
+}
+
+func (r *catalogRepository) Delete(id string) error {
+	result := r.db.Where("id = ?", id).Delete(&model.CatalogItem{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ExportRepository interface {
+	Create(export *model.DataExport) error
+	GetByID(id string) (*model.DataExport, error)
+	GetLatest(userID string) (*model.DataExport, error)
+	ClaimDue(limit int, lease time.Duration) ([]*model.DataExport, error)
+	Save(export *model.DataExport) error
+	DeleteExpired(before time.Time) (int64, error)
+}
+
+type exportRepository struct {
+	db *gorm.DB
+}
+
+func NewExportRepository(db *gorm.DB) ExportRepository {
+	return &exportRepository{
+		db: db,
+	}
+}
+
+func (r *exportRepository) Create(export *model.DataExport) error {
+	return r.db.Create(export).Error
+}
+
+func (r *exportRepository) GetByID(id string) (*model.DataExport, error) {
+	var export model.DataExport
+
+	if err := r.db.Where("id = ?", id).First(&export).Error; err != nil {
+		return nil, err
+	}
+
+	return &export, nil
+}
+
+// GetLatest returns the most recent export of a user without loading the archive itself
+func (r *exportRepository) GetLatest(userID string) (*model.DataExport, error) {
+	var export model.DataExport
+
+	if err := r.db.Omit("archive").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		First(&export).Error; err != nil {
+		return nil, err
+	}
+
+	return &export, nil
+}
+
+// ClaimDue locks pending exports, and exports whose worker stopped before finishing, and marks
+// them as processing so other workers skip them
+func (r *exportRepository) ClaimDue(limit int, lease time.Duration) ([]*model.DataExport, error) {
+	var exports []*model.DataExport
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Omit("archive").
+			Where("status = ? OR (status = ? AND claimed_at < ?)",
+				model.DataExportStatusPending, model.DataExportStatusProcessing, now.Add(-lease)).
+			Order("created_at ASC").
+			Limit(limit).
+			Find(&exports).Error; err != nil {
+			return err
+		}
+
+		if len(exports) == 0 {
+			return nil
+		}
+
+		ids := make([]string, len(exports))
+		for i, export := range exports {
+			ids[i] = export.ID
+			export.Status = model.DataExportStatusProcessing
+			export.ClaimedAt = &now
+		}
+
+		return tx.Model(&model.DataExport{}).
+			Where("id IN ?", ids).
+			Updates(map[string]any{"status": model.DataExportStatusProcessing, "claimed_at": now}).Error
+	})
+
+	return exports, err
+}
+
+func (r *exportRepository) Save(export *model.DataExport) error {
+	return r.db.Save(export).Error
+}
+
+// DeleteExpired removes exports whose download link expired before the given time
+func (r *exportRepository) DeleteExpired(before time.Time) (int64, error) {
+	result := r.db.Where("expires_at < ?", before).Delete(&model.DataExport{})
+
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"gorm.io/gorm"
+)
+
+type InvoiceRepository interface {
+	Create(invoice *model.Invoice) error
+	GetByID(id string) (*model.Invoice, error)
+	GetAll(offset, limit int, status string) ([]*model.Invoice, int64, error)
+	Update(invoice *model.Invoice) error
+}
+
+type invoiceRepository struct {
+	db *gorm.DB
+}
+
+func NewInvoiceRepository(db *gorm.DB) InvoiceRepository {
+	return &invoiceRepository{
+		db: db,
+	}
+}
+
+// Create saves an invoice and its line items under the next sequential invoice number
+func (r *invoiceRepository) Create(invoice *model.Invoice) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var sequence int64
+
+		if err := tx.Raw("SELECT nextval('invoice_number_seq')").Scan(&sequence).Error; err != nil {
+			return fmt.Errorf("failed to generate invoice number: %w", err)
+		}
+
+		invoice.InvoiceNumber = fmt.Sprintf("BELVA-INV-%06d", sequence)
+
+		if err := tx.Omit("Order").Create(invoice).Error; err != nil {
+			if isDuplicateError(err) {
+				return errors.New("order already has an open invoice")
+			}
+
+			return err
+		}
+
+		return tx.Preload("User").Where("id = ?", invoice.OrderID).First(&invoice.Order).Error
+	})
+}
+
+func (r *invoiceRepository) GetByID(id string) (*model.Invoice, error) {
+	var invoice model.Invoice
+
+	if err := r.db.Preload("Order", unscopedOrder).Preload("Order.User").
+		Preload("LineItems", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
+		Where("id = ?", id).First(&invoice).Error; err != nil {
+		return nil, err
+	}
+
+	return &invoice, nil
+}
+
+func (r *invoiceRepository) GetAll(offset, limit int, status string) ([]*model.Invoice, int64, error) {
+	var invoices []*model.Invoice
+
+	var count int64
+
+	tx := r.db.Model(&model.Invoice{})
+	if status != "" {
+		tx = tx.Where("status = ?", status)
+	}
+
+	if err := tx.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := tx.Preload("Order", unscopedOrder).Preload("Order.User").
+		Preload("LineItems", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
+		Order("created_at DESC").Offset(offset).Limit(limit).Find(&invoices).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return invoices, count, nil
+}
+
+func (r *invoiceRepository) Update(invoice *model.Invoice) error {
+	return r.db.Omit("Order", "LineItems").Save(invoice).Error
+}
+
+// unscopedOrder loads the order of an invoice even when the order was deleted
+func unscopedOrder(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
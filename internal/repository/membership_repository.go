@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"gorm.io/gorm"
+)
+
+type MembershipRepository interface {
+	CreatePlan(plan *model.MembershipPlan) error
+	GetPlanByID(id string) (*model.MembershipPlan, error)
+	GetPlans(activeOnly bool) ([]*model.MembershipPlan, error)
+	UpdatePlan(plan *model.MembershipPlan) error
+	GetActiveSubscription(userID string) (*model.UserSubscription, error)
+	Subscribe(subscription *model.UserSubscription) error
+	SaveSubscription(subscription *model.UserSubscription) error
+	SaveRenewal(subscription *model.UserSubscription, previousRenewsAt time.Time) (bool, error)
+	GetDueRenewals(now time.Time, limit int) ([]*model.UserSubscription, error)
+}
+
+type membershipRepository struct {
+	db *gorm.DB
+}
+
+func NewMembershipRepository(db *gorm.DB) MembershipRepository {
+	return &membershipRepository{
+		db: db,
+	}
+}
+
+func (r *membershipRepository) CreatePlan(plan *model.MembershipPlan) error {
+	err := r.db.Create(plan).Error
+	if isDuplicateError(err) {
+		return errors.New("membership plan already exists")
+	}
+
+	return err
+}
+
+func (r *membershipRepository) GetPlanByID(id string) (*model.MembershipPlan, error) {
+	var plan model.MembershipPlan
+
+	if err := r.db.Where("id = ?", id).First(&plan).Error; err != nil {
+		return nil, err
+	}
+
+	return &plan, nil
+}
+
+func (r *membershipRepository) GetPlans(activeOnly bool) ([]*model.MembershipPlan, error) {
+	var plans []*model.MembershipPlan
+
+	tx := r.db.Model(&model.MembershipPlan{})
+	if activeOnly {
+		tx = tx.Where("active = ?", true)
+	}
+
+	if err := tx.Order("monthly_price ASC, name ASC").Find(&plans).Error; err != nil {
+		return nil, err
+	}
+
+	return plans, nil
+}
+
+// UpdatePlan saves a plan and renames the membership status of its subscribers along with it
+func (r *membershipRepository) UpdatePlan(plan *model.MembershipPlan) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(plan).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&model.User{}).
+			Where("id IN (?)", tx.Model(&model.UserSubscription{}).
+				Select("user_id").
+				Where("plan_id = ? AND status = ?", plan.ID, model.SubscriptionStatusActive)).
+			Update("membership_status", plan.Name).Error
+	})
+	if isDuplicateError(err) {
+		return errors.New("membership plan already exists")
+	}
+
+	return err
+}
+
+func (r *membershipRepository) GetActiveSubscription(userID string) (*model.UserSubscription, error) {
+	var subscription model.UserSubscription
+
+	if err := r.db.Preload("Plan").
+		Where("user_id = ? AND status = ?", userID, model.SubscriptionStatusActive).
+		First(&subscription).Error; err != nil {
+		return nil, err
+	}
+
+	return &subscription, nil
+}
+
+// Subscribe ends the active subscription of the user, if any, starts the given one and sets
+// the membership status of the user to the name of the plan
+func (r *membershipRepository) Subscribe(subscription *model.UserSubscription) error {
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.UserSubscription{}).
+			Where("user_id = ? AND status = ?", subscription.UserID, model.SubscriptionStatusActive).
+			Updates(map[string]any{
+				"status":   model.SubscriptionStatusCancelled,
+				"ended_at": subscription.StartedAt,
+			}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Omit("Plan").Create(subscription).Error; err != nil {
+			return err
+		}
+
+		return setMembershipStatus(tx, subscription)
+	})
+	if isDuplicateError(err) {
+		return errors.New("subscription was changed concurrently")
+	}
+
+	return err
+}
+
+// SaveSubscription saves a subscription and updates the membership status of the user when it ended
+func (r *membershipRepository) SaveSubscription(subscription *model.UserSubscription) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Plan").Save(subscription).Error; err != nil {
+			return err
+		}
+
+		return setMembershipStatus(tx, subscription)
+	})
+}
+
+// SaveRenewal saves a subscription moved to a new period, or expired at the end of the last
+// one, unless another worker already did since it was read. It reports whether it was saved
+func (r *membershipRepository) SaveRenewal(subscription *model.UserSubscription, previousRenewsAt time.Time) (bool, error) {
+	var saved bool
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.UserSubscription{}).
+			Where("id = ? AND status = ? AND renews_at = ?", subscription.ID, model.SubscriptionStatusActive, previousRenewsAt).
+			Updates(map[string]any{
+				"status":               subscription.Status,
+				"current_period_start": subscription.CurrentPeriodStart,
+				"renews_at":            subscription.RenewsAt,
+				"ended_at":             subscription.EndedAt,
+				"shots_used":           subscription.ShotsUsed,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return nil
+		}
+
+		saved = true
+
+		return setMembershipStatus(tx, subscription)
+	})
+
+	return saved, err
+}
+
+// GetDueRenewals returns active subscriptions whose period ended by now
+func (r *membershipRepository) GetDueRenewals(now time.Time, limit int) ([]*model.UserSubscription, error) {
+	var subscriptions []*model.UserSubscription
+
+	if err := r.db.Where("status = ? AND renews_at <= ?", model.SubscriptionStatusActive, now).
+		Order("renews_at ASC").
+		Limit(limit).
+		Find(&subscriptions).Error; err != nil {
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+// setMembershipStatus shows the plan of an active subscription as the membership status of its
+// user, and PAYG once the subscription ended
+func setMembershipStatus(tx *gorm.DB, subscription *model.UserSubscription) error {
+	status := model.MembershipPAYG
+
+	if subscription.Status == model.SubscriptionStatusActive {
+		if subscription.Plan.Name == "" {
+			if err := tx.Where("id = ?", subscription.PlanID).First(&subscription.Plan).Error; err != nil {
+				return err
+			}
+		}
+
+		status = subscription.Plan.Name
+	}
+
+	return tx.Model(&model.User{}).
+		Where("id = ?", subscription.UserID).
+		Update("membership_status", status).Error
+}
+
+// useSubscriptionShots counts shots against the current period of an active subscription,
+// failing when the plan does not have that many shots left
+func useSubscriptionShots(tx *gorm.DB, subscriptionID string, shots int) error {
+	result := tx.Model(&model.UserSubscription{}).
+		Where("id = ? AND status = ?", subscriptionID, model.SubscriptionStatusActive).
+		Where("shots_used + ? <= (?)", shots, tx.Model(&model.MembershipPlan{}).
+			Select("included_shots").
+			Where("membership_plans.id = user_subscriptions.plan_id")).
+		Update("shots_used", gorm.Expr("shots_used + ?", shots))
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("membership plan has no shots left")
+	}
+
+	return nil
+}
+
+// releaseSubscriptionShots gives the shots of a cancelled order back to its subscription, unless
+// the subscription moved on to a new period since the order was placed
+func releaseSubscriptionShots(tx *gorm.DB, order *model.Order) error {
+	if order.PlanShots == 0 {
+		return nil
+	}
+
+	return tx.Model(&model.UserSubscription{}).
+		Where("id = ? AND status = ? AND current_period_start <= ?", *order.SubscriptionID, model.SubscriptionStatusActive, order.CreatedAt).
+		Update("shots_used", gorm.Expr("GREATEST(shots_used - ?, 0)", order.PlanShots)).Error
+}
+
+// adjustSubscriptionShots uses up more shots of the subscription of an order when delta is
+// positive, failing when the plan does not have them left, and gives shots back when it is
+// negative. Like releaseSubscriptionShots, shots are only given back to the period the order was
+// placed in, since a new period starts with all of its shots
+func adjustSubscriptionShots(tx *gorm.DB, order *model.Order, delta int) error {
+	if delta > 0 {
+		return useSubscriptionShots(tx, *order.SubscriptionID, delta)
+	}
+
+	return tx.Model(&model.UserSubscription{}).
+		Where("id = ? AND status = ? AND current_period_start <= ?", *order.SubscriptionID, model.SubscriptionStatusActive, order.CreatedAt).
+		Update("shots_used", gorm.Expr("GREATEST(shots_used + ?, 0)", delta)).Error
+}
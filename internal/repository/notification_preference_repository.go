@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"gorm.io/gorm"
+)
+
+type NotificationPreferenceRepository interface {
+	Get(userID string) (*model.NotificationPreference, error)
+	Save(preference *model.NotificationPreference) error
+}
+
+type notificationPreferenceRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationPreferenceRepository(db *gorm.DB) NotificationPreferenceRepository {
+	return &notificationPreferenceRepository{
+		db: db,
+	}
+}
+
+// Get returns the preferences of a user, or the defaults when they never changed them
+func (r *notificationPreferenceRepository) Get(userID string) (*model.NotificationPreference, error) {
+	var preference model.NotificationPreference
+
+	if err := r.db.Where("user_id = ?", userID).First(&preference).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return model.DefaultNotificationPreference(userID), nil
+		}
+
+		return nil, err
+	}
+
+	return &preference, nil
+}
+
+func (r *notificationPreferenceRepository) Save(preference *model.NotificationPreference) error {
+	return r.db.Save(preference).Error
+}
@@ -3,21 +3,39 @@ package repository
 import (
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/MogboPython/belvaphilips_backend/pkg/model"
 	"github.com/MogboPython/belvaphilips_backend/pkg/utils"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type OrderRepository interface {
-	Create(order *model.Order) error
+	Create(order *model.Order, emails []*model.EmailOutbox) error
 	GetByOrderID(orderID string) (*model.Order, error)
 	GetByUserID(userID string, offset, limit int) ([]*model.Order, error)
-	Update(orderID, status string) (*model.Order, error)
-	GetAll(offset, limit int, status string) ([]*model.Order, model.OrdersCount, error)
-	// Delete(id int64) error
+	GetByUserIDWithDeleted(userID string) ([]*model.Order, error)
+	GetVisibleToUser(userID string, offset, limit int) ([]*model.Order, error)
+	GetByOrganizationID(organizationID string, offset, limit int) ([]*model.Order, error)
+	UpdateStatus(order *model.Order, history *model.OrderStatusHistory, emails []*model.EmailOutbox) error
+	GetStatusHistory(orderID string) ([]*model.OrderStatusHistory, error)
+	GetStatusEmailSettings() ([]*model.OrderStatusEmailSetting, error)
+	GetStatusEmailSetting(status string) (*model.OrderStatusEmailSetting, error)
+	SaveStatusEmailSetting(setting *model.OrderStatusEmailSetting) error
+	CreateQuote(order *model.Order, quote *model.Quote, history *model.OrderStatusHistory, emails []*model.EmailOutbox) error
+	GetLatestQuote(orderID string) (*model.Quote, error)
+	RespondToQuote(order *model.Order, quote *model.Quote, history *model.OrderStatusHistory, emails []*model.EmailOutbox) error
+	GetAll(offset, limit int, filter *model.OrderFilter) ([]*model.Order, model.OrdersCount, error)
+	GetAllInBatches(filter *model.OrderFilter, batchSize int, fn func([]*model.Order) error) error
+	GetDetailKeys(filter *model.OrderFilter) ([]string, error)
+	Cancel(order *model.Order, history *model.OrderStatusHistory, emails []*model.EmailOutbox) error
+	Revise(order *model.Order, revision *model.OrderRevision, shotsDelta int, emails []*model.EmailOutbox) error
+	GetRevisions(orderID string) ([]*model.OrderRevision, error)
+	Delete(id string) error
+	Restore(id string) (*model.Order, error)
 }
 
 type orderRepository struct {
@@ -30,7 +48,9 @@ func NewOrderRepository(db *gorm.DB) OrderRepository {
 	}
 }
 
-func (r *orderRepository) Create(order *model.Order) error {
+// Create saves a new order and queues the given emails in the same transaction. An order placed
+// under a subscription uses up its plan shots, and is refused when the plan does not have them left
+func (r *orderRepository) Create(order *model.Order, emails []*model.EmailOutbox) error {
 	exists, err := utils.ExistsByID(r.db, &model.User{}, order.UserID)
 	if err != nil {
 		return err
@@ -47,7 +67,27 @@ func (r *orderRepository) Create(order *model.Order) error {
 
 	order.OrderName = orderName
 
-	err = r.db.Create(&order).Error
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		if order.SubscriptionID != nil {
+			if err := useSubscriptionShots(tx, *order.SubscriptionID, order.PlanShots); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Create(&order).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Create(&model.OrderStatusHistory{
+			OrderID:   order.ID,
+			ToStatus:  order.Status,
+			ChangedBy: order.UserID,
+		}).Error; err != nil {
+			return err
+		}
+
+		return enqueueEmails(tx, emails)
+	})
 	if err != nil {
 		return err
 	}
@@ -55,6 +95,14 @@ func (r *orderRepository) Create(order *model.Order) error {
 	return r.db.Model(&order).Association("User").Find(&order.User)
 }
 
+func enqueueEmails(tx *gorm.DB, emails []*model.EmailOutbox) error {
+	if len(emails) == 0 {
+		return nil
+	}
+
+	return tx.Create(&emails).Error
+}
+
 func (*orderRepository) generateUniqueOrderName() (string, error) {
 	now := time.Now()
 	date := now.Format("20060102")
@@ -77,41 +125,39 @@ func (r *orderRepository) GetByOrderID(orderID string) (*model.Order, error) {
 	return &order, nil
 }
 
-func (r *orderRepository) GetAll(offset, limit int, status string) ([]*model.Order, model.OrdersCount, error) {
+// The groups of statuses the order list can be filtered by
+const (
+	orderStatusActive    = "quote_received"
+	orderStatusCompleted = "mark_completed"
+)
+
+// GetAll returns a page of the orders matching filter and counts them. The counts by status group
+// ignore the status filter so they stay the same while switching between groups
+func (r *orderRepository) GetAll(offset, limit int, filter *model.OrderFilter) ([]*model.Order, model.OrdersCount, error) {
 	var orders []*model.Order
 
 	var count model.OrdersCount
 
-	tx := r.db.Model(&model.Order{})
-
-	const (
-		statusActive    = "quote_received"
-		statusCompleted = "mark_completed"
-	)
-
-	switch status {
-	case "active":
-		tx = tx.Where("status = ?", statusActive)
-	case "completed":
-		tx = tx.Where("status = ?", statusCompleted)
-	case "pending":
-		tx = tx.Where("status != ? AND status != ?", statusActive, statusCompleted)
-	}
-
-	if err := tx.Preload("User").Offset(offset).Limit(limit).Find(&orders).Error; err != nil {
+	if err := sortOrders(filterOrdersByStatus(r.db, filter), filter).Preload("User").
+		Offset(offset).Limit(limit).
+		Find(&orders).Error; err != nil {
 		return nil, count, err
 	}
 
 	if err := r.db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Model(&model.Order{}).Count(&count.Total).Error; err != nil {
+		if err := filterOrdersByStatus(tx, filter).Count(&count.Matching).Error; err != nil {
+			return err
+		}
+
+		if err := filterOrders(tx.Model(&model.Order{}), filter).Count(&count.Total).Error; err != nil {
 			return err
 		}
 
-		if err := tx.Model(&model.Order{}).Where("status = ?", statusActive).Count(&count.ActiveCount).Error; err != nil {
+		if err := filterOrders(tx.Model(&model.Order{}), filter).Where("orders.status = ?", orderStatusActive).Count(&count.ActiveCount).Error; err != nil {
 			return err
 		}
 
-		if err := tx.Model(&model.Order{}).Where("status = ?", statusCompleted).Count(&count.CompletedCount).Error; err != nil {
+		if err := filterOrders(tx.Model(&model.Order{}), filter).Where("orders.status = ?", orderStatusCompleted).Count(&count.CompletedCount).Error; err != nil {
 			return err
 		}
 
@@ -124,6 +170,158 @@ func (r *orderRepository) GetAll(offset, limit int, status string) ([]*model.Ord
 	return orders, count, nil
 }
 
+// GetAllInBatches calls fn with the orders matching filter, batchSize of them at a time, so an
+// export never holds the whole table in memory. Each batch starts after the last order of the
+// previous one in the sort order rather than at an offset, so orders created or deleted during the
+// export can neither be skipped nor repeated. It stops at the first error fn returns
+func (r *orderRepository) GetAllInBatches(filter *model.OrderFilter, batchSize int, fn func([]*model.Order) error) error {
+	var last *model.Order
+
+	for {
+		var orders []*model.Order
+
+		tx := sortOrders(filterOrdersByStatus(r.db, filter), filter).Preload("User").Limit(batchSize)
+		if last != nil {
+			tx = tx.Where(afterOrder(filter, last))
+		}
+
+		if err := tx.Find(&orders).Error; err != nil {
+			return err
+		}
+
+		if len(orders) > 0 {
+			if err := fn(orders); err != nil {
+				return err
+			}
+
+			last = orders[len(orders)-1]
+		}
+
+		if len(orders) < batchSize {
+			return nil
+		}
+	}
+}
+
+// afterOrder matches the orders that come after order in the sort order of filter
+func afterOrder(filter *model.OrderFilter, order *model.Order) clause.Expr {
+	column := clause.Column{Table: "orders", Name: filter.Sort}
+	value := orderSortValue(order, filter.Sort)
+
+	if filter.Descending {
+		return gorm.Expr("? < ? OR (? = ? AND orders.id > ?)", column, value, column, value, order.ID)
+	}
+
+	return gorm.Expr("(?, orders.id) > (?, ?)", column, value, order.ID)
+}
+
+// orderSortValue returns the value of the sort column of order
+func orderSortValue(order *model.Order, column string) any {
+	switch column {
+	case "updated_at":
+		return order.UpdatedAt
+	case "order_name":
+		return order.OrderName
+	case "product_name":
+		return order.ProductName
+	case "status":
+		return order.Status
+	case "quantity":
+		return order.Quantity
+	case "quoted_amount":
+		return order.QuotedAmount
+	default:
+		return order.CreatedAt
+	}
+}
+
+// GetDetailKeys returns the keys used in the details of the orders matching filter, sorted
+func (r *orderRepository) GetDetailKeys(filter *model.OrderFilter) ([]string, error) {
+	var keys []string
+
+	orders := filterOrdersByStatus(r.db, filter).
+		Select("orders.details").
+		Where("jsonb_typeof(orders.details) = 'object'")
+
+	if err := r.db.Table("(?) AS filtered", orders).
+		Distinct("jsonb_object_keys(filtered.details) AS key").
+		Order("key").
+		Pluck("key", &keys).Error; err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// filterOrdersByStatus applies every condition of filter, including the status or status group
+func filterOrdersByStatus(tx *gorm.DB, filter *model.OrderFilter) *gorm.DB {
+	tx = filterOrders(tx.Model(&model.Order{}), filter)
+
+	switch filter.Status {
+	case "":
+	case "active":
+		tx = tx.Where("orders.status = ?", orderStatusActive)
+	case "completed":
+		tx = tx.Where("orders.status = ?", orderStatusCompleted)
+	case "pending":
+		tx = tx.Where("orders.status != ? AND orders.status != ?", orderStatusActive, orderStatusCompleted)
+	case "deleted":
+		// Deleted orders are left out of every other filter and of the counts
+		tx = tx.Unscoped().Where("orders.deleted_at IS NOT NULL")
+	default:
+		tx = tx.Where("orders.status = ?", filter.Status)
+	}
+
+	return tx
+}
+
+// sortOrders orders by the sort column of filter, breaking ties by ID so pages never overlap
+func sortOrders(tx *gorm.DB, filter *model.OrderFilter) *gorm.DB {
+	return tx.Order(clause.OrderByColumn{Column: clause.Column{Table: "orders", Name: filter.Sort}, Desc: filter.Descending}).
+		Order("orders.id")
+}
+
+// filterOrders applies every condition of filter other than the status and the sort order
+func filterOrders(tx *gorm.DB, filter *model.OrderFilter) *gorm.DB {
+	if filter.UserID != "" {
+		tx = tx.Where("orders.user_id = ?", filter.UserID)
+	}
+
+	if filter.ShootType != "" {
+		tx = tx.Where("orders.shoot_type ILIKE ?", escapeLike(filter.ShootType))
+	}
+
+	if filter.DeliverySpeed != "" {
+		tx = tx.Where("orders.delivery_speed ILIKE ?", escapeLike(filter.DeliverySpeed))
+	}
+
+	if filter.MembershipType != "" {
+		tx = tx.Where("orders.membership_type ILIKE ?", escapeLike(filter.MembershipType))
+	}
+
+	if filter.CreatedFrom != nil {
+		tx = tx.Where("orders.created_at >= ?", *filter.CreatedFrom)
+	}
+
+	if filter.CreatedTo != nil {
+		tx = tx.Where("orders.created_at < ?", *filter.CreatedTo)
+	}
+
+	if filter.Search != "" {
+		pattern := "%" + escapeLike(filter.Search) + "%"
+		customers := tx.Session(&gorm.Session{NewDB: true}).Model(&model.User{}).Select("id").Where("email ILIKE ?", pattern)
+
+		tx = tx.Where("(orders.order_name ILIKE ? OR orders.product_name ILIKE ? OR orders.user_id IN (?))", pattern, pattern, customers)
+	}
+
+	return tx
+}
+
+// escapeLike escapes the wildcards of a LIKE pattern so value only matches itself
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
 func (r *orderRepository) GetByUserID(userID string, offset, limit int) ([]*model.Order, error) {
 	var orders []*model.Order
 
@@ -134,19 +332,289 @@ func (r *orderRepository) GetByUserID(userID string, offset, limit int) ([]*mode
 	return orders, nil
 }
 
-func (r *orderRepository) Update(orderID, status string) (*model.Order, error) {
-	var order model.Order
+// GetByUserIDWithDeleted returns every order a user placed, including the deleted ones, for the
+// export of their data
+func (r *orderRepository) GetByUserIDWithDeleted(userID string) ([]*model.Order, error) {
+	var orders []*model.Order
 
-	if err := r.db.Preload("User").Where("id = ?", orderID).First(&order).Error; err != nil {
+	if err := r.db.Unscoped().Preload("User").Where("user_id = ?", userID).Order("created_at ASC").Find(&orders).Error; err != nil {
 		return nil, err
 	}
 
-	order.Status = status
-	order.UpdatedAt = time.Now()
+	return orders, nil
+}
+
+// GetVisibleToUser returns the orders a user placed together with the orders of every
+// organization they are a member of
+func (r *orderRepository) GetVisibleToUser(userID string, offset, limit int) ([]*model.Order, error) {
+	var orders []*model.Order
 
-	if err := r.db.Save(&order).Error; err != nil {
+	memberships := r.db.Model(&model.OrganizationMember{}).Select("organization_id").Where("user_id = ?", userID)
+
+	if err := r.db.Preload("User").
+		Where("user_id = ? OR organization_id IN (?)", userID, memberships).
+		Order("created_at DESC").
+		Offset(offset).Limit(limit).
+		Find(&orders).Error; err != nil {
 		return nil, err
 	}
 
-	return &order, nil
+	return orders, nil
+}
+
+func (r *orderRepository) GetByOrganizationID(organizationID string, offset, limit int) ([]*model.Order, error) {
+	var orders []*model.Order
+
+	if err := r.db.Preload("User").
+		Where("organization_id = ?", organizationID).
+		Order("created_at DESC").
+		Offset(offset).Limit(limit).
+		Find(&orders).Error; err != nil {
+		return nil, err
+	}
+
+	return orders, nil
+}
+
+// UpdateStatus saves the new status of an order together with its history entry and queued emails
+func (r *orderRepository) UpdateStatus(order *model.Order, history *model.OrderStatusHistory, emails []*model.EmailOutbox) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return applyStatusChange(tx, order, history, emails, nil)
+	})
+}
+
+// applyStatusChange moves an order to history.ToStatus, along with any extra column updates,
+// records the history entry, queues the emails and reloads the order
+func applyStatusChange(tx *gorm.DB, order *model.Order, history *model.OrderStatusHistory, emails []*model.EmailOutbox, updates map[string]any) error {
+	if updates == nil {
+		updates = map[string]any{}
+	}
+
+	updates["status"] = history.ToStatus
+	updates["updated_at"] = time.Now()
+
+	result := tx.Model(&model.Order{}).
+		Where("id = ? AND status = ?", order.ID, history.FromStatus).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	// Another request changed the status since the order was read
+	if result.RowsAffected == 0 {
+		return errors.New("order status was changed concurrently")
+	}
+
+	if history.ToStatus == model.OrderStatusCancelled && order.SubscriptionID != nil {
+		if err := releaseSubscriptionShots(tx, order); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Create(history).Error; err != nil {
+		return err
+	}
+
+	if err := enqueueEmails(tx, emails); err != nil {
+		return err
+	}
+
+	return tx.Preload("User").Where("id = ?", order.ID).First(order).Error
+}
+
+// Cancel moves an order to cancelled with the reason given, records the history entry, voids its
+// open invoice and queues the emails. Shots the order used up from a membership plan are given
+// back. Orders with a paid invoice are refused, they have to be refunded by staff
+func (r *orderRepository) Cancel(order *model.Order, history *model.OrderStatusHistory, emails []*model.EmailOutbox) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var paid int64
+		if err := tx.Model(&model.Invoice{}).
+			Where("order_id = ? AND status = ?", order.ID, model.InvoiceStatusPaid).
+			Count(&paid).Error; err != nil {
+			return err
+		}
+
+		if paid > 0 {
+			return errors.New("order has a paid invoice")
+		}
+
+		if err := applyStatusChange(tx, order, history, emails, map[string]any{
+			"cancellation_reason": order.CancellationReason,
+		}); err != nil {
+			return err
+		}
+
+		return tx.Model(&model.Invoice{}).
+			Where("order_id = ? AND status IN ?", order.ID, []string{model.InvoiceStatusDraft, model.InvoiceStatusIssued}).
+			Updates(map[string]any{"status": model.InvoiceStatusVoid, "updated_at": time.Now()}).Error
+	})
+}
+
+// Revise saves the edited options of an order that was not quoted yet, records the revision and
+// queues the emails. shotsDelta is the change in shots the order uses up from its membership plan
+func (r *orderRepository) Revise(order *model.Order, revision *model.OrderRevision, shotsDelta int, emails []*model.EmailOutbox) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Order{}).
+			Where("id = ? AND status = ?", order.ID, model.OrderStatusQuoteReceived).
+			Updates(map[string]any{
+				"finish_type": order.FinishType,
+				"details":     order.Details,
+				"shots":       order.Shots,
+				"quantity":    order.Quantity,
+				"plan_shots":  order.PlanShots,
+				"updated_at":  time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return errors.New("order status was changed concurrently")
+		}
+
+		if order.SubscriptionID != nil && shotsDelta != 0 {
+			if err := adjustSubscriptionShots(tx, order, shotsDelta); err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Create(revision).Error; err != nil {
+			return err
+		}
+
+		if err := enqueueEmails(tx, emails); err != nil {
+			return err
+		}
+
+		return tx.Preload("User").Where("id = ?", order.ID).First(order).Error
+	})
+}
+
+func (r *orderRepository) GetRevisions(orderID string) ([]*model.OrderRevision, error) {
+	var revisions []*model.OrderRevision
+
+	if err := r.db.Where("order_id = ?", orderID).Order("created_at ASC").Find(&revisions).Error; err != nil {
+		return nil, err
+	}
+
+	return revisions, nil
+}
+
+// Delete hides an order until it is restored
+func (r *orderRepository) Delete(id string) error {
+	result := r.db.Where("id = ?", id).Delete(&model.Order{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// Restore brings back a deleted order
+func (r *orderRepository) Restore(id string) (*model.Order, error) {
+	result := r.db.Unscoped().Model(&model.Order{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return r.GetByOrderID(id)
+}
+
+func (r *orderRepository) GetStatusHistory(orderID string) ([]*model.OrderStatusHistory, error) {
+	var history []*model.OrderStatusHistory
+
+	if err := r.db.Where("order_id = ?", orderID).Order("created_at ASC").Find(&history).Error; err != nil {
+		return nil, err
+	}
+
+	return history, nil
+}
+
+func (r *orderRepository) GetStatusEmailSettings() ([]*model.OrderStatusEmailSetting, error) {
+	var settings []*model.OrderStatusEmailSetting
+
+	if err := r.db.Order("status ASC").Find(&settings).Error; err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+func (r *orderRepository) GetStatusEmailSetting(status string) (*model.OrderStatusEmailSetting, error) {
+	var setting model.OrderStatusEmailSetting
+
+	if err := r.db.Where("status = ?", status).First(&setting).Error; err != nil {
+		return nil, err
+	}
+
+	return &setting, nil
+}
+
+func (r *orderRepository) SaveStatusEmailSetting(setting *model.OrderStatusEmailSetting) error {
+	return r.db.Save(setting).Error
+}
+
+// CreateQuote supersedes any pending quote of the order, saves the new one and moves the order along
+func (r *orderRepository) CreateQuote(order *model.Order, quote *model.Quote, history *model.OrderStatusHistory, emails []*model.EmailOutbox) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.Quote{}).
+			Where("order_id = ? AND status = ?", order.ID, model.QuoteStatusPending).
+			Update("status", model.QuoteStatusSuperseded).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Create(quote).Error; err != nil {
+			return err
+		}
+
+		return applyStatusChange(tx, order, history, emails, map[string]any{
+			"quoted_amount": quote.Total,
+			"currency":      quote.Currency,
+		})
+	})
+}
+
+func (r *orderRepository) GetLatestQuote(orderID string) (*model.Quote, error) {
+	var quote model.Quote
+
+	if err := r.db.Preload("LineItems", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position ASC")
+	}).Where("order_id = ?", orderID).Order("created_at DESC").First(&quote).Error; err != nil {
+		return nil, err
+	}
+
+	return &quote, nil
+}
+
+// RespondToQuote saves the customer's answer to a pending quote and moves the order along
+func (r *orderRepository) RespondToQuote(order *model.Order, quote *model.Quote, history *model.OrderStatusHistory, emails []*model.EmailOutbox) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.Quote{}).
+			Where("id = ? AND status = ?", quote.ID, model.QuoteStatusPending).
+			Updates(map[string]any{
+				"status":           quote.Status,
+				"rejection_reason": quote.RejectionReason,
+				"responded_at":     quote.RespondedAt,
+				"updated_at":       time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return errors.New("order status was changed concurrently")
+		}
+
+		return applyStatusChange(tx, order, history, emails, nil)
+	})
 }
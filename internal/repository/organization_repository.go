@@ -0,0 +1,274 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OrganizationRepository interface {
+	Create(organization *model.Organization) error
+	GetByID(id string) (*model.Organization, error)
+	Update(organization *model.Organization) error
+	GetMemberships(userID string) ([]*model.OrganizationMember, error)
+	GetMember(organizationID, userID string) (*model.OrganizationMember, error)
+	GetMembers(organizationID string) ([]*model.OrganizationMember, error)
+	IsMember(organizationID, userID string) (bool, error)
+	UpdateMemberRole(organizationID, userID, role string) error
+	RemoveMember(organizationID, userID string) error
+	CreateInvitation(invitation *model.OrganizationInvitation, emails []*model.EmailOutbox) error
+	GetPendingInvitations(organizationID string) ([]*model.OrganizationInvitation, error)
+	GetInvitationByHash(tokenHash string) (*model.OrganizationInvitation, error)
+	DeleteInvitation(organizationID, id string) error
+	AcceptInvitation(invitation *model.OrganizationInvitation, userID string) error
+}
+
+type organizationRepository struct {
+	db *gorm.DB
+}
+
+func NewOrganizationRepository(db *gorm.DB) OrganizationRepository {
+	return &organizationRepository{
+		db: db,
+	}
+}
+
+// Create saves a new organization with its creator as the first owner
+func (r *organizationRepository) Create(organization *model.Organization) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(organization).Error; err != nil {
+			return err
+		}
+
+		return tx.Omit("Organization", "User").Create(&model.OrganizationMember{
+			OrganizationID: organization.ID,
+			UserID:         organization.CreatedBy,
+			Role:           model.OrganizationRoleOwner,
+		}).Error
+	})
+}
+
+func (r *organizationRepository) GetByID(id string) (*model.Organization, error) {
+	var organization model.Organization
+
+	if err := r.db.Where("id = ?", id).First(&organization).Error; err != nil {
+		return nil, err
+	}
+
+	return &organization, nil
+}
+
+func (r *organizationRepository) Update(organization *model.Organization) error {
+	return r.db.Save(organization).Error
+}
+
+// GetMemberships returns the organizations a user belongs to with the role they hold in each
+func (r *organizationRepository) GetMemberships(userID string) ([]*model.OrganizationMember, error) {
+	var members []*model.OrganizationMember
+
+	if err := r.db.Preload("Organization").
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Find(&members).Error; err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+func (r *organizationRepository) GetMember(organizationID, userID string) (*model.OrganizationMember, error) {
+	var member model.OrganizationMember
+
+	if err := r.db.Preload("User").
+		Where("organization_id = ? AND user_id = ?", organizationID, userID).
+		First(&member).Error; err != nil {
+		return nil, err
+	}
+
+	return &member, nil
+}
+
+func (r *organizationRepository) GetMembers(organizationID string) ([]*model.OrganizationMember, error) {
+	var members []*model.OrganizationMember
+
+	if err := r.db.Preload("User").
+		Where("organization_id = ?", organizationID).
+		Order("created_at ASC").
+		Find(&members).Error; err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+func (r *organizationRepository) IsMember(organizationID, userID string) (bool, error) {
+	if !isUUID(organizationID) || !isUUID(userID) {
+		return false, nil
+	}
+
+	var count int64
+
+	if err := r.db.Model(&model.OrganizationMember{}).
+		Where("organization_id = ? AND user_id = ?", organizationID, userID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// UpdateMemberRole changes the role of a member as long as the organization keeps an owner
+func (r *organizationRepository) UpdateMemberRole(organizationID, userID, role string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockOrganization(tx, organizationID); err != nil {
+			return err
+		}
+
+		result := tx.Model(&model.OrganizationMember{}).
+			Where("organization_id = ? AND user_id = ?", organizationID, userID).
+			Update("role", role)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return ensureOrganizationOwner(tx, organizationID)
+	})
+}
+
+// RemoveMember takes a user out of an organization as long as the organization keeps an owner.
+// Orders the member placed stay with the organization
+func (r *organizationRepository) RemoveMember(organizationID, userID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := lockOrganization(tx, organizationID); err != nil {
+			return err
+		}
+
+		result := tx.Where("organization_id = ? AND user_id = ?", organizationID, userID).
+			Delete(&model.OrganizationMember{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return ensureOrganizationOwner(tx, organizationID)
+	})
+}
+
+// CreateInvitation saves an invitation and queues the given emails in the same transaction
+func (r *organizationRepository) CreateInvitation(invitation *model.OrganizationInvitation, emails []*model.EmailOutbox) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Organization").Create(invitation).Error; err != nil {
+			return err
+		}
+
+		return enqueueEmails(tx, emails)
+	})
+}
+
+func (r *organizationRepository) GetPendingInvitations(organizationID string) ([]*model.OrganizationInvitation, error) {
+	var invitations []*model.OrganizationInvitation
+
+	if err := r.db.Where("organization_id = ? AND accepted_at IS NULL AND expires_at > ?", organizationID, time.Now()).
+		Order("created_at DESC").
+		Find(&invitations).Error; err != nil {
+		return nil, err
+	}
+
+	return invitations, nil
+}
+
+func (r *organizationRepository) GetInvitationByHash(tokenHash string) (*model.OrganizationInvitation, error) {
+	var invitation model.OrganizationInvitation
+
+	if err := r.db.Preload("Organization").Where("token_hash = ?", tokenHash).First(&invitation).Error; err != nil {
+		return nil, err
+	}
+
+	return &invitation, nil
+}
+
+func (r *organizationRepository) DeleteInvitation(organizationID, id string) error {
+	result := r.db.Where("organization_id = ? AND id = ? AND accepted_at IS NULL", organizationID, id).
+		Delete(&model.OrganizationInvitation{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// AcceptInvitation marks an invitation as used and adds the user to its organization. An
+// invitation can only be accepted once even when two requests race
+func (r *organizationRepository) AcceptInvitation(invitation *model.OrganizationInvitation, userID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		result := tx.Model(&model.OrganizationInvitation{}).
+			Where("id = ? AND accepted_at IS NULL", invitation.ID).
+			Update("accepted_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return errors.New("invitation was already used")
+		}
+
+		member := &model.OrganizationMember{
+			OrganizationID: invitation.OrganizationID,
+			UserID:         userID,
+			Role:           invitation.Role,
+		}
+
+		if err := tx.Omit("Organization", "User").Create(member).Error; err != nil {
+			if isDuplicateError(err) {
+				return errors.New("user is already a member of the organization")
+			}
+
+			return err
+		}
+
+		invitation.AcceptedAt = &now
+
+		return nil
+	})
+}
+
+// lockOrganization locks the organization row until the end of tx, so concurrent changes to its
+// members are applied one after the other and each sees the owners left by the previous one
+func lockOrganization(tx *gorm.DB, organizationID string) error {
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", organizationID).
+		First(&model.Organization{}).Error
+}
+
+// ensureOrganizationOwner fails when an organization would be left without an owner to manage it
+func ensureOrganizationOwner(tx *gorm.DB, organizationID string) error {
+	var count int64
+
+	if err := tx.Model(&model.OrganizationMember{}).
+		Where("organization_id = ? AND role = ?", organizationID, model.OrganizationRoleOwner).
+		Count(&count).Error; err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return errors.New("organization would be left without an owner")
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OutboxRepository interface {
+	ClaimDue(limit int, lease time.Duration) ([]*model.EmailOutbox, error)
+	Save(email *model.EmailOutbox) error
+	GetAll(offset, limit int, status string) ([]*model.EmailOutbox, int64, error)
+	Requeue(id string) (*model.EmailOutbox, error)
+}
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepository{
+		db: db,
+	}
+}
+
+// ClaimDue locks pending emails that are due and pushes their next attempt back by lease,
+// so other workers skip them while they are being sent
+func (r *outboxRepository) ClaimDue(limit int, lease time.Duration) ([]*model.EmailOutbox, error) {
+	var emails []*model.EmailOutbox
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", model.OutboxStatusPending, now).
+			Order("next_attempt_at ASC").
+			Limit(limit).
+			Find(&emails).Error; err != nil {
+			return err
+		}
+
+		if len(emails) == 0 {
+			return nil
+		}
+
+		ids := make([]string, len(emails))
+		for i, email := range emails {
+			ids[i] = email.ID
+		}
+
+		return tx.Model(&model.EmailOutbox{}).
+			Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(lease)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return emails, nil
+}
+
+func (r *outboxRepository) Save(email *model.EmailOutbox) error {
+	return r.db.Save(email).Error
+}
+
+func (r *outboxRepository) GetAll(offset, limit int, status string) ([]*model.EmailOutbox, int64, error) {
+	var emails []*model.EmailOutbox
+
+	var count int64
+
+	tx := r.db.Model(&model.EmailOutbox{})
+	if status != "" {
+		tx = tx.Where("status = ?", status)
+	}
+
+	if err := tx.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := tx.Order("created_at DESC").Offset(offset).Limit(limit).Find(&emails).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return emails, count, nil
+}
+
+// Requeue moves a dead email back to pending with a fresh set of attempts
+func (r *outboxRepository) Requeue(id string) (*model.EmailOutbox, error) {
+	var email model.EmailOutbox
+
+	if err := r.db.Where("id = ?", id).First(&email).Error; err != nil {
+		return nil, err
+	}
+
+	if email.Status != model.OutboxStatusDead {
+		return nil, errors.New("only failed emails can be resent")
+	}
+
+	if email.Sensitive {
+		return nil, errors.New("message content was removed and cannot be resent")
+	}
+
+	email.Status = model.OutboxStatusPending
+	email.Attempts = 0
+	email.LastError = ""
+	email.NextAttemptAt = time.Now()
+
+	if err := r.db.Save(&email).Error; err != nil {
+		return nil, err
+	}
+
+	return &email, nil
+}
@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"gorm.io/gorm"
+)
+
+type PaymentRepository interface {
+	Create(payment *model.Payment) error
+	GetByID(id string) (*model.Payment, error)
+	GetByReference(reference string) (*model.Payment, error)
+	GetByInvoiceID(invoiceID string) ([]*model.Payment, error)
+	Update(payment *model.Payment) error
+	MarkSucceeded(payment *model.Payment, invoice *model.Invoice, history *model.OrderStatusHistory, emails []*model.EmailOutbox) error
+	MarkFailed(payment *model.Payment) error
+	Refund(payment *model.Payment, amount int64, refund func() error) error
+}
+
+type paymentRepository struct {
+	db *gorm.DB
+}
+
+func NewPaymentRepository(db *gorm.DB) PaymentRepository {
+	return &paymentRepository{
+		db: db,
+	}
+}
+
+func (r *paymentRepository) Create(payment *model.Payment) error {
+	if err := r.db.Create(payment).Error; err != nil {
+		if isDuplicateError(err) {
+			return errors.New("invoice already has a pending payment")
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (r *paymentRepository) GetByID(id string) (*model.Payment, error) {
+	var payment model.Payment
+
+	if err := r.db.Where("id = ?", id).First(&payment).Error; err != nil {
+		return nil, err
+	}
+
+	return &payment, nil
+}
+
+func (r *paymentRepository) GetByReference(reference string) (*model.Payment, error) {
+	var payment model.Payment
+
+	if err := r.db.Where("reference = ?", reference).First(&payment).Error; err != nil {
+		return nil, err
+	}
+
+	return &payment, nil
+}
+
+func (r *paymentRepository) GetByInvoiceID(invoiceID string) ([]*model.Payment, error) {
+	var payments []*model.Payment
+
+	if err := r.db.Where("invoice_id = ?", invoiceID).Order("created_at DESC").Find(&payments).Error; err != nil {
+		return nil, err
+	}
+
+	return payments, nil
+}
+
+func (r *paymentRepository) Update(payment *model.Payment) error {
+	return r.db.Save(payment).Error
+}
+
+// MarkSucceeded settles a pending payment. In the same transaction it saves the invoice when it is
+// given and, when history is given, advances the order of the invoice and queues its emails
+func (r *paymentRepository) MarkSucceeded(payment *model.Payment, invoice *model.Invoice, history *model.OrderStatusHistory, emails []*model.EmailOutbox) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		if err := settlePayment(tx, payment, map[string]any{
+			"status":  model.PaymentStatusSucceeded,
+			"paid_at": now,
+		}); err != nil {
+			return err
+		}
+
+		payment.Status = model.PaymentStatusSucceeded
+		payment.PaidAt = &now
+
+		if invoice == nil {
+			return nil
+		}
+
+		// The invoice may have been voided since it was read, by a cancellation for instance
+		result := tx.Model(&model.Invoice{}).
+			Where("id = ? AND status = ?", invoice.ID, model.InvoiceStatusIssued).
+			Updates(map[string]any{
+				"status":  invoice.Status,
+				"paid_at": invoice.PaidAt,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return errors.New("invoice is no longer issued")
+		}
+
+		if history == nil {
+			return nil
+		}
+
+		return applyStatusChange(tx, &invoice.Order, history, emails, nil)
+	})
+}
+
+func (r *paymentRepository) MarkFailed(payment *model.Payment) error {
+	if err := settlePayment(r.db, payment, map[string]any{
+		"status":         model.PaymentStatusFailed,
+		"failure_reason": payment.FailureReason,
+	}); err != nil {
+		return err
+	}
+
+	payment.Status = model.PaymentStatusFailed
+
+	return nil
+}
+
+// settlePayment moves a payment out of pending, failing when a concurrent webhook got there first
+func settlePayment(tx *gorm.DB, payment *model.Payment, updates map[string]any) error {
+	updates["updated_at"] = time.Now()
+
+	result := tx.Model(&model.Payment{}).
+		Where("id = ? AND status = ?", payment.ID, model.PaymentStatusPending).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.New("payment was already settled")
+	}
+
+	return nil
+}
+
+// Refund records a refund of amount on a successful payment and calls refund to have the provider
+// pay it back, in one transaction. The payment is claimed first so concurrent refunds cannot both
+// reach the provider, and a full refund voids the invoice the payment settled
+func (r *paymentRepository) Refund(payment *model.Payment, amount int64, refund func() error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		refunded := payment.RefundedAmount + amount
+
+		status := model.PaymentStatusSucceeded
+		if refunded == payment.Amount {
+			status = model.PaymentStatusRefunded
+		}
+
+		result := tx.Model(&model.Payment{}).
+			Where("id = ? AND status = ? AND refunded_amount = ?", payment.ID, model.PaymentStatusSucceeded, payment.RefundedAmount).
+			Updates(map[string]any{
+				"refunded_amount": refunded,
+				"status":          status,
+				"updated_at":      time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return errors.New("payment was refunded concurrently")
+		}
+
+		if status == model.PaymentStatusRefunded {
+			if err := tx.Model(&model.Invoice{}).
+				Where("id = ? AND status = ?", payment.InvoiceID, model.InvoiceStatusPaid).
+				Updates(map[string]any{"status": model.InvoiceStatusVoid, "updated_at": time.Now()}).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := refund(); err != nil {
+			return err
+		}
+
+		payment.RefundedAmount = refunded
+		payment.Status = status
+
+		return nil
+	})
+}
@@ -0,0 +1,236 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"gorm.io/gorm"
+)
+
+type RoleRepository interface {
+	GetPermissions() ([]*model.Permission, error)
+	Create(role *model.Role) error
+	GetByID(id string) (*model.Role, error)
+	GetByName(name string) (*model.Role, error)
+	GetAll() ([]*model.Role, error)
+	Update(role *model.Role) error
+	Delete(id string) error
+	GetAdminRoles(adminID string) ([]*model.Role, error)
+	SetAdminRoles(adminID string, roleIDs []string) error
+	HasPermissions(adminID string, permissions ...string) (bool, error)
+	MustChangePassword(adminID string) (bool, error)
+}
+
+type roleRepository struct {
+	db *gorm.DB
+}
+
+func NewRoleRepository(db *gorm.DB) RoleRepository {
+	return &roleRepository{
+		db: db,
+	}
+}
+
+func (r *roleRepository) GetPermissions() ([]*model.Permission, error) {
+	var permissions []*model.Permission
+
+	if err := r.db.Order("key ASC").Find(&permissions).Error; err != nil {
+		return nil, err
+	}
+
+	return permissions, nil
+}
+
+func (r *roleRepository) Create(role *model.Role) error {
+	if err := r.db.Create(role).Error; err != nil {
+		if isDuplicateError(err) {
+			return errors.New("role already exists")
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+func (r *roleRepository) GetByID(id string) (*model.Role, error) {
+	var role model.Role
+
+	if err := r.db.Preload("Permissions").Where("id = ?", id).First(&role).Error; err != nil {
+		return nil, err
+	}
+
+	return &role, nil
+}
+
+func (r *roleRepository) GetByName(name string) (*model.Role, error) {
+	var role model.Role
+
+	if err := r.db.Preload("Permissions").Where("name = ?", name).First(&role).Error; err != nil {
+		return nil, err
+	}
+
+	return &role, nil
+}
+
+func (r *roleRepository) GetAll() ([]*model.Role, error) {
+	var roles []*model.Role
+
+	if err := r.db.Preload("Permissions").Order("name ASC").Find(&roles).Error; err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+// Update saves a role and replaces its permissions. The change is rolled back when it would
+// leave nobody able to manage staff
+func (r *roleRepository) Update(role *model.Role) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Permissions").Save(role).Error; err != nil {
+			if isDuplicateError(err) {
+				return errors.New("role already exists")
+			}
+
+			return err
+		}
+
+		if err := tx.Where("role_id = ?", role.ID).Delete(&model.RolePermission{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Create(&role.Permissions).Error; err != nil {
+			return err
+		}
+
+		return ensureStaffManager(tx)
+	})
+}
+
+func (r *roleRepository) Delete(id string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ?", id).Delete(&model.Role{})
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return ensureStaffManager(tx)
+	})
+}
+
+func (r *roleRepository) GetAdminRoles(adminID string) ([]*model.Role, error) {
+	var roles []*model.Role
+
+	if err := r.db.Preload("Permissions").
+		Joins("JOIN admin_roles ON admin_roles.role_id = roles.id").
+		Where("admin_roles.admin_id = ?", adminID).
+		Order("roles.name ASC").
+		Find(&roles).Error; err != nil {
+		return nil, err
+	}
+
+	return roles, nil
+}
+
+// SetAdminRoles replaces the roles of a staff account
+func (r *roleRepository) SetAdminRoles(adminID string, roleIDs []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("admin_id = ?", adminID).Delete(&model.AdminRole{}).Error; err != nil {
+			return err
+		}
+
+		if err := assignRoles(tx, adminID, roleIDs); err != nil {
+			return err
+		}
+
+		return ensureStaffManager(tx)
+	})
+}
+
+// HasPermissions reports whether an active staff account holds every one of the permissions
+// through its roles. Without permissions it only checks that the account is active
+func (r *roleRepository) HasPermissions(adminID string, permissions ...string) (bool, error) {
+	if !isUUID(adminID) {
+		return false, nil
+	}
+
+	var count int64
+
+	if len(permissions) == 0 {
+		err := r.db.Model(&model.Admin{}).Where("id = ? AND active", adminID).Count(&count).Error
+		return count > 0, err
+	}
+
+	err := r.db.Table("role_permissions AS rp").
+		Joins("JOIN admin_roles AS ar ON ar.role_id = rp.role_id").
+		Joins("JOIN admins AS a ON a.id = ar.admin_id").
+		Where("a.id = ? AND a.active AND rp.permission IN ?", adminID, permissions).
+		Distinct("rp.permission").
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+
+	return count == int64(len(permissions)), nil
+}
+
+// MustChangePassword reports whether a staff account still has to replace the temporary password
+// it was invited or reset with
+func (r *roleRepository) MustChangePassword(adminID string) (bool, error) {
+	if !isUUID(adminID) {
+		return false, nil
+	}
+
+	var count int64
+
+	err := r.db.Model(&model.Admin{}).Where("id = ? AND must_change_password", adminID).Count(&count).Error
+
+	return count > 0, err
+}
+
+// assignRoles gives a staff account the roles, failing when one of them does not exist
+func assignRoles(tx *gorm.DB, adminID string, roleIDs []string) error {
+	if len(roleIDs) == 0 {
+		return nil
+	}
+
+	var count int64
+	if err := tx.Model(&model.Role{}).Where("id IN ?", roleIDs).Count(&count).Error; err != nil {
+		return err
+	}
+
+	if count != int64(len(roleIDs)) {
+		return errors.New("role not found")
+	}
+
+	adminRoles := make([]*model.AdminRole, len(roleIDs))
+	for i, roleID := range roleIDs {
+		adminRoles[i] = &model.AdminRole{AdminID: adminID, RoleID: roleID}
+	}
+
+	return tx.Create(&adminRoles).Error
+}
+
+// ensureStaffManager fails when no active admin is left with the staff:manage permission,
+// which would lock everyone out of role management
+func ensureStaffManager(tx *gorm.DB) error {
+	var count int64
+
+	if err := tx.Table("admins AS a").
+		Joins("JOIN admin_roles AS ar ON ar.admin_id = a.id").
+		Joins("JOIN role_permissions AS rp ON rp.role_id = ar.role_id").
+		Where("a.active AND rp.permission = ?", model.PermissionStaffManage).
+		Count(&count).Error; err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return errors.New("no active admin would be left to manage staff")
+	}
+
+	return nil
+}
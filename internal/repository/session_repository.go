@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SessionRepository interface {
+	Create(token *model.RefreshToken) error
+	GetByHash(hash string) (*model.RefreshToken, error)
+	Rotate(current, next *model.RefreshToken) error
+	Revoke(id string) error
+	RevokeFamily(familyID string) error
+	RevokeSubject(subjectID string) error
+	RevokeOtherSessions(subjectID, accessTokenID string) error
+	IsRevoked(jti string) (bool, error)
+}
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepository{
+		db: db,
+	}
+}
+
+func (r *sessionRepository) Create(token *model.RefreshToken) error {
+	return r.db.Create(token).Error
+}
+
+func (r *sessionRepository) GetByHash(hash string) (*model.RefreshToken, error) {
+	var token model.RefreshToken
+
+	if err := r.db.Where("token_hash = ?", hash).First(&token).Error; err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// Rotate revokes the current refresh token and saves the one replacing it. Only one of two
+// concurrent refreshes with the same token can win
+func (r *sessionRepository) Rotate(current, next *model.RefreshToken) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.RefreshToken{}).
+			Where("id = ? AND revoked_at IS NULL", current.ID).
+			Update("revoked_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+
+		if result.RowsAffected == 0 {
+			return errors.New("refresh token was already used")
+		}
+
+		return tx.Create(next).Error
+	})
+}
+
+func (r *sessionRepository) Revoke(id string) error {
+	return revokeSessions(r.db, "id = ?", id)
+}
+
+func (r *sessionRepository) RevokeFamily(familyID string) error {
+	return revokeSessions(r.db, "family_id = ?", familyID)
+}
+
+func (r *sessionRepository) RevokeSubject(subjectID string) error {
+	return revokeSessions(r.db, "subject_id = ?", subjectID)
+}
+
+// RevokeOtherSessions revokes every session of a subject except the one that issued the access
+// token accessTokenID. Without a matching session every session is revoked
+func (r *sessionRepository) RevokeOtherSessions(subjectID, accessTokenID string) error {
+	current := r.db.Model(&model.RefreshToken{}).Select("family_id").Where("access_token_id = ?", accessTokenID)
+
+	return revokeSessions(r.db, "subject_id = ? AND family_id NOT IN (?)", subjectID, current)
+}
+
+func (r *sessionRepository) IsRevoked(jti string) (bool, error) {
+	var count int64
+
+	if err := r.db.Model(&model.RevokedToken{}).Where("jti = ?", jti).Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// revokeSessions revokes the matching refresh tokens and lists every access token they issued
+// that has not expired yet, including those of tokens that were already rotated
+func revokeSessions(db *gorm.DB, query string, args ...any) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		var tokens []*model.RefreshToken
+		if err := tx.Where(query, args...).Where("access_expires_at > ?", now).Find(&tokens).Error; err != nil {
+			return err
+		}
+
+		if len(tokens) > 0 {
+			revoked := make([]*model.RevokedToken, len(tokens))
+			for i, token := range tokens {
+				revoked[i] = &model.RevokedToken{JTI: token.AccessTokenID, ExpiresAt: token.AccessExpiresAt}
+			}
+
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&revoked).Error; err != nil {
+				return err
+			}
+		}
+
+		// Entries for access tokens that expired on their own are no longer needed
+		if err := tx.Where("expires_at <= ?", now).Delete(&model.RevokedToken{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&model.RefreshToken{}).
+			Where(query, args...).
+			Where("revoked_at IS NULL").
+			Update("revoked_at", now).Error
+	})
+}
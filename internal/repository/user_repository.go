@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
@@ -12,9 +13,10 @@ type UserRepository interface {
 	Create(user *model.User) error
 	GetByID(id string) (*model.User, error)
 	GetAll(offset, limit int) ([]*model.User, error)
-	UpdateMembership(userID, status string) (*model.User, error)
-	// Update(id int64, user *model.User) error
-	// Delete(id int64) error
+	GetAllInBatches(batchSize int, fn func([]*model.User) error) error
+	Update(user *model.User) error
+	Anonymize(user *model.User) error
+	IsDeleted(id string) (bool, error)
 }
 
 type userRepository struct {
@@ -52,74 +54,104 @@ func (r *userRepository) GetAll(offset, limit int) ([]*model.User, error) {
 	return users, nil
 }
 
-func (r *userRepository) UpdateMembership(userID, status string) (*model.User, error) {
-	var user model.User
+// GetAllInBatches calls fn with every user, oldest first and batchSize of them at a time. Each
+// batch starts after the last user of the previous one rather than at an offset, so users created
+// or deleted during the export can neither be skipped nor repeated. It stops at the first error fn
+// returns
+func (r *userRepository) GetAllInBatches(batchSize int, fn func([]*model.User) error) error {
+	var last *model.User
 
-	if err := r.db.Where("id = ?", userID).First(&user).Error; err != nil {
-		return nil, err
+	for {
+		var users []*model.User
+
+		tx := r.db.Order("created_at").Order("id").Limit(batchSize)
+		if last != nil {
+			tx = tx.Where("(created_at, id) > (?, ?)", last.CreatedAt, last.ID)
+		}
+
+		if err := tx.Find(&users).Error; err != nil {
+			return err
+		}
+
+		if len(users) > 0 {
+			if err := fn(users); err != nil {
+				return err
+			}
+
+			last = users[len(users)-1]
+		}
+
+		if len(users) < batchSize {
+			return nil
+		}
 	}
+}
 
-	user.MembershipStatus = status
-	user.UpdatedAt = time.Now()
+func (r *userRepository) Update(user *model.User) error {
+	return r.db.Save(user).Error
+}
 
-	if err := r.db.Save(&user).Error; err != nil {
-		return nil, err
+// IsDeleted reports whether the account of a user was deleted. Users without an account yet, or
+// with an ID that is not a UUID, are not deleted
+func (r *userRepository) IsDeleted(id string) (bool, error) {
+	if !isUUID(id) {
+		return false, nil
 	}
 
-	return &user, nil
+	var count int64
+
+	err := r.db.Model(&model.User{}).Where("id = ? AND deleted_at IS NOT NULL", id).Count(&count).Error
+
+	return count > 0, err
+}
+
+// isUUID reports whether id can be compared with a uuid column. Tokens carry IDs as plain
+// strings, and a malformed one would otherwise fail the query
+func isUUID(id string) bool {
+	_, err := uuid.Parse(id)
+	return err == nil
 }
 
-// Update updates an existing user
-// func (r *userRepository) Update(id int64, user *model.User) error {
-// 	query := `
-// 		UPDATE users
-// 		SET username = $1, email = $2, phone = $3, updated_at = $4
-// 		WHERE id = $5`
-
-// 	user.UpdatedAt = time.Now()
-
-// 	result, err := r.db.Exec(
-// 		query,
-// 		user.Username,
-// 		user.Email,
-// 		user.Phone,
-// 		user.UpdatedAt,
-// 		id,
-// 	)
-// 	if err != nil {
-// 		return fmt.Errorf("failed to update user: %w", err)
-// 	}
-
-// 	rowsAffected, err := result.RowsAffected()
-// 	if err != nil {
-// 		return fmt.Errorf("failed to get rows affected: %w", err)
-// 	}
-
-// 	if rowsAffected == 0 {
-// 		return errors.New("user not found")
-// 	}
-
-// 	user.ID = id
-// 	return nil
-// }
-
-// Delete removes a user by ID
-// func (r *userRepository) Delete(id int64) error {
-// 	query := `DELETE FROM users WHERE id = $1`
-
-// 	result, err := r.db.Exec(query, id)
-// 	if err != nil {
-// 		return fmt.Errorf("failed to delete user: %w", err)
-// 	}
-
-// 	rowsAffected, err := result.RowsAffected()
-// 	if err != nil {
-// 		return fmt.Errorf("failed to get rows affected: %w", err)
-// 	}
-
-// 	if rowsAffected == 0 {
-// 		return errors.New("user not found")
-// 	}
-
-// 	return nil
-// }
+// Anonymize replaces the personal data of a user and keeps the row so orders, invoices and
+// payments still point at it. Messages queued for the user that were not sent yet, data exports
+// and notification preferences are dropped and every session of the user is revoked. Messages
+// queued before they carried a user are matched by email address only, as the phone number may
+// have been passed on to someone else
+func (r *userRepository) Anonymize(user *model.User) error {
+	email := user.Email
+	now := time.Now()
+
+	user.Name = "Deleted user"
+	user.Email = "deleted-" + user.ID + "@users.invalid"
+	user.CompanyName = ""
+	user.PhoneNumber = ""
+	user.MembershipStatus = model.MembershipPAYG
+	user.DeletedAt = &now
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(user).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("status = ? AND (user_id = ? OR (user_id IS NULL AND recipient = ?))",
+			model.OutboxStatusPending, user.ID, email).Delete(&model.EmailOutbox{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("user_id = ?", user.ID).Delete(&model.DataExport{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("user_id = ?", user.ID).Delete(&model.NotificationPreference{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&model.UserSubscription{}).
+			Where("user_id = ? AND status = ?", user.ID, model.SubscriptionStatusActive).
+			Updates(map[string]any{"status": model.SubscriptionStatusCancelled, "ended_at": now}).Error; err != nil {
+			return err
+		}
+
+		return revokeSessions(tx, "subject_id = ?", user.ID)
+	})
+}
@@ -1,13 +1,15 @@
 package router
 
 import (
+	"github.com/MogboPython/belvaphilips_backend/internal/auth"
 	"github.com/MogboPython/belvaphilips_backend/internal/handler"
 	"github.com/MogboPython/belvaphilips_backend/internal/middleware"
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
 	"github.com/gofiber/contrib/swagger"
 	"github.com/gofiber/fiber/v2"
 )
 
-func SetupRoutes(app *fiber.App, userHandler *handler.UserHandler, adminHandler *handler.AdminHandler, orderHandler *handler.OrderHandler, postHandler *handler.PostHandler) {
+func SetupRoutes(app *fiber.App, userHandler *handler.UserHandler, adminHandler *handler.AdminHandler, orderHandler *handler.OrderHandler, postHandler *handler.PostHandler, outboxHandler *handler.OutboxHandler, contactHandler *handler.ContactHandler, catalogHandler *handler.CatalogHandler, invoiceHandler *handler.InvoiceHandler, paymentHandler *handler.PaymentHandler, roleHandler *handler.RoleHandler, sessionHandler *handler.SessionHandler, exportHandler *handler.ExportHandler, organizationHandler *handler.OrganizationHandler, membershipHandler *handler.MembershipHandler, analyticsHandler *handler.AnalyticsHandler, permissions middleware.PermissionChecker, members middleware.MembershipChecker, verifier *auth.Verifier, revocations middleware.RevocationChecker, accounts middleware.AccountChecker) {
 	app.Get("/health", func(c *fiber.Ctx) error {
 		return c.JSON(fiber.Map{"status": "ok"})
 	})
@@ -19,40 +21,147 @@ func SetupRoutes(app *fiber.App, userHandler *handler.UserHandler, adminHandler
 
 	app.Use(swagger.New(swaggerCfg))
 
+	protected := middleware.Protected(verifier, revocations, accounts)
+
+	staff := func(required ...string) fiber.Handler {
+		return middleware.RequirePermission(permissions, required...)
+	}
+
+	// Saving a post as published needs posts:publish on top of posts:write
+	publishing := middleware.RequirePermissionWhen(permissions, func(c *fiber.Ctx) bool {
+		return c.FormValue("status") == "published"
+	}, model.PermissionPostsPublish)
+
 	api := app.Group("/api/v1")
 	api.Post("/admin/login", adminHandler.AdminLogin)
-	api.Post("/contact", handler.ContactUs)
+	api.Post("/admin/login/2fa", adminHandler.AdminTwoFactorLogin)
+	api.Post("/contact", contactHandler.ContactUs)
 	api.Post("/token", userHandler.CreateUserAccessToken)
+	api.Post("/auth/refresh", sessionHandler.RefreshSession)
+	api.Post("/auth/logout", sessionHandler.Logout)
+	api.Get("/catalog", catalogHandler.GetCatalog)
+	api.Get("/membership-plans", membershipHandler.GetPlans)
+	api.Post("/payments/webhook", paymentHandler.PaymentWebhook)
 	{
-		user := api.Group("/users", middleware.Protected())
-		user.Get("/:id", userHandler.GetUserByID)
+		user := api.Group("/users", protected)
+		user.Get("/:id", middleware.OwnerOrAdmin(middleware.ParamOwner("id"), permissions, model.PermissionUsersRead), userHandler.GetUserByID)
+		user.Put("/:id", middleware.OwnerOrAdmin(middleware.ParamOwner("id"), permissions, model.PermissionUsersUpdate), userHandler.UpdateUser)
+		user.Delete("/:id", middleware.OwnerOrAdmin(middleware.ParamOwner("id"), permissions, model.PermissionUsersUpdate), userHandler.DeleteUser)
+		user.Get("/:id/notification-preferences", middleware.OwnerOrAdmin(middleware.ParamOwner("id"), permissions, model.PermissionUsersRead), userHandler.GetNotificationPreferences)
+		user.Put("/:id/notification-preferences", middleware.OwnerOrAdmin(middleware.ParamOwner("id"), permissions, model.PermissionUsersUpdate), userHandler.UpdateNotificationPreferences)
+		user.Get("/:id/export", middleware.OwnerOrAdmin(middleware.ParamOwner("id"), permissions, model.PermissionUsersRead), exportHandler.GetUserExport)
+		user.Get("/:id/export/:export_id/download", middleware.OwnerOrAdmin(middleware.ParamOwner("id"), permissions, model.PermissionUsersRead), exportHandler.DownloadUserExport)
 		user.Post("/", userHandler.CreateUser)
-		user.Put("/:id/membership", userHandler.UpdateMembershipStatus)
+		user.Get("/:id/membership", middleware.OwnerOrAdmin(middleware.ParamOwner("id"), permissions, model.PermissionUsersRead), membershipHandler.GetSubscription)
+		user.Put("/:id/membership", staff(model.PermissionUsersUpdateMembership), membershipHandler.Subscribe)
+		user.Delete("/:id/membership", staff(model.PermissionUsersUpdateMembership), membershipHandler.CancelSubscription)
 	}
 	{
-		admin := api.Group("/admin", middleware.Protected(), middleware.AdminRole())
-		admin.Get("/get_users", adminHandler.GetAllUsers)
+		admin := api.Group("/admin", protected)
+		admin.Get("/get_users", staff(model.PermissionUsersRead), adminHandler.GetAllUsers)
+		admin.Delete("/users/:id/sessions", staff(model.PermissionSessionsRevoke), sessionHandler.RevokeSessions)
+		admin.Get("/staff", staff(model.PermissionStaffManage), adminHandler.GetAdmins)
+		admin.Post("/staff", staff(model.PermissionStaffManage), adminHandler.InviteAdmin)
+		admin.Post("/staff/:id/disable", staff(model.PermissionStaffManage), adminHandler.DisableAdmin)
+		admin.Post("/staff/:id/enable", staff(model.PermissionStaffManage), adminHandler.EnableAdmin)
+		admin.Post("/staff/:id/reset-password", staff(model.PermissionStaffManage), adminHandler.ResetAdminPassword)
+		admin.Delete("/staff/:id/sessions", staff(model.PermissionStaffManage), sessionHandler.RevokeSessions)
+		admin.Post("/staff/:id/2fa/reset", staff(model.PermissionStaffManage), adminHandler.ResetTwoFactor)
+		admin.Get("/staff/:id/roles", staff(model.PermissionStaffManage), roleHandler.GetAdminRoles)
+		admin.Put("/staff/:id/roles", staff(model.PermissionStaffManage), roleHandler.SetAdminRoles)
+		admin.Get("/roles", staff(model.PermissionStaffManage), roleHandler.GetRoles)
+		admin.Post("/roles", staff(model.PermissionStaffManage), roleHandler.CreateRole)
+		admin.Put("/roles/:id", staff(model.PermissionStaffManage), roleHandler.UpdateRole)
+		admin.Delete("/roles/:id", staff(model.PermissionStaffManage), roleHandler.DeleteRole)
+		admin.Get("/permissions", staff(model.PermissionStaffManage), roleHandler.GetPermissions)
+		admin.Put("/me/password", middleware.RequirePasswordChange(permissions), adminHandler.ChangePassword)
+		admin.Post("/me/2fa/setup", staff(), adminHandler.SetupTwoFactor)
+		admin.Post("/me/2fa/enable", staff(), adminHandler.EnableTwoFactor)
+		admin.Post("/me/2fa/disable", staff(), adminHandler.DisableTwoFactor)
+		admin.Post("/me/2fa/recovery-codes", staff(), adminHandler.RegenerateRecoveryCodes)
+		admin.Get("/order-status-emails", staff(model.PermissionEmailsManage), orderHandler.GetStatusEmailSettings)
+		admin.Put("/order-status-emails/:status", staff(model.PermissionEmailsManage), orderHandler.UpdateStatusEmailSetting)
+		admin.Get("/emails", staff(model.PermissionEmailsManage), outboxHandler.GetEmails)
+		admin.Post("/emails/:id/resend", staff(model.PermissionEmailsManage), outboxHandler.ResendEmail)
+		admin.Get("/catalog", staff(model.PermissionCatalogManage), catalogHandler.GetFullCatalog)
+		admin.Post("/catalog", staff(model.PermissionCatalogManage), catalogHandler.CreateCatalogItem)
+		admin.Put("/catalog/:id", staff(model.PermissionCatalogManage), catalogHandler.UpdateCatalogItem)
+		admin.Delete("/catalog/:id", staff(model.PermissionCatalogManage), catalogHandler.DeleteCatalogItem)
+		admin.Get("/membership-plans", staff(model.PermissionPlansManage), membershipHandler.GetAllPlans)
+		admin.Post("/membership-plans", staff(model.PermissionPlansManage), membershipHandler.CreatePlan)
+		admin.Put("/membership-plans/:id", staff(model.PermissionPlansManage), membershipHandler.UpdatePlan)
+		admin.Post("/payments/:id/refund", staff(model.PermissionPaymentsRefund), paymentHandler.RefundPayment)
+		admin.Get("/analytics", staff(model.PermissionAnalyticsRead), analyticsHandler.GetAnalytics)
+		admin.Get("/exports/orders", staff(model.PermissionOrdersRead), exportHandler.ExportOrders)
+		admin.Get("/exports/users", staff(model.PermissionUsersRead), exportHandler.ExportUsers)
 	}
 	{
-		order := api.Group("/orders/", middleware.Protected())
+		order := api.Group("/orders/", protected)
+		orderOwner := func(required ...string) fiber.Handler {
+			return middleware.OwnerMemberOrAdmin(orderHandler.OrderOwner, members, permissions, required...)
+		}
+		orderCustomer := middleware.OwnerOrMember(orderHandler.OrderOwner, members)
+
 		// User-specific routes
-		order.Get("/user/:userId", orderHandler.GetOrdersByUserID)
+		order.Get("/user/:userId", middleware.OwnerOrAdmin(middleware.ParamOwner("userId"), permissions, model.PermissionOrdersRead), orderHandler.GetOrdersByUserID)
+		order.Get("/organization/:id", middleware.OwnerMemberOrAdmin(middleware.ParamOrganization("id"), members, permissions, model.PermissionOrdersRead), orderHandler.GetOrdersByOrganizationID)
 
 		// Admin-specific routes
-		order.Get("/", middleware.AdminRole(), orderHandler.GetAllOrders)
-		order.Put("/:order_id/status", middleware.AdminRole(), orderHandler.UpdateOrderStatus)
+		order.Get("/", staff(model.PermissionOrdersRead), orderHandler.GetAllOrders)
+		order.Put("/:order_id/status", staff(model.PermissionOrdersUpdateStatus), orderHandler.UpdateOrderStatus)
+		order.Get("/:id/quote/preview", staff(model.PermissionQuotesManage), orderHandler.PreviewQuote)
+		order.Post("/:id/quote", staff(model.PermissionQuotesManage), orderHandler.IssueQuote)
+		order.Post("/:id/invoice", staff(model.PermissionInvoicesManage), invoiceHandler.CreateInvoice)
+		order.Delete("/:id", staff(model.PermissionOrdersDelete), orderHandler.DeleteOrder)
+		order.Post("/:id/restore", staff(model.PermissionOrdersDelete), orderHandler.RestoreOrder)
 
 		// General routes
 		order.Post("/", orderHandler.CreateOrder)
-		order.Get("/:id", orderHandler.GetOrderByID)
+		order.Get("/:id", orderOwner(model.PermissionOrdersRead), orderHandler.GetOrderByID)
+		order.Get("/:id/history", orderOwner(model.PermissionOrdersRead), orderHandler.GetOrderStatusHistory)
+		order.Get("/:id/quote", orderOwner(model.PermissionOrdersRead), orderHandler.GetQuote)
+		order.Post("/:id/quote/accept", orderCustomer, orderHandler.AcceptQuote)
+		order.Post("/:id/quote/reject", orderCustomer, orderHandler.RejectQuote)
+		order.Post("/:id/cancel", orderCustomer, orderHandler.CancelOrder)
+		order.Patch("/:id", orderCustomer, orderHandler.UpdateOrder)
+		order.Get("/:id/revisions", orderOwner(model.PermissionOrdersRead), orderHandler.GetOrderRevisions)
+	}
+	{
+		organization := api.Group("/organizations", protected)
+		organizationMember := middleware.OwnerMemberOrAdmin(middleware.ParamOrganization("id"), members, permissions, model.PermissionOrganizationsManage)
+
+		organization.Post("/", organizationHandler.CreateOrganization)
+		organization.Get("/", organizationHandler.GetOrganizations)
+		organization.Post("/invitations/accept", organizationHandler.AcceptInvitation)
+
+		organization.Get("/:id", organizationMember, organizationHandler.GetOrganization)
+		organization.Put("/:id", organizationMember, organizationHandler.UpdateOrganization)
+		organization.Get("/:id/members", organizationMember, organizationHandler.GetMembers)
+		organization.Put("/:id/members/:user_id", organizationMember, organizationHandler.UpdateMemberRole)
+		organization.Delete("/:id/members/:user_id", organizationMember, organizationHandler.RemoveMember)
+		organization.Get("/:id/invitations", organizationMember, organizationHandler.GetInvitations)
+		organization.Post("/:id/invitations", organizationMember, organizationHandler.InviteMember)
+		organization.Delete("/:id/invitations/:invitation_id", organizationMember, organizationHandler.RevokeInvitation)
+	}
+	{
+		invoice := api.Group("/invoices", protected)
+		invoiceOwner := middleware.OwnerMemberOrAdmin(invoiceHandler.InvoiceOwner, members, permissions, model.PermissionInvoicesManage)
+
+		invoice.Get("/", staff(model.PermissionInvoicesManage), invoiceHandler.GetAllInvoices)
+		invoice.Put("/:id/status", staff(model.PermissionInvoicesManage), invoiceHandler.UpdateInvoiceStatus)
+
+		invoice.Get("/:id", invoiceOwner, invoiceHandler.GetInvoiceByID)
+		invoice.Get("/:id/pdf", invoiceOwner, invoiceHandler.GetInvoicePDF)
+		invoice.Post("/:id/pay", invoiceOwner, paymentHandler.PayInvoice)
+		invoice.Get("/:id/payments", invoiceOwner, paymentHandler.GetInvoicePayments)
 	}
 	{
 		post := api.Group("/posts/")
-		post.Post("/upload-image", middleware.Protected(), middleware.AdminRole(), postHandler.UploadImage)
-		post.Post("/", middleware.Protected(), middleware.AdminRole(), postHandler.CreatePost)
-		post.Get("/drafts", middleware.Protected(), middleware.AdminRole(), postHandler.GetAllDraftPosts)
-		post.Put("/:id", middleware.Protected(), middleware.AdminRole(), postHandler.UpdatePost)
-		post.Delete("/:id", middleware.Protected(), middleware.AdminRole(), postHandler.DeletePost)
+		post.Post("/upload-image", protected, staff(model.PermissionPostsWrite), postHandler.UploadImage)
+		post.Post("/", protected, staff(model.PermissionPostsWrite), publishing, postHandler.CreatePost)
+		post.Get("/drafts", protected, staff(model.PermissionPostsWrite), postHandler.GetAllDraftPosts)
+		post.Put("/:id", protected, staff(model.PermissionPostsWrite), publishing, postHandler.UpdatePost)
+		post.Delete("/:id", protected, staff(model.PermissionPostsDelete), postHandler.DeletePost)
 
 		post.Get("/", postHandler.GetAllPosts)
 		post.Get("/:id", postHandler.GetPostByID)
@@ -60,11 +169,11 @@ func SetupRoutes(app *fiber.App, userHandler *handler.UserHandler, adminHandler
 	{
 		gallery := api.Group("/gallery")
 		gallery.Get("/:slug", postHandler.GetGalleryBySlug)
-		gallery.Get("/", middleware.Protected(), middleware.AdminRole(), postHandler.GetAllGalleries)
-		gallery.Post("/", middleware.Protected(), middleware.AdminRole(), postHandler.CreateGallery)
-		gallery.Put("/:id", middleware.Protected(), middleware.AdminRole(), postHandler.UpdateGallery)
-		gallery.Delete("/:id/image", middleware.Protected(), middleware.AdminRole(), postHandler.DeleteGalleryImage)
-		gallery.Delete("/:id", middleware.Protected(), middleware.AdminRole(), postHandler.DeleteGallery)
+		gallery.Get("/", protected, staff(model.PermissionGalleryWrite), postHandler.GetAllGalleries)
+		gallery.Post("/", protected, staff(model.PermissionGalleryWrite), postHandler.CreateGallery)
+		gallery.Put("/:id", protected, staff(model.PermissionGalleryWrite), postHandler.UpdateGallery)
+		gallery.Delete("/:id/image", protected, staff(model.PermissionGalleryDelete), postHandler.DeleteGalleryImage)
+		gallery.Delete("/:id", protected, staff(model.PermissionGalleryDelete), postHandler.DeleteGallery)
 	}
 
 	// handle unavailable route
@@ -3,43 +3,113 @@ package service
 import (
 	"errors"
 	"fmt"
+	"time"
 
-	"github.com/MogboPython/belvaphilips_backend/internal/config"
 	"github.com/MogboPython/belvaphilips_backend/internal/repository"
 	"github.com/MogboPython/belvaphilips_backend/pkg/model"
 	"github.com/MogboPython/belvaphilips_backend/pkg/utils"
+	"gorm.io/gorm"
 
 	"github.com/gofiber/fiber/v2/log"
 )
 
+var (
+	ErrIncorrectCredentials = errors.New("incorrect username or password")
+	ErrAdminDisabled        = errors.New("admin account is disabled")
+	ErrAdminsAlreadyExist   = errors.New("an admin account already exists")
+	ErrCannotDisableSelf    = errors.New("admins cannot disable their own account")
+)
+
+const temporaryPasswordLength = 16
+
 type AdminService interface {
-	Login(req *model.AdminLoginRequest) (string, error)
+	Login(req *model.AdminLoginRequest) (*model.AdminLoginResponse, error)
 	GetAllUsers(page, limit string) ([]*model.UserResponse, error)
+	GetAdmins() ([]*model.AdminResponse, error)
+	InviteAdmin(req *model.AdminInviteRequest) (*model.AdminResponse, error)
+	SetAdminActive(id string, actor *model.Actor, active bool) (*model.AdminResponse, error)
+	ResetAdminPassword(id string) (*model.AdminResponse, error)
+	ChangePassword(actor *model.Actor, req *model.AdminPasswordChangeRequest) error
+	CreateFirstAdmin(req *model.AdminInviteRequest, password string, temporary bool) (*model.AdminResponse, error)
+	VerifyTwoFactorLogin(req *model.AdminTwoFactorLoginRequest) (*model.AdminLoginResponse, error)
+	SetupTwoFactor(actor *model.Actor) (*model.TwoFactorSetupResponse, error)
+	EnableTwoFactor(actor *model.Actor, req *model.TwoFactorCodeRequest) (*model.RecoveryCodesResponse, error)
+	DisableTwoFactor(actor *model.Actor, req *model.TwoFactorDisableRequest) error
+	RegenerateRecoveryCodes(actor *model.Actor, req *model.TwoFactorCodeRequest) (*model.RecoveryCodesResponse, error)
+	ResetTwoFactor(id string) (*model.AdminResponse, error)
 }
 
 type adminService struct {
-	userRepo repository.UserRepository
+	userRepo    repository.UserRepository
+	adminRepo   repository.AdminRepository
+	roleRepo    repository.RoleRepository
+	sessionRepo repository.SessionRepository
 }
 
-func NewAdminService(userRepo repository.UserRepository) AdminService {
+func NewAdminService(userRepo repository.UserRepository, adminRepo repository.AdminRepository, roleRepo repository.RoleRepository, sessionRepo repository.SessionRepository) AdminService {
 	return &adminService{
-		userRepo: userRepo,
+		userRepo:    userRepo,
+		adminRepo:   adminRepo,
+		roleRepo:    roleRepo,
+		sessionRepo: sessionRepo,
 	}
 }
 
 // Login Admin with username and password
-func (*adminService) Login(req *model.AdminLoginRequest) (string, error) {
-	if (req.Username != config.Config("ADMIN_USERNAME_HASH")) || (req.Password != config.Config("ADMIN_PASSWORD_HASH")) {
-		return "", errors.New("incorrect username or password")
+func (s *adminService) Login(req *model.AdminLoginRequest) (*model.AdminLoginResponse, error) {
+	admin, err := s.adminRepo.GetByUsername(req.Username)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrIncorrectCredentials
+		}
+
+		return nil, fmt.Errorf("failed to find admin: %w", err)
+	}
+
+	if !utils.CheckPasswordHash(req.Password, admin.PasswordHash) {
+		return nil, ErrIncorrectCredentials
 	}
 
-	token, err := utils.GenerateToken("AdminSession", "admin")
+	if !admin.Active {
+		return nil, ErrAdminDisabled
+	}
+
+	if admin.TOTPEnabled {
+		challenge, err := utils.GenerateChallengeToken(admin.ID)
+		if err != nil {
+			log.Error("Error generating two-factor challenge:", err)
+			return nil, errors.New("error generating token")
+		}
+
+		return &model.AdminLoginResponse{
+			ChallengeToken:     challenge,
+			TwoFactorRequired:  true,
+			MustChangePassword: admin.MustChangePassword,
+		}, nil
+	}
+
+	return s.startAdminSession(admin)
+}
+
+// startAdminSession issues tokens to an admin who passed every sign in step
+func (s *adminService) startAdminSession(admin *model.Admin) (*model.AdminLoginResponse, error) {
+	tokens, err := issueSession(s.sessionRepo, admin.ID, model.RoleAdmin)
 	if err != nil {
-		log.Error("Error signing token:", err)
-		return "", errors.New("error generating token")
+		log.Error("Error starting admin session:", err)
+		return nil, errors.New("error generating token")
 	}
 
-	return token, nil
+	now := time.Now()
+	admin.LastLoginAt = &now
+
+	if err := s.adminRepo.RecordLogin(admin.ID, now); err != nil {
+		log.Warnf("Failed to record login of admin %s: %v", admin.ID, err)
+	}
+
+	return &model.AdminLoginResponse{
+		TokenResponse:      tokens,
+		MustChangePassword: admin.MustChangePassword,
+	}, nil
 }
 
 // GetAllUsers retrieves all users
@@ -59,3 +129,208 @@ func (s *adminService) GetAllUsers(pageStr, limitStr string) ([]*model.UserRespo
 
 	return userResponses, nil
 }
+
+func (s *adminService) GetAdmins() ([]*model.AdminResponse, error) {
+	admins, err := s.adminRepo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get admins: %w", err)
+	}
+
+	adminResponses := make([]*model.AdminResponse, len(admins))
+	for i, admin := range admins {
+		adminResponses[i] = mapAdminToResponse(admin)
+	}
+
+	return adminResponses, nil
+}
+
+// InviteAdmin creates a staff account with a temporary password and emails the password to them
+func (s *adminService) InviteAdmin(req *model.AdminInviteRequest) (*model.AdminResponse, error) {
+	password, err := utils.GenerateTemporaryPassword(temporaryPasswordLength)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate password: %w", err)
+	}
+
+	admin, err := newAdmin(req, password)
+	if err != nil {
+		return nil, err
+	}
+
+	admin.MustChangePassword = true
+
+	emails, err := newAdminCredentialsEmails(admin, password, "admin_invite.html", "Your Staff Account - BelvaPhilips Imagery")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.adminRepo.Create(admin, req.RoleIDs, emails); err != nil {
+		return nil, fmt.Errorf("failed to create admin: %w", err)
+	}
+
+	return mapAdminToResponse(admin), nil
+}
+
+// SetAdminActive disables or re-enables a staff account. Disabled admins cannot sign in and are
+// signed out everywhere
+func (s *adminService) SetAdminActive(id string, actor *model.Actor, active bool) (*model.AdminResponse, error) {
+	if !active && id == actor.ID {
+		return nil, ErrCannotDisableSelf
+	}
+
+	admin, err := s.adminRepo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find admin: %w", err)
+	}
+
+	admin.Active = active
+
+	if err := s.adminRepo.Update(admin, nil, "active"); err != nil {
+		return nil, fmt.Errorf("failed to update admin: %w", err)
+	}
+
+	if !active {
+		if err := s.sessionRepo.RevokeSubject(admin.ID); err != nil {
+			return nil, fmt.Errorf("failed to revoke admin sessions: %w", err)
+		}
+	}
+
+	return mapAdminToResponse(admin), nil
+}
+
+// ResetAdminPassword replaces the password of a staff account with a temporary one and emails it to them.
+// Sessions opened with the old password are revoked
+func (s *adminService) ResetAdminPassword(id string) (*model.AdminResponse, error) {
+	admin, err := s.adminRepo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find admin: %w", err)
+	}
+
+	password, err := utils.GenerateTemporaryPassword(temporaryPasswordLength)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate password: %w", err)
+	}
+
+	if admin.PasswordHash, err = utils.HashPassword(password); err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	admin.MustChangePassword = true
+
+	emails, err := newAdminCredentialsEmails(admin, password, "admin_password_reset.html", "Your Staff Password Was Reset - BelvaPhilips Imagery")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.adminRepo.Update(admin, emails, "password_hash", "must_change_password"); err != nil {
+		return nil, fmt.Errorf("failed to update admin: %w", err)
+	}
+
+	if err := s.sessionRepo.RevokeSubject(admin.ID); err != nil {
+		return nil, fmt.Errorf("failed to revoke admin sessions: %w", err)
+	}
+
+	return mapAdminToResponse(admin), nil
+}
+
+// ChangePassword sets a new password for the signed in admin and signs them out everywhere else
+func (s *adminService) ChangePassword(actor *model.Actor, req *model.AdminPasswordChangeRequest) error {
+	admin, err := s.adminRepo.GetByID(actor.ID)
+	if err != nil {
+		return fmt.Errorf("failed to find admin: %w", err)
+	}
+
+	if !utils.CheckPasswordHash(req.CurrentPassword, admin.PasswordHash) {
+		return ErrIncorrectCredentials
+	}
+
+	if admin.PasswordHash, err = utils.HashPassword(req.NewPassword); err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	admin.MustChangePassword = false
+
+	if err := s.adminRepo.Update(admin, nil, "password_hash", "must_change_password"); err != nil {
+		return fmt.Errorf("failed to update admin: %w", err)
+	}
+
+	if err := s.sessionRepo.RevokeOtherSessions(admin.ID, actor.TokenID); err != nil {
+		return fmt.Errorf("failed to revoke admin sessions: %w", err)
+	}
+
+	return nil
+}
+
+// CreateFirstAdmin creates the initial admin account with the owner role. It refuses to run once
+// any admin exists. A temporary password has to be changed at the first sign in
+func (s *adminService) CreateFirstAdmin(req *model.AdminInviteRequest, password string, temporary bool) (*model.AdminResponse, error) {
+	count, err := s.adminRepo.Count()
+	if err != nil {
+		return nil, fmt.Errorf("failed to count admins: %w", err)
+	}
+
+	if count > 0 {
+		return nil, ErrAdminsAlreadyExist
+	}
+
+	admin, err := newAdmin(req, password)
+	if err != nil {
+		return nil, err
+	}
+
+	admin.MustChangePassword = temporary
+
+	owner, err := s.roleRepo.GetByName(model.OwnerRole)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find %s role: %w", model.OwnerRole, err)
+	}
+
+	if err := s.adminRepo.Create(admin, []string{owner.ID}, nil); err != nil {
+		return nil, fmt.Errorf("failed to create admin: %w", err)
+	}
+
+	return mapAdminToResponse(admin), nil
+}
+
+func newAdmin(req *model.AdminInviteRequest, password string) (*model.Admin, error) {
+	hash, err := utils.HashPassword(password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	return &model.Admin{
+		Username:     req.Username,
+		Email:        req.Email,
+		Name:         req.Name,
+		PasswordHash: hash,
+		Active:       true,
+	}, nil
+}
+
+// newAdminCredentialsEmails builds the email that hands a temporary password to a staff member.
+// The password is removed from the outbox once the email left it
+func newAdminCredentialsEmails(admin *model.Admin, password, templateName, subject string) ([]*model.EmailOutbox, error) {
+	body, err := utils.ParseTemplate(templateName, map[string]string{
+		"Name":     admin.Name,
+		"Username": admin.Username,
+		"Password": password,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", templateName, err)
+	}
+
+	return []*model.EmailOutbox{sensitive(newOutboxEmail(admin.Email, subject, body))}, nil
+}
+
+func mapAdminToResponse(admin *model.Admin) *model.AdminResponse {
+	return &model.AdminResponse{
+		ID:                 admin.ID,
+		Username:           admin.Username,
+		Email:              admin.Email,
+		Name:               admin.Name,
+		Active:             admin.Active,
+		MustChangePassword: admin.MustChangePassword,
+		TOTPEnabled:        admin.TOTPEnabled,
+		LastLoginAt:        admin.LastLoginAt,
+		CreatedAt:          admin.CreatedAt,
+	}
+}
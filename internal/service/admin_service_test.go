@@ -0,0 +1,213 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MogboPython/belvaphilips_backend/internal/repository"
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/MogboPython/belvaphilips_backend/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type stubAdminRepository struct {
+	repository.AdminRepository
+	admins        map[string]*model.Admin
+	roles         map[string][]string
+	recoveryCodes map[string][]string
+	updated       [][]string
+}
+
+func newStubAdminRepository(t *testing.T, admins ...*model.Admin) *stubAdminRepository {
+	t.Helper()
+
+	repo := &stubAdminRepository{
+		admins:        map[string]*model.Admin{},
+		roles:         map[string][]string{},
+		recoveryCodes: map[string][]string{},
+	}
+	for _, admin := range admins {
+		repo.admins[admin.ID] = admin
+	}
+
+	return repo
+}
+
+func (r *stubAdminRepository) GetByID(id string) (*model.Admin, error) {
+	if admin, ok := r.admins[id]; ok {
+		return admin, nil
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *stubAdminRepository) GetByUsername(username string) (*model.Admin, error) {
+	for _, admin := range r.admins {
+		if admin.Username == username {
+			return admin, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *stubAdminRepository) Count() (int64, error) {
+	return int64(len(r.admins)), nil
+}
+
+func (r *stubAdminRepository) Create(admin *model.Admin, roleIDs []string, _ []*model.EmailOutbox) error {
+	admin.ID = "admin-" + admin.Username
+	r.admins[admin.ID] = admin
+	r.roles[admin.ID] = roleIDs
+
+	return nil
+}
+
+func (r *stubAdminRepository) Update(_ *model.Admin, _ []*model.EmailOutbox, columns ...string) error {
+	r.updated = append(r.updated, columns)
+	return nil
+}
+
+func (r *stubAdminRepository) RecordLogin(adminID string, at time.Time) error {
+	r.admins[adminID].LastLoginAt = &at
+	return nil
+}
+
+func (r *stubAdminRepository) ConsumeTOTPStep(adminID string, step int64) error {
+	admin := r.admins[adminID]
+	if step <= admin.TOTPLastStep {
+		return errors.New("two-factor code was already used")
+	}
+
+	admin.TOTPLastStep = step
+
+	return nil
+}
+
+func (r *stubAdminRepository) RecordTOTPFailure(adminID string, maxAttempts int, lockedUntil time.Time) error {
+	admin := r.admins[adminID]
+
+	admin.TOTPFailedAttempts++
+	if admin.TOTPFailedAttempts >= maxAttempts {
+		admin.TOTPFailedAttempts = 0
+		admin.TOTPLockedUntil = &lockedUntil
+	}
+
+	return nil
+}
+
+func (r *stubAdminRepository) SetRecoveryCodes(adminID string, codeHashes []string) error {
+	r.recoveryCodes[adminID] = codeHashes
+	return nil
+}
+
+func (r *stubAdminRepository) UseRecoveryCode(adminID, codeHash string) error {
+	for i, hash := range r.recoveryCodes[adminID] {
+		if hash == codeHash {
+			r.recoveryCodes[adminID] = append(r.recoveryCodes[adminID][:i], r.recoveryCodes[adminID][i+1:]...)
+			return nil
+		}
+	}
+
+	return errors.New("recovery code not found")
+}
+
+func newTestAdmin(t *testing.T, password string, active bool) *model.Admin {
+	t.Helper()
+
+	hash, err := utils.HashPassword(password)
+	assert.NoError(t, err)
+
+	return &model.Admin{ID: "admin-1", Username: "studio", Email: "studio@example.com", PasswordHash: hash, Active: active}
+}
+
+func TestAdminLogin(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Should sign in with the right password", func(t *testing.T) {
+		t.Parallel()
+
+		s := &adminService{adminRepo: newStubAdminRepository(t, newTestAdmin(t, "correct horse battery", true)), sessionRepo: newStubSessionRepository(t)}
+
+		login, err := s.Login(&model.AdminLoginRequest{Username: "studio", Password: "correct horse battery"})
+
+		assert.NoError(t, err)
+		assert.NotEmpty(t, login.AccessToken)
+	})
+
+	t.Run("Should reject a wrong password or unknown username", func(t *testing.T) {
+		t.Parallel()
+
+		s := &adminService{adminRepo: newStubAdminRepository(t, newTestAdmin(t, "correct horse battery", true)), sessionRepo: newStubSessionRepository(t)}
+
+		_, err := s.Login(&model.AdminLoginRequest{Username: "studio", Password: "wrong"})
+		assert.ErrorIs(t, err, ErrIncorrectCredentials)
+
+		_, err = s.Login(&model.AdminLoginRequest{Username: "nobody", Password: "correct horse battery"})
+		assert.ErrorIs(t, err, ErrIncorrectCredentials)
+	})
+
+	t.Run("Should not sign in disabled admins", func(t *testing.T) {
+		t.Parallel()
+
+		s := &adminService{adminRepo: newStubAdminRepository(t, newTestAdmin(t, "correct horse battery", false)), sessionRepo: newStubSessionRepository(t)}
+
+		_, err := s.Login(&model.AdminLoginRequest{Username: "studio", Password: "correct horse battery"})
+
+		assert.ErrorIs(t, err, ErrAdminDisabled)
+	})
+}
+
+func TestAdminAccounts(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Should not let admins disable themselves", func(t *testing.T) {
+		t.Parallel()
+
+		s := &adminService{adminRepo: newStubAdminRepository(t, newTestAdmin(t, "correct horse battery", true)), sessionRepo: newStubSessionRepository(t)}
+
+		_, err := s.SetAdminActive("admin-1", &model.Actor{ID: "admin-1", Role: model.RoleAdmin}, false)
+
+		assert.ErrorIs(t, err, ErrCannotDisableSelf)
+	})
+
+	t.Run("Should require the current password to change it", func(t *testing.T) {
+		t.Parallel()
+
+		admin := newTestAdmin(t, "correct horse battery", true)
+		admin.MustChangePassword = true
+		sessions := newStubSessionRepository(t)
+		repo := newStubAdminRepository(t, admin)
+		s := &adminService{adminRepo: repo, sessionRepo: sessions}
+		actor := &model.Actor{ID: "admin-1", Role: model.RoleAdmin, TokenID: "jti-1"}
+
+		err := s.ChangePassword(actor, &model.AdminPasswordChangeRequest{CurrentPassword: "wrong", NewPassword: "a much better password"})
+		assert.ErrorIs(t, err, ErrIncorrectCredentials)
+
+		err = s.ChangePassword(actor, &model.AdminPasswordChangeRequest{CurrentPassword: "correct horse battery", NewPassword: "a much better password"})
+		assert.NoError(t, err)
+		assert.True(t, utils.CheckPasswordHash("a much better password", admin.PasswordHash))
+		assert.False(t, admin.MustChangePassword)
+		assert.Equal(t, [][]string{{"password_hash", "must_change_password"}}, repo.updated)
+		assert.Equal(t, []string{"admin-1"}, sessions.revokedSubjects)
+		assert.Equal(t, []string{"jti-1"}, sessions.keptTokenIDs)
+	})
+
+	t.Run("Should only bootstrap an empty admins table", func(t *testing.T) {
+		t.Parallel()
+
+		req := &model.AdminInviteRequest{Username: "owner", Email: "owner@example.com", Name: "Owner"}
+		adminRepo := newStubAdminRepository(t)
+		s := &adminService{adminRepo: adminRepo, roleRepo: newStubRoleRepository(t)}
+
+		admin, err := s.CreateFirstAdmin(req, "correct horse battery", true)
+		assert.NoError(t, err)
+		assert.True(t, admin.MustChangePassword)
+		assert.Equal(t, []string{"role-owner"}, adminRepo.roles[admin.ID])
+
+		_, err = s.CreateFirstAdmin(req, "correct horse battery", false)
+		assert.ErrorIs(t, err, ErrAdminsAlreadyExist)
+	})
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MogboPython/belvaphilips_backend/internal/repository"
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+)
+
+var ErrInvalidAnalyticsRange = errors.New("invalid analytics range")
+
+const (
+	analyticsDefaultDays  = 30
+	analyticsMaxPeriods   = 400
+	analyticsTopCustomers = 10
+)
+
+// analyticsPeriodLength is roughly how long every interval is, to keep ranges to a size a chart
+// can show
+var analyticsPeriodLength = map[string]time.Duration{
+	model.AnalyticsIntervalDay:   24 * time.Hour,
+	model.AnalyticsIntervalWeek:  7 * 24 * time.Hour,
+	model.AnalyticsIntervalMonth: 30 * 24 * time.Hour,
+}
+
+type AnalyticsService interface {
+	GetAnalytics(request *model.AnalyticsRequest) (*model.AnalyticsResponse, error)
+}
+
+type analyticsService struct {
+	analyticsRepo repository.AnalyticsRepository
+}
+
+func NewAnalyticsService(analyticsRepo repository.AnalyticsRepository) AnalyticsService {
+	return &analyticsService{
+		analyticsRepo: analyticsRepo,
+	}
+}
+
+// GetAnalytics describes the orders and sign ups in the requested range, the last 30 days by default
+func (s *analyticsService) GetAnalytics(request *model.AnalyticsRequest) (*model.AnalyticsResponse, error) {
+	from, to, interval, err := parseAnalyticsRange(request, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	response := &model.AnalyticsResponse{From: from, To: to, Interval: interval}
+
+	if response.OrdersOverTime, err = s.analyticsRepo.CountOrdersByPeriod(from, to, interval); err != nil {
+		return nil, fmt.Errorf("failed to count orders over time: %w", err)
+	}
+
+	if response.OrdersByShootType, err = s.analyticsRepo.CountOrdersByShootType(from, to); err != nil {
+		return nil, fmt.Errorf("failed to count orders by shoot type: %w", err)
+	}
+
+	if response.OrdersByDeliverySpeed, err = s.analyticsRepo.CountOrdersByDeliverySpeed(from, to); err != nil {
+		return nil, fmt.Errorf("failed to count orders by delivery speed: %w", err)
+	}
+
+	completion, err := s.analyticsRepo.GetCompletionTime(from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order completion time: %w", err)
+	}
+
+	response.CompletionTime = *completion
+
+	if response.NewUsersOverTime, err = s.analyticsRepo.CountUsersByPeriod(from, to, interval); err != nil {
+		return nil, fmt.Errorf("failed to count new users over time: %w", err)
+	}
+
+	if response.TopCustomers, err = s.analyticsRepo.GetTopCustomers(from, to, analyticsTopCustomers); err != nil {
+		return nil, fmt.Errorf("failed to get top customers: %w", err)
+	}
+
+	return response, nil
+}
+
+// parseAnalyticsRange checks the range and interval of an analytics request. The range ends now
+// and starts 30 days earlier unless asked otherwise, and is counted per day by default
+func parseAnalyticsRange(request *model.AnalyticsRequest, now time.Time) (from, to time.Time, interval string, err error) {
+	interval = strings.ToLower(strings.TrimSpace(request.Interval))
+	if interval == "" {
+		interval = model.AnalyticsIntervalDay
+	}
+
+	periodLength, ok := analyticsPeriodLength[interval]
+	if !ok {
+		return from, to, "", fmt.Errorf("%w: interval must be day, week or month", ErrInvalidAnalyticsRange)
+	}
+
+	fromTime, err := parseFilterTime(request.From, false)
+	if err != nil {
+		return from, to, "", fmt.Errorf("%w: from %v", ErrInvalidAnalyticsRange, err)
+	}
+
+	toTime, err := parseFilterTime(request.To, true)
+	if err != nil {
+		return from, to, "", fmt.Errorf("%w: to %v", ErrInvalidAnalyticsRange, err)
+	}
+
+	to = now
+	if toTime != nil {
+		to = *toTime
+	}
+
+	from = to.AddDate(0, 0, -analyticsDefaultDays)
+	if fromTime != nil {
+		from = *fromTime
+	}
+
+	if !from.Before(to) {
+		return from, to, "", fmt.Errorf("%w: from must be before to", ErrInvalidAnalyticsRange)
+	}
+
+	if to.Sub(from) > analyticsMaxPeriods*periodLength {
+		return from, to, "", fmt.Errorf("%w: the range has more than %d %ss, use a longer interval", ErrInvalidAnalyticsRange, analyticsMaxPeriods, interval)
+	}
+
+	return from, to, interval, nil
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MogboPython/belvaphilips_backend/internal/repository"
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubAnalyticsRepository records the range it was asked about
+type stubAnalyticsRepository struct {
+	repository.AnalyticsRepository
+	from, to time.Time
+	interval string
+}
+
+func (r *stubAnalyticsRepository) CountOrdersByPeriod(from, to time.Time, interval string) ([]model.AnalyticsPeriod, error) {
+	r.from, r.to, r.interval = from, to, interval
+
+	return []model.AnalyticsPeriod{{Start: from, Count: 3}}, nil
+}
+
+func (*stubAnalyticsRepository) CountUsersByPeriod(from, _ time.Time, _ string) ([]model.AnalyticsPeriod, error) {
+	return []model.AnalyticsPeriod{{Start: from, Count: 1}}, nil
+}
+
+func (*stubAnalyticsRepository) CountOrdersByShootType(time.Time, time.Time) ([]model.AnalyticsBreakdown, error) {
+	return []model.AnalyticsBreakdown{{Value: "Studio", Count: 3}}, nil
+}
+
+func (*stubAnalyticsRepository) CountOrdersByDeliverySpeed(time.Time, time.Time) ([]model.AnalyticsBreakdown, error) {
+	return []model.AnalyticsBreakdown{{Value: "Standard", Count: 3}}, nil
+}
+
+func (*stubAnalyticsRepository) GetCompletionTime(time.Time, time.Time) (*model.OrderCompletionTime, error) {
+	return &model.OrderCompletionTime{}, nil
+}
+
+func (*stubAnalyticsRepository) GetTopCustomers(time.Time, time.Time, int) ([]model.TopCustomer, error) {
+	return []model.TopCustomer{{UserID: "user-1", OrderCount: 3}}, nil
+}
+
+func TestParseAnalyticsRange(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, time.March, 15, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Should cover the last 30 days per day by default", func(t *testing.T) {
+		t.Parallel()
+
+		from, to, interval, err := parseAnalyticsRange(&model.AnalyticsRequest{}, now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, now.AddDate(0, 0, -30), from)
+		assert.Equal(t, now, to)
+		assert.Equal(t, model.AnalyticsIntervalDay, interval)
+	})
+
+	t.Run("Should include the whole last day of a date range", func(t *testing.T) {
+		t.Parallel()
+
+		from, to, interval, err := parseAnalyticsRange(&model.AnalyticsRequest{From: "2026-01-01", To: "2026-03-31", Interval: "Week"}, now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC), from)
+		assert.Equal(t, time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC), to)
+		assert.Equal(t, model.AnalyticsIntervalWeek, interval)
+	})
+
+	t.Run("Should refuse ranges it cannot chart", func(t *testing.T) {
+		t.Parallel()
+
+		for _, request := range []*model.AnalyticsRequest{
+			{Interval: "hour"},
+			{From: "yesterday"},
+			{From: "2026-03-01", To: "2026-02-01"},
+			{From: "2020-01-01", To: "2026-01-01", Interval: model.AnalyticsIntervalDay},
+		} {
+			_, _, _, err := parseAnalyticsRange(request, now)
+
+			assert.ErrorIs(t, err, ErrInvalidAnalyticsRange, request)
+		}
+	})
+}
+
+func TestGetAnalytics(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Should aggregate over the requested range", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &stubAnalyticsRepository{}
+		s := &analyticsService{analyticsRepo: repo}
+
+		analytics, err := s.GetAnalytics(&model.AnalyticsRequest{From: "2026-01-01", To: "2026-06-30", Interval: "month"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC), repo.from)
+		assert.Equal(t, time.Date(2026, time.July, 1, 0, 0, 0, 0, time.UTC), repo.to)
+		assert.Equal(t, model.AnalyticsIntervalMonth, repo.interval)
+		assert.Equal(t, int64(3), analytics.OrdersOverTime[0].Count)
+		assert.Equal(t, "Studio", analytics.OrdersByShootType[0].Value)
+		assert.Equal(t, "user-1", analytics.TopCustomers[0].UserID)
+		assert.Nil(t, analytics.CompletionTime.AverageHours)
+	})
+}
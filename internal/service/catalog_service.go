@@ -0,0 +1,171 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/MogboPython/belvaphilips_backend/internal/repository"
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUnknownCatalogOption = errors.New("option is not available in the catalog")
+	ErrCatalogNameRequired  = errors.New("catalog item name cannot be blank")
+)
+
+type CatalogService interface {
+	GetCatalog(activeOnly bool) (*model.CatalogResponse, error)
+	CreateItem(req *model.CatalogItemRequest) (*model.CatalogItemResponse, error)
+	UpdateItem(id string, req *model.CatalogItemRequest) (*model.CatalogItemResponse, error)
+	DeleteItem(id string) error
+}
+
+type catalogService struct {
+	catalogRepo repository.CatalogRepository
+}
+
+func NewCatalogService(catalogRepo repository.CatalogRepository) CatalogService {
+	return &catalogService{
+		catalogRepo: catalogRepo,
+	}
+}
+
+// GetCatalog returns the catalog grouped by category, optionally leaving out inactive items
+func (s *catalogService) GetCatalog(activeOnly bool) (*model.CatalogResponse, error) {
+	items, err := s.catalogRepo.GetAll(activeOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get catalog: %w", err)
+	}
+
+	catalog := &model.CatalogResponse{
+		ShootTypes:     []*model.CatalogItemResponse{},
+		FinishTypes:    []*model.CatalogItemResponse{},
+		Shots:          []*model.CatalogItemResponse{},
+		DeliverySpeeds: []*model.CatalogItemResponse{},
+	}
+
+	for _, item := range items {
+		response := mapCatalogItemToResponse(item)
+
+		switch item.Category {
+		case model.CatalogCategoryShootType:
+			catalog.ShootTypes = append(catalog.ShootTypes, response)
+		case model.CatalogCategoryFinishType:
+			catalog.FinishTypes = append(catalog.FinishTypes, response)
+		case model.CatalogCategoryShot:
+			catalog.Shots = append(catalog.Shots, response)
+		case model.CatalogCategoryDeliverySpeed:
+			catalog.DeliverySpeeds = append(catalog.DeliverySpeeds, response)
+		}
+	}
+
+	return catalog, nil
+}
+
+func (s *catalogService) CreateItem(req *model.CatalogItemRequest) (*model.CatalogItemResponse, error) {
+	// Orders are matched against the catalog by their trimmed names
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, ErrCatalogNameRequired
+	}
+
+	item := &model.CatalogItem{
+		Category:    req.Category,
+		Name:        name,
+		Description: req.Description,
+		BasePrice:   req.BasePrice,
+		SortOrder:   req.SortOrder,
+		Active:      req.Active == nil || *req.Active,
+	}
+
+	if err := s.catalogRepo.Create(item); err != nil {
+		return nil, fmt.Errorf("failed to create catalog item: %w", err)
+	}
+
+	return mapCatalogItemToResponse(item), nil
+}
+
+func (s *catalogService) UpdateItem(id string, req *model.CatalogItemRequest) (*model.CatalogItemResponse, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, ErrCatalogNameRequired
+	}
+
+	item, err := s.catalogRepo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find catalog item: %w", err)
+	}
+
+	item.Category = req.Category
+	item.Name = name
+	item.Description = req.Description
+	item.BasePrice = req.BasePrice
+	item.SortOrder = req.SortOrder
+
+	if req.Active != nil {
+		item.Active = *req.Active
+	}
+
+	if err := s.catalogRepo.Update(item); err != nil {
+		return nil, fmt.Errorf("failed to update catalog item: %w", err)
+	}
+
+	return mapCatalogItemToResponse(item), nil
+}
+
+func (s *catalogService) DeleteItem(id string) error {
+	if err := s.catalogRepo.Delete(id); err != nil {
+		return fmt.Errorf("failed to delete catalog item: %w", err)
+	}
+
+	return nil
+}
+
+// catalogPriceList prices order options from the base prices of active catalog items
+type catalogPriceList struct {
+	catalogRepo repository.CatalogRepository
+}
+
+func NewCatalogPriceList(catalogRepo repository.CatalogRepository) PriceList {
+	return &catalogPriceList{
+		catalogRepo: catalogRepo,
+	}
+}
+
+func (p *catalogPriceList) UnitPrice(kind, name string) (int64, bool) {
+	item, err := p.catalogRepo.GetActiveByName(kind, name)
+	if err != nil {
+		return 0, false
+	}
+
+	return item.BasePrice, true
+}
+
+// canonicalCatalogName checks that name is an active catalog option, ignoring case and surrounding
+// spaces, and returns it as spelled in the catalog
+func canonicalCatalogName(catalogRepo repository.CatalogRepository, category, name string) (string, error) {
+	item, err := catalogRepo.GetActiveByName(category, strings.TrimSpace(name))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", fmt.Errorf("%w: %s %q", ErrUnknownCatalogOption, category, name)
+		}
+
+		return "", err
+	}
+
+	return item.Name, nil
+}
+
+func mapCatalogItemToResponse(item *model.CatalogItem) *model.CatalogItemResponse {
+	return &model.CatalogItemResponse{
+		ID:          item.ID,
+		Category:    item.Category,
+		Name:        item.Name,
+		Description: item.Description,
+		BasePrice:   item.BasePrice,
+		SortOrder:   item.SortOrder,
+		Active:      item.Active,
+	}
+}
@@ -0,0 +1,268 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/MogboPython/belvaphilips_backend/internal/repository"
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// stubCatalogRepository matches names the way the database does, ignoring their case
+type stubCatalogRepository struct {
+	repository.CatalogRepository
+	items []*model.CatalogItem
+	err   error
+}
+
+func (r *stubCatalogRepository) GetAll(bool) ([]*model.CatalogItem, error) {
+	return r.items, r.err
+}
+
+func (r *stubCatalogRepository) GetActiveByName(category, name string) (*model.CatalogItem, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	for _, item := range r.items {
+		if item.Category == category && item.Active && strings.EqualFold(item.Name, name) {
+			return item, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *stubCatalogRepository) GetByID(id string) (*model.CatalogItem, error) {
+	for _, item := range r.items {
+		if item.ID == id {
+			return item, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *stubCatalogRepository) Create(item *model.CatalogItem) error {
+	r.items = append(r.items, item)
+	return nil
+}
+
+func (*stubCatalogRepository) Update(*model.CatalogItem) error {
+	return nil
+}
+
+func newTestCatalog() *stubCatalogRepository {
+	return &stubCatalogRepository{items: []*model.CatalogItem{
+		{ID: "item-1", Category: model.CatalogCategoryShootType, Name: "Studio", Active: true},
+		{ID: "item-2", Category: model.CatalogCategoryFinishType, Name: "Basic Retouch", Active: true},
+		{ID: "item-3", Category: model.CatalogCategoryShot, Name: "Front View", Active: true},
+		{ID: "item-4", Category: model.CatalogCategoryShot, Name: "Back View", Active: true},
+		{ID: "item-5", Category: model.CatalogCategoryDeliverySpeed, Name: "Express", Active: true},
+		{ID: "item-6", Category: model.CatalogCategoryShot, Name: "Top View", Active: false},
+	}}
+}
+
+func TestCanonicalCatalogName(t *testing.T) {
+	t.Parallel()
+
+	catalog := newTestCatalog()
+
+	for _, tc := range []struct {
+		name     string
+		category string
+		option   string
+		want     string
+		err      error
+	}{
+		{name: "Should keep names spelled as in the catalog", category: model.CatalogCategoryShootType, option: "Studio", want: "Studio"},
+		{name: "Should ignore the case of names", category: model.CatalogCategoryShot, option: "FRONT view", want: "Front View"},
+		{name: "Should ignore spaces around names", category: model.CatalogCategoryDeliverySpeed, option: "  express\t", want: "Express"},
+		{name: "Should refuse names that are not in the catalog", category: model.CatalogCategoryShot, option: "Side View", err: ErrUnknownCatalogOption},
+		{name: "Should refuse inactive options", category: model.CatalogCategoryShot, option: "Top View", err: ErrUnknownCatalogOption},
+		{name: "Should refuse options of another category", category: model.CatalogCategoryShot, option: "Studio", err: ErrUnknownCatalogOption},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			name, err := canonicalCatalogName(catalog, tc.category, tc.option)
+
+			assert.ErrorIs(t, err, tc.err)
+			assert.Equal(t, tc.want, name)
+		})
+	}
+
+	t.Run("Should pass on database errors", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := canonicalCatalogName(&stubCatalogRepository{err: errors.New("database unavailable")}, model.CatalogCategoryShot, "Front View")
+
+		assert.EqualError(t, err, "database unavailable")
+		assert.NotErrorIs(t, err, ErrUnknownCatalogOption)
+	})
+}
+
+func TestNormalizeOrderOptions(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name    string
+		request *model.OrderRequest
+		want    *model.OrderRequest
+		err     error
+	}{
+		{
+			name: "Should rewrite every option as spelled in the catalog",
+			request: &model.OrderRequest{
+				ShootType: " studio ", FinishType: "BASIC RETOUCH", DeliverySpeed: "express", Shots: []string{"front view", " Back View"},
+			},
+			want: &model.OrderRequest{
+				ShootType: "Studio", FinishType: "Basic Retouch", DeliverySpeed: "Express", Shots: []string{"Front View", "Back View"},
+			},
+		},
+		{
+			name:    "Should leave out optional options that were not chosen",
+			request: &model.OrderRequest{ShootType: "Studio"},
+			want:    &model.OrderRequest{ShootType: "Studio"},
+		},
+		{
+			name:    "Should refuse an unknown shoot type",
+			request: &model.OrderRequest{ShootType: "Underwater"},
+			err:     ErrUnknownCatalogOption,
+		},
+		{
+			name:    "Should refuse an unknown finish type",
+			request: &model.OrderRequest{ShootType: "Studio", FinishType: "Oil Painting"},
+			err:     ErrUnknownCatalogOption,
+		},
+		{
+			name:    "Should refuse an unknown delivery speed",
+			request: &model.OrderRequest{ShootType: "Studio", DeliverySpeed: "Yesterday"},
+			err:     ErrUnknownCatalogOption,
+		},
+		{
+			name:    "Should refuse unknown and inactive shots",
+			request: &model.OrderRequest{ShootType: "Studio", Shots: []string{"Front View", "Top View"}},
+			err:     ErrUnknownCatalogOption,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := &orderService{catalogRepo: newTestCatalog()}
+
+			err := s.normalizeOrderOptions(tc.request)
+
+			assert.ErrorIs(t, err, tc.err)
+
+			if tc.want != nil {
+				assert.Equal(t, tc.want, tc.request)
+			}
+		})
+	}
+}
+
+func TestGetCatalog(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Should group items by category and keep their order", func(t *testing.T) {
+		t.Parallel()
+
+		s := NewCatalogService(newTestCatalog())
+
+		catalog, err := s.GetCatalog(false)
+
+		assert.NoError(t, err)
+
+		names := func(items []*model.CatalogItemResponse) []string {
+			var result []string
+			for _, item := range items {
+				result = append(result, item.Name)
+			}
+
+			return result
+		}
+
+		assert.Equal(t, []string{"Studio"}, names(catalog.ShootTypes))
+		assert.Equal(t, []string{"Basic Retouch"}, names(catalog.FinishTypes))
+		assert.Equal(t, []string{"Front View", "Back View", "Top View"}, names(catalog.Shots))
+		assert.Equal(t, []string{"Express"}, names(catalog.DeliverySpeeds))
+	})
+
+	t.Run("Should return empty groups rather than null", func(t *testing.T) {
+		t.Parallel()
+
+		catalog, err := NewCatalogService(&stubCatalogRepository{}).GetCatalog(true)
+
+		assert.NoError(t, err)
+		assert.NotNil(t, catalog.ShootTypes)
+		assert.NotNil(t, catalog.FinishTypes)
+		assert.NotNil(t, catalog.Shots)
+		assert.NotNil(t, catalog.DeliverySpeeds)
+	})
+
+	t.Run("Should skip items of unknown categories", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &stubCatalogRepository{items: []*model.CatalogItem{{ID: "item-1", Category: "props", Name: "Mannequin", Active: true}}}
+
+		catalog, err := NewCatalogService(repo).GetCatalog(false)
+
+		assert.NoError(t, err)
+		assert.Empty(t, catalog.ShootTypes)
+		assert.Empty(t, catalog.FinishTypes)
+		assert.Empty(t, catalog.Shots)
+		assert.Empty(t, catalog.DeliverySpeeds)
+	})
+
+	t.Run("Should wrap repository errors", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := NewCatalogService(&stubCatalogRepository{err: errors.New("database unavailable")}).GetCatalog(false)
+
+		assert.ErrorContains(t, err, "failed to get catalog")
+	})
+}
+
+func TestSaveCatalogItem(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Should trim the name of a new item", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newTestCatalog()
+
+		item, err := NewCatalogService(repo).CreateItem(&model.CatalogItemRequest{Category: model.CatalogCategoryShot, Name: "  Side View\t"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Side View", item.Name)
+		assert.Equal(t, "Side View", repo.items[len(repo.items)-1].Name)
+	})
+
+	t.Run("Should trim the name of an updated item", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newTestCatalog()
+
+		item, err := NewCatalogService(repo).UpdateItem("item-3", &model.CatalogItemRequest{Category: model.CatalogCategoryShot, Name: " Front Angle "})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Front Angle", item.Name)
+		assert.Equal(t, "Front Angle", repo.items[2].Name)
+	})
+
+	t.Run("Should refuse blank names", func(t *testing.T) {
+		t.Parallel()
+
+		s := NewCatalogService(newTestCatalog())
+
+		_, err := s.CreateItem(&model.CatalogItemRequest{Category: model.CatalogCategoryShot, Name: "   "})
+		assert.ErrorIs(t, err, ErrCatalogNameRequired)
+
+		_, err = s.UpdateItem("item-3", &model.CatalogItemRequest{Category: model.CatalogCategoryShot, Name: "\t"})
+		assert.ErrorIs(t, err, ErrCatalogNameRequired)
+	})
+}
@@ -4,14 +4,29 @@ import (
 	"fmt"
 
 	"github.com/MogboPython/belvaphilips_backend/internal/config"
+	"github.com/MogboPython/belvaphilips_backend/internal/mailer"
 	"github.com/MogboPython/belvaphilips_backend/pkg/model"
 	"github.com/MogboPython/belvaphilips_backend/pkg/utils"
 
 	"github.com/gofiber/fiber/v2/log"
 )
 
+type ContactService interface {
+	SendContactEmail(req *model.ContactUsRequest) error
+}
+
+type contactService struct {
+	mail mailer.Mailer
+}
+
+func NewContactService(mail mailer.Mailer) ContactService {
+	return &contactService{
+		mail: mail,
+	}
+}
+
 // SendContactEmail sends a contact email to the admin
-func SendContactEmail(req *model.ContactUsRequest) error {
+func (s *contactService) SendContactEmail(req *model.ContactUsRequest) error {
 	to := config.Config("ADMIN_EMAIL")
 	subject := fmt.Sprintf("Contact from: %s %s", req.Firstname, req.Lastname)
 	body, err := utils.ParseTemplate("contact.html", req)
@@ -21,7 +36,7 @@ func SendContactEmail(req *model.ContactUsRequest) error {
 		return fmt.Errorf("failed to parse template: %w", err)
 	}
 
-	_, err = utils.SendEmail(to, subject, body)
+	err = s.mail.Send(&mailer.Message{To: to, Subject: subject, Body: body})
 	if err != nil {
 		log.Error("failed to send email: %v", err)
 		return fmt.Errorf("failed to send email: %w", err)
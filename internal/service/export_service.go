@@ -0,0 +1,315 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MogboPython/belvaphilips_backend/internal/repository"
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"gorm.io/gorm"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+var ErrExportNotReady = errors.New("the export is not ready for download")
+
+const (
+	exportBatchSize    = 5
+	exportPollInterval = 15 * time.Second
+	exportBuildLease   = 10 * time.Minute
+	exportMaxAttempts  = 3
+	exportTTL          = 7 * 24 * time.Hour
+)
+
+type ExportService interface {
+	Start(ctx context.Context)
+	RequestUserExport(userID string, actor *model.Actor) (*model.DataExportResponse, error)
+	GetUserExportArchive(userID, exportID string) (*model.DataExport, error)
+	ExportOrders(format string, request *model.OrderFilterRequest) (*model.Spreadsheet, error)
+	ExportUsers(format string) (*model.Spreadsheet, error)
+}
+
+type exportService struct {
+	exportRepo repository.ExportRepository
+	userRepo   repository.UserRepository
+	orderRepo  repository.OrderRepository
+}
+
+func NewExportService(exportRepo repository.ExportRepository, userRepo repository.UserRepository, orderRepo repository.OrderRepository) ExportService {
+	return &exportService{
+		exportRepo: exportRepo,
+		userRepo:   userRepo,
+		orderRepo:  orderRepo,
+	}
+}
+
+// RequestUserExport returns the export of a user that is being built or can still be downloaded,
+// and queues a new one when there is none
+func (s *exportService) RequestUserExport(userID string, actor *model.Actor) (*model.DataExportResponse, error) {
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	latest, err := s.exportRepo.GetLatest(userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to find export: %w", err)
+	}
+
+	if latest != nil && exportIsCurrent(latest, time.Now()) {
+		return mapExportToResponse(latest), nil
+	}
+
+	export := &model.DataExport{
+		UserID:      userID,
+		RequestedBy: actor.ID,
+		Status:      model.DataExportStatusPending,
+	}
+
+	if err := s.exportRepo.Create(export); err != nil {
+		return nil, fmt.Errorf("failed to queue export: %w", err)
+	}
+
+	log.Infof("Queued data export %s of user %s", export.ID, userID)
+
+	return mapExportToResponse(export), nil
+}
+
+// GetUserExportArchive returns a finished export of the user that has not expired yet
+func (s *exportService) GetUserExportArchive(userID, exportID string) (*model.DataExport, error) {
+	export, err := s.exportRepo.GetByID(exportID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find export: %w", err)
+	}
+
+	if export.UserID != userID {
+		return nil, fmt.Errorf("failed to find export: %w", gorm.ErrRecordNotFound)
+	}
+
+	if export.Status != model.DataExportStatusReady || export.ExpiresAt == nil || time.Now().After(*export.ExpiresAt) {
+		return nil, ErrExportNotReady
+	}
+
+	return export, nil
+}
+
+// Start builds queued exports and removes expired ones until ctx is cancelled
+func (s *exportService) Start(ctx context.Context) {
+	ticker := time.NewTicker(exportPollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.processDue()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *exportService) processDue() {
+	if removed, err := s.exportRepo.DeleteExpired(time.Now()); err != nil {
+		log.Errorf("Failed to remove expired exports: %v", err)
+	} else if removed > 0 {
+		log.Infof("Removed %d expired exports", removed)
+	}
+
+	exports, err := s.exportRepo.ClaimDue(exportBatchSize, exportBuildLease)
+	if err != nil {
+		log.Errorf("Failed to claim exports: %v", err)
+		return
+	}
+
+	for _, export := range exports {
+		s.build(export)
+	}
+}
+
+func (s *exportService) build(export *model.DataExport) {
+	export.Attempts++
+
+	archive, err := s.buildArchive(export.UserID)
+
+	now := time.Now()
+
+	switch {
+	case err == nil:
+		expiresAt := now.Add(exportTTL)
+
+		export.Status = model.DataExportStatusReady
+		export.Archive = archive
+		export.FileName = fmt.Sprintf("belvaphilips-export-%s.zip", now.Format("2006-01-02"))
+		export.CompletedAt = &now
+		export.ExpiresAt = &expiresAt
+		export.LastError = ""
+	case export.Attempts >= exportMaxAttempts:
+		export.Status = model.DataExportStatusFailed
+		export.CompletedAt = &now
+		export.LastError = err.Error()
+
+		log.Errorf("Giving up on data export %s of user %s after %d attempts: %v", export.ID, export.UserID, export.Attempts, err)
+	default:
+		export.Status = model.DataExportStatusPending
+		export.LastError = err.Error()
+
+		log.Warnf("Failed to build data export %s of user %s (attempt %d): %v", export.ID, export.UserID, export.Attempts, err)
+	}
+
+	if err := s.exportRepo.Save(export); err != nil {
+		log.Errorf("Failed to save data export %s: %v", export.ID, err)
+	}
+}
+
+func (s *exportService) buildArchive(userID string) ([]byte, error) {
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	// Orders hidden by an admin are still the customer's data
+	orders, err := s.orderRepo.GetByUserIDWithDeleted(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find orders: %w", err)
+	}
+
+	return writeExportArchive(user, orders)
+}
+
+// writeExportArchive packs the profile and orders of a user as JSON and CSV files in a ZIP archive
+func writeExportArchive(user *model.User, orders []*model.Order) ([]byte, error) {
+	responses := make([]*model.OrderResponse, len(orders))
+	for i, order := range orders {
+		responses[i] = mapOrderToResponse(order)
+	}
+
+	profileRows := [][]string{
+		{"id", "name", "email", "company_name", "phone_number", "membership_status", "created_at", "updated_at"},
+		{
+			user.ID, user.Name, user.Email, user.CompanyName, user.PhoneNumber, user.MembershipStatus,
+			user.CreatedAt.Format(time.RFC3339), user.UpdatedAt.Format(time.RFC3339),
+		},
+	}
+
+	orderRows := [][]string{{
+		"id", "order_name", "status", "product_name", "product_description", "shoot_type", "finish_type",
+		"delivery_speed", "membership_type", "quantity", "currency", "quoted_amount", "details", "shots",
+		"created_at", "updated_at",
+	}}
+
+	for _, order := range orders {
+		orderRows = append(orderRows, []string{
+			order.ID, order.OrderName, order.Status, order.ProductName, order.ProductDescription, order.ShootType,
+			order.FinishType, order.DeliverySpeed, order.MembershipType, strconv.Itoa(order.Quantity), order.Currency,
+			strconv.FormatInt(order.QuotedAmount, 10), string(order.Details), strings.Join(order.Shots, "\n"),
+			order.CreatedAt.Format(time.RFC3339), order.UpdatedAt.Format(time.RFC3339),
+		})
+	}
+
+	var buf bytes.Buffer
+
+	archive := zip.NewWriter(&buf)
+
+	files := []struct {
+		write func(io.Writer) error
+		name  string
+	}{
+		{name: "profile.json", write: jsonFile(user)},
+		{name: "profile.csv", write: csvFile(profileRows)},
+		{name: "orders.json", write: jsonFile(responses)},
+		{name: "orders.csv", write: csvFile(orderRows)},
+	}
+
+	for _, file := range files {
+		w, err := archive.Create(file.name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to add %s: %w", file.name, err)
+		}
+
+		if err := file.write(w); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", file.name, err)
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close archive: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func jsonFile(v any) func(io.Writer) error {
+	return func(w io.Writer) error {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(v)
+	}
+}
+
+func csvFile(rows [][]string) func(io.Writer) error {
+	return func(w io.Writer) error {
+		writer := csv.NewWriter(w)
+
+		for _, row := range rows {
+			for i, value := range row {
+				row[i] = csvSafe(value)
+			}
+
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+		}
+
+		writer.Flush()
+
+		return writer.Error()
+	}
+}
+
+// csvSafe keeps spreadsheet apps from running values typed by customers as formulas
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+
+	return value
+}
+
+// exportIsCurrent reports whether an export is still being built or can still be downloaded
+func exportIsCurrent(export *model.DataExport, now time.Time) bool {
+	switch export.Status {
+	case model.DataExportStatusPending, model.DataExportStatusProcessing:
+		return true
+	case model.DataExportStatusReady:
+		return export.ExpiresAt != nil && now.Before(*export.ExpiresAt)
+	default:
+		return false
+	}
+}
+
+func mapExportToResponse(export *model.DataExport) *model.DataExportResponse {
+	response := &model.DataExportResponse{
+		ID:          export.ID,
+		UserID:      export.UserID,
+		Status:      export.Status,
+		CreatedAt:   export.CreatedAt,
+		CompletedAt: export.CompletedAt,
+		ExpiresAt:   export.ExpiresAt,
+	}
+
+	if export.Status == model.DataExportStatusReady {
+		response.DownloadURL = fmt.Sprintf("/api/v1/users/%s/export/%s/download", export.UserID, export.ID)
+	}
+
+	return response
+}
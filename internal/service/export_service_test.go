@@ -0,0 +1,245 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/MogboPython/belvaphilips_backend/internal/repository"
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+type stubExportRepository struct {
+	repository.ExportRepository
+	exports []*model.DataExport
+}
+
+func (r *stubExportRepository) Create(export *model.DataExport) error {
+	export.ID = fmt.Sprintf("export-%d", len(r.exports)+1)
+	r.exports = append(r.exports, export)
+
+	return nil
+}
+
+func (r *stubExportRepository) GetByID(id string) (*model.DataExport, error) {
+	for _, export := range r.exports {
+		if export.ID == id {
+			return export, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *stubExportRepository) GetLatest(userID string) (*model.DataExport, error) {
+	for i := len(r.exports) - 1; i >= 0; i-- {
+		if r.exports[i].UserID == userID {
+			return r.exports[i], nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (*stubExportRepository) Save(*model.DataExport) error {
+	return nil
+}
+
+// failingUserRepository finds users for requests but fails while the archive is built
+type failingUserRepository struct {
+	*stubUserRepository
+	fail bool
+}
+
+func (r *failingUserRepository) GetByID(id string) (*model.User, error) {
+	if r.fail {
+		return nil, errors.New("database unavailable")
+	}
+
+	return r.stubUserRepository.GetByID(id)
+}
+
+// readArchive returns the files of a ZIP archive by name
+func readArchive(t *testing.T, archive []byte) map[string][]byte {
+	t.Helper()
+
+	reader, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	assert.NoError(t, err)
+
+	files := map[string][]byte{}
+
+	for _, file := range reader.File {
+		rc, err := file.Open()
+		assert.NoError(t, err)
+
+		content, err := io.ReadAll(rc)
+		assert.NoError(t, err)
+		assert.NoError(t, rc.Close())
+
+		files[file.Name] = content
+	}
+
+	return files
+}
+
+func TestWriteExportArchive(t *testing.T) {
+	t.Parallel()
+
+	user := newTestUser()
+	orders := []*model.Order{{
+		ID:          "order-1",
+		UserID:      user.ID,
+		User:        *user,
+		OrderName:   "BELVA-0001",
+		ProductName: "=HYPERLINK(\"http://example.com\")",
+		Status:      model.OrderStatusQuoted,
+		Details:     datatypes.JSON(`{"backdrop":"white"}`),
+		Shots:       pq.StringArray{"front", "side"},
+		Quantity:    3,
+		CreatedAt:   time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+	}}
+
+	archive, err := writeExportArchive(user, orders)
+	assert.NoError(t, err)
+
+	files := readArchive(t, archive)
+	assert.Len(t, files, 4)
+
+	var profile model.User
+	assert.NoError(t, json.Unmarshal(files["profile.json"], &profile))
+	assert.Equal(t, user.Email, profile.Email)
+
+	var exported []*model.OrderResponse
+	assert.NoError(t, json.Unmarshal(files["orders.json"], &exported))
+	assert.Len(t, exported, 1)
+	assert.Equal(t, map[string]any{"backdrop": "white"}, exported[0].Details)
+	assert.Equal(t, []string{"front", "side"}, exported[0].Shots)
+
+	rows, err := csv.NewReader(bytes.NewReader(files["orders.csv"])).ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, "id", rows[0][0])
+	assert.Equal(t, "'=HYPERLINK(\"http://example.com\")", rows[1][3])
+	assert.Equal(t, `{"backdrop":"white"}`, rows[1][12])
+	assert.Equal(t, "2026-01-02T03:04:05Z", rows[1][14])
+}
+
+func TestRequestUserExport(t *testing.T) {
+	t.Parallel()
+
+	actor := &model.Actor{ID: "user-1", Role: model.RoleAuthenticated}
+
+	t.Run("Should reuse an export that is still being built", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &stubExportRepository{}
+		s := &exportService{exportRepo: repo, userRepo: newStubUserRepository(t, newTestUser())}
+
+		first, err := s.RequestUserExport("user-1", actor)
+		assert.NoError(t, err)
+		assert.Equal(t, model.DataExportStatusPending, first.Status)
+		assert.Empty(t, first.DownloadURL)
+
+		second, err := s.RequestUserExport("user-1", actor)
+		assert.NoError(t, err)
+		assert.Equal(t, first.ID, second.ID)
+		assert.Len(t, repo.exports, 1)
+	})
+
+	t.Run("Should queue a new export once the last one expired", func(t *testing.T) {
+		t.Parallel()
+
+		expired := time.Now().Add(-time.Hour)
+		repo := &stubExportRepository{exports: []*model.DataExport{
+			{ID: "export-old", UserID: "user-1", Status: model.DataExportStatusReady, ExpiresAt: &expired},
+		}}
+		s := &exportService{exportRepo: repo, userRepo: newStubUserRepository(t, newTestUser())}
+
+		export, err := s.RequestUserExport("user-1", actor)
+
+		assert.NoError(t, err)
+		assert.NotEqual(t, "export-old", export.ID)
+		assert.Len(t, repo.exports, 2)
+
+		_, err = s.GetUserExportArchive("user-1", "export-old")
+		assert.ErrorIs(t, err, ErrExportNotReady)
+	})
+}
+
+func TestBuildExport(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Should store the archive and a download link", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &stubExportRepository{}
+		userRepo := newStubUserRepository(t, newTestUser())
+		s := &exportService{exportRepo: repo, userRepo: userRepo, orderRepo: &stubOrderRepository{}}
+
+		requested, err := s.RequestUserExport("user-1", &model.Actor{ID: "user-1", Role: model.RoleAuthenticated})
+		assert.NoError(t, err)
+
+		s.build(repo.exports[0])
+
+		export, err := s.GetUserExportArchive("user-1", requested.ID)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, export.Archive)
+		assert.Equal(t, "/api/v1/users/user-1/export/"+export.ID+"/download", mapExportToResponse(export).DownloadURL)
+
+		_, err = s.GetUserExportArchive("user-2", requested.ID)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("Should include orders an admin deleted", func(t *testing.T) {
+		t.Parallel()
+
+		orders := &stubOrderRepository{orders: []*model.Order{
+			{ID: "order-1", UserID: "user-1", OrderName: "BELVA-0001"},
+			{ID: "order-2", UserID: "user-1", OrderName: "BELVA-0002", DeletedAt: gorm.DeletedAt{Time: time.Now(), Valid: true}},
+			{ID: "order-3", UserID: "user-2", OrderName: "BELVA-0003"},
+		}}
+		s := &exportService{userRepo: newStubUserRepository(t, newTestUser()), orderRepo: orders}
+
+		archive, err := s.buildArchive("user-1")
+		assert.NoError(t, err)
+
+		var exported []*model.OrderResponse
+		assert.NoError(t, json.Unmarshal(readArchive(t, archive)["orders.json"], &exported))
+		assert.Len(t, exported, 2)
+		assert.Equal(t, "BELVA-0002", exported[1].OrderName)
+	})
+
+	t.Run("Should retry and then give up on exports that keep failing", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &stubExportRepository{}
+		userRepo := &failingUserRepository{stubUserRepository: newStubUserRepository(t, newTestUser())}
+		s := &exportService{exportRepo: repo, userRepo: userRepo, orderRepo: &stubOrderRepository{}}
+
+		_, err := s.RequestUserExport("user-1", &model.Actor{ID: "user-1", Role: model.RoleAuthenticated})
+		assert.NoError(t, err)
+
+		userRepo.fail = true
+		export := repo.exports[0]
+
+		s.build(export)
+		assert.Equal(t, model.DataExportStatusPending, export.Status)
+
+		for export.Attempts < exportMaxAttempts {
+			s.build(export)
+		}
+
+		assert.Equal(t, model.DataExportStatusFailed, export.Status)
+		assert.Equal(t, "failed to find user: database unavailable", export.LastError)
+	})
+}
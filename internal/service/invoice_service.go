@@ -0,0 +1,370 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/MogboPython/belvaphilips_backend/internal/config"
+	"github.com/MogboPython/belvaphilips_backend/internal/pdf"
+	"github.com/MogboPython/belvaphilips_backend/internal/repository"
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/MogboPython/belvaphilips_backend/pkg/utils"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrQuoteNotAccepted         = errors.New("order has no accepted quote")
+	ErrInvalidInvoiceTransition = errors.New("invalid invoice status transition")
+	ErrDiscountExceedsSubtotal  = errors.New("discount cannot exceed the invoice subtotal")
+)
+
+// invoiceStatusTransitions maps every invoice status to the statuses it may move to next
+var invoiceStatusTransitions = map[string][]string{
+	model.InvoiceStatusDraft:  {model.InvoiceStatusIssued, model.InvoiceStatusVoid},
+	model.InvoiceStatusIssued: {model.InvoiceStatusPaid, model.InvoiceStatusVoid},
+}
+
+const (
+	defaultInvoiceDueDays = 7
+	basisPointsPerUnit    = 10000
+	dateLayout            = "02 Jan 2006"
+)
+
+type InvoiceService interface {
+	CreateInvoice(orderID string, req *model.InvoiceRequest) (*model.InvoiceResponse, error)
+	GetInvoiceByID(id string) (*model.InvoiceResponse, error)
+	GetInvoiceOwner(id string) (*model.ResourceOwner, error)
+	GetAllInvoices(pageStr, limitStr, status string) (model.TotalInvoiceResponse, error)
+	UpdateInvoiceStatus(id string, req *model.InvoiceStatusChangeRequest) (*model.InvoiceResponse, error)
+	RenderInvoicePDF(ctx context.Context, id string) (string, []byte, error)
+}
+
+type invoiceService struct {
+	invoiceRepo repository.InvoiceRepository
+	orderRepo   repository.OrderRepository
+	renderer    pdf.Renderer
+}
+
+func NewInvoiceService(invoiceRepo repository.InvoiceRepository, orderRepo repository.OrderRepository, renderer pdf.Renderer) InvoiceService {
+	return &invoiceService{
+		invoiceRepo: invoiceRepo,
+		orderRepo:   orderRepo,
+		renderer:    renderer,
+	}
+}
+
+// CreateInvoice drafts an invoice from the accepted quote of an order
+func (s *invoiceService) CreateInvoice(orderID string, req *model.InvoiceRequest) (*model.InvoiceResponse, error) {
+	if _, err := s.orderRepo.GetByOrderID(orderID); err != nil {
+		return nil, fmt.Errorf("failed to find order: %w", err)
+	}
+
+	quote, err := s.orderRepo.GetLatestQuote(orderID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to find quote: %w", err)
+	}
+
+	if quote == nil || quote.Status != model.QuoteStatusAccepted {
+		return nil, ErrQuoteNotAccepted
+	}
+
+	taxRate := defaultTaxRate()
+	if req.TaxRateBasisPoints != nil {
+		taxRate = *req.TaxRateBasisPoints
+	}
+
+	invoice, err := calculateInvoice(quote, req.DiscountAmount, req.DiscountDescription, taxRate)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice.Notes = req.Notes
+
+	if err := s.invoiceRepo.Create(invoice); err != nil {
+		return nil, fmt.Errorf("failed to create invoice: %w", err)
+	}
+
+	return mapInvoiceToResponse(invoice), nil
+}
+
+// calculateInvoice builds a draft invoice from the quote lines, followed by a discount line and a tax line.
+// Tax is charged on the discounted subtotal and rounded to the nearest minor unit
+func calculateInvoice(quote *model.Quote, discount int64, discountDescription string, taxRateBasisPoints int) (*model.Invoice, error) {
+	lineItems := make([]model.InvoiceLineItem, 0, len(quote.LineItems)+2)
+
+	var subtotal int64
+
+	for _, item := range quote.LineItems {
+		subtotal += item.Amount
+
+		// The membership discount of the quote is already taken off the subtotal
+		kind := model.InvoiceLineKindItem
+		if item.Kind == model.LineItemKindDiscount {
+			kind = model.InvoiceLineKindDiscount
+		}
+
+		lineItems = append(lineItems, model.InvoiceLineItem{
+			Kind:        kind,
+			Description: item.Description,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice,
+			Amount:      item.Amount,
+			Position:    len(lineItems),
+		})
+	}
+
+	if discount > subtotal {
+		return nil, ErrDiscountExceedsSubtotal
+	}
+
+	if discount > 0 {
+		if discountDescription == "" {
+			discountDescription = "Discount"
+		}
+
+		lineItems = append(lineItems, model.InvoiceLineItem{
+			Kind:        model.InvoiceLineKindDiscount,
+			Description: discountDescription,
+			Quantity:    1,
+			UnitPrice:   -discount,
+			Amount:      -discount,
+			Position:    len(lineItems),
+		})
+	}
+
+	taxable := subtotal - discount
+	tax := (taxable*int64(taxRateBasisPoints) + basisPointsPerUnit/2) / basisPointsPerUnit
+
+	if tax > 0 {
+		lineItems = append(lineItems, model.InvoiceLineItem{
+			Kind:        model.InvoiceLineKindTax,
+			Description: fmt.Sprintf("Tax (%s%%)", strconv.FormatFloat(float64(taxRateBasisPoints)/100, 'f', -1, 64)),
+			Quantity:    1,
+			UnitPrice:   tax,
+			Amount:      tax,
+			Position:    len(lineItems),
+		})
+	}
+
+	return &model.Invoice{
+		OrderID:            quote.OrderID,
+		QuoteID:            quote.ID,
+		Status:             model.InvoiceStatusDraft,
+		Currency:           quote.Currency,
+		LineItems:          lineItems,
+		Subtotal:           subtotal,
+		DiscountAmount:     discount,
+		TaxRateBasisPoints: taxRateBasisPoints,
+		TaxAmount:          tax,
+		Total:              taxable + tax,
+	}, nil
+}
+
+func defaultTaxRate() int {
+	rate, err := strconv.Atoi(config.Config("INVOICE_TAX_RATE_BASIS_POINTS"))
+	if err != nil || rate < 0 {
+		return 0
+	}
+
+	return rate
+}
+
+func (s *invoiceService) GetInvoiceByID(id string) (*model.InvoiceResponse, error) {
+	invoice, err := s.invoiceRepo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find invoice: %w", err)
+	}
+
+	return mapInvoiceToResponse(invoice), nil
+}
+
+// GetInvoiceOwner returns the user who placed the order an invoice bills and the organization
+// the order was placed for
+func (s *invoiceService) GetInvoiceOwner(id string) (*model.ResourceOwner, error) {
+	invoice, err := s.invoiceRepo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find invoice: %w", err)
+	}
+
+	return orderOwner(&invoice.Order), nil
+}
+
+func (s *invoiceService) GetAllInvoices(pageStr, limitStr, status string) (model.TotalInvoiceResponse, error) {
+	var totalInvoiceResponse model.TotalInvoiceResponse
+
+	offset, limit := utils.GetPageAndLimitInt(pageStr, limitStr)
+
+	invoices, count, err := s.invoiceRepo.GetAll(offset, limit, status)
+	if err != nil {
+		return totalInvoiceResponse, fmt.Errorf("failed to get invoices: %w", err)
+	}
+
+	invoiceResponses := make([]*model.InvoiceResponse, len(invoices))
+	for i, invoice := range invoices {
+		invoiceResponses[i] = mapInvoiceToResponse(invoice)
+	}
+
+	totalInvoiceResponse.Invoices = invoiceResponses
+	totalInvoiceResponse.Total = count
+
+	return totalInvoiceResponse, nil
+}
+
+func (s *invoiceService) UpdateInvoiceStatus(id string, req *model.InvoiceStatusChangeRequest) (*model.InvoiceResponse, error) {
+	invoice, err := s.invoiceRepo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find invoice: %w", err)
+	}
+
+	if err := applyInvoiceStatus(invoice, req.Status, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := s.invoiceRepo.Update(invoice); err != nil {
+		return nil, fmt.Errorf("failed to update invoice: %w", err)
+	}
+
+	return mapInvoiceToResponse(invoice), nil
+}
+
+// applyInvoiceStatus moves an invoice to status, stamping when it was issued or paid
+func applyInvoiceStatus(invoice *model.Invoice, status string, now time.Time) error {
+	allowed := false
+
+	for _, next := range invoiceStatusTransitions[invoice.Status] {
+		if next == status {
+			allowed = true
+			break
+		}
+	}
+
+	if !allowed {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidInvoiceTransition, invoice.Status, status)
+	}
+
+	switch status {
+	case model.InvoiceStatusIssued:
+		dueDays, err := strconv.Atoi(config.Config("INVOICE_DUE_DAYS"))
+		if err != nil || dueDays < 0 {
+			dueDays = defaultInvoiceDueDays
+		}
+
+		dueAt := now.AddDate(0, 0, dueDays)
+		invoice.IssuedAt = &now
+		invoice.DueAt = &dueAt
+	case model.InvoiceStatusPaid:
+		invoice.PaidAt = &now
+	}
+
+	invoice.Status = status
+
+	return nil
+}
+
+type invoiceTemplateLine struct {
+	Description string
+	Quantity    int
+	UnitPrice   string
+	Amount      string
+}
+
+// RenderInvoicePDF renders templates/invoice.html for an invoice and converts it to a PDF.
+// It returns the invoice number alongside the document for naming the file
+func (s *invoiceService) RenderInvoicePDF(ctx context.Context, id string) (string, []byte, error) {
+	invoice, err := s.invoiceRepo.GetByID(id)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to find invoice: %w", err)
+	}
+
+	var lines, adjustments []invoiceTemplateLine
+
+	for _, item := range invoice.LineItems {
+		line := invoiceTemplateLine{
+			Description: item.Description,
+			Quantity:    item.Quantity,
+			UnitPrice:   utils.FormatAmount(invoice.Currency, item.UnitPrice),
+			Amount:      utils.FormatAmount(invoice.Currency, item.Amount),
+		}
+
+		if item.Kind == model.InvoiceLineKindItem {
+			lines = append(lines, line)
+		} else {
+			adjustments = append(adjustments, line)
+		}
+	}
+
+	data := map[string]any{
+		"InvoiceNumber": invoice.InvoiceNumber,
+		"Status":        invoice.Status,
+		"IssuedDate":    formatOptionalDate(invoice.IssuedAt),
+		"DueDate":       formatOptionalDate(invoice.DueAt),
+		"CustomerName":  invoice.Order.User.Name,
+		"CustomerEmail": invoice.Order.User.Email,
+		"CompanyName":   invoice.Order.User.CompanyName,
+		"OrderName":     invoice.Order.OrderName,
+		"ProductName":   invoice.Order.ProductName,
+		"Lines":         lines,
+		"Adjustments":   adjustments,
+		"Subtotal":      utils.FormatAmount(invoice.Currency, invoice.Subtotal),
+		"Total":         utils.FormatAmount(invoice.Currency, invoice.Total),
+		"Notes":         invoice.Notes,
+	}
+
+	html, err := utils.ParseTemplate("invoice.html", data)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to parse invoice template: %w", err)
+	}
+
+	document, err := s.renderer.RenderHTML(ctx, html)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to render invoice pdf: %w", err)
+	}
+
+	return invoice.InvoiceNumber, document, nil
+}
+
+func formatOptionalDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.Format(dateLayout)
+}
+
+func mapInvoiceToResponse(invoice *model.Invoice) *model.InvoiceResponse {
+	lineItems := make([]*model.InvoiceLineItemResponse, len(invoice.LineItems))
+	for i, item := range invoice.LineItems {
+		lineItems[i] = &model.InvoiceLineItemResponse{
+			Kind:        item.Kind,
+			Description: item.Description,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice,
+			Amount:      item.Amount,
+		}
+	}
+
+	return &model.InvoiceResponse{
+		ID:                 invoice.ID,
+		InvoiceNumber:      invoice.InvoiceNumber,
+		OrderID:            invoice.OrderID,
+		OrderName:          invoice.Order.OrderName,
+		UserID:             invoice.Order.UserID,
+		Status:             invoice.Status,
+		Currency:           invoice.Currency,
+		Notes:              invoice.Notes,
+		LineItems:          lineItems,
+		Subtotal:           invoice.Subtotal,
+		DiscountAmount:     invoice.DiscountAmount,
+		TaxRateBasisPoints: invoice.TaxRateBasisPoints,
+		TaxAmount:          invoice.TaxAmount,
+		Total:              invoice.Total,
+		IssuedAt:           invoice.IssuedAt,
+		DueAt:              invoice.DueAt,
+		PaidAt:             invoice.PaidAt,
+		CreatedAt:          invoice.CreatedAt,
+		UpdatedAt:          invoice.UpdatedAt,
+	}
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateInvoice(t *testing.T) {
+	t.Parallel()
+
+	quote := &model.Quote{
+		ID:       "quote-id",
+		OrderID:  "order-id",
+		Currency: "NGN",
+		LineItems: []model.QuoteLineItem{
+			{Description: "shoot type: Flat Lay", Quantity: 2, UnitPrice: 500000, Amount: 1000000},
+			{Description: "delivery speed: Express", Quantity: 2, UnitPrice: 100000, Amount: 200000},
+		},
+	}
+
+	t.Run("Should apply the discount before tax", func(t *testing.T) {
+		t.Parallel()
+
+		invoice, err := calculateInvoice(quote, 200000, "Loyalty discount", 750)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1200000), invoice.Subtotal)
+		assert.Equal(t, int64(75000), invoice.TaxAmount)
+		assert.Equal(t, int64(1075000), invoice.Total)
+		assert.Len(t, invoice.LineItems, 4)
+		assert.Equal(t, model.InvoiceLineKindDiscount, invoice.LineItems[2].Kind)
+		assert.Equal(t, int64(-200000), invoice.LineItems[2].Amount)
+		assert.Equal(t, "Tax (7.5%)", invoice.LineItems[3].Description)
+	})
+
+	t.Run("Should leave out empty discount and tax lines", func(t *testing.T) {
+		t.Parallel()
+
+		invoice, err := calculateInvoice(quote, 0, "", 0)
+
+		assert.NoError(t, err)
+		assert.Len(t, invoice.LineItems, 2)
+		assert.Equal(t, invoice.Subtotal, invoice.Total)
+	})
+
+	t.Run("Should reject a discount larger than the subtotal", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := calculateInvoice(quote, 1200001, "", 0)
+
+		assert.ErrorIs(t, err, ErrDiscountExceedsSubtotal)
+	})
+}
+
+func TestApplyInvoiceStatus(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Should stamp the issue and due dates", func(t *testing.T) {
+		t.Parallel()
+
+		now := time.Now()
+		invoice := &model.Invoice{Status: model.InvoiceStatusDraft}
+
+		assert.NoError(t, applyInvoiceStatus(invoice, model.InvoiceStatusIssued, now))
+		assert.Equal(t, model.InvoiceStatusIssued, invoice.Status)
+		assert.Equal(t, now, *invoice.IssuedAt)
+		assert.True(t, invoice.DueAt.After(now))
+	})
+
+	t.Run("Should not reopen a void invoice", func(t *testing.T) {
+		t.Parallel()
+
+		invoice := &model.Invoice{Status: model.InvoiceStatusVoid}
+
+		assert.ErrorIs(t, applyInvoiceStatus(invoice, model.InvoiceStatusPaid, time.Now()), ErrInvalidInvoiceTransition)
+	})
+}
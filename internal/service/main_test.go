@@ -0,0 +1,14 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestMain reads the email templates from the root of the module, where they live
+func TestMain(m *testing.M) {
+	os.Setenv("TEMPLATES_DIR", filepath.Join("..", "..", "templates"))
+
+	os.Exit(m.Run())
+}
@@ -0,0 +1,352 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/MogboPython/belvaphilips_backend/internal/repository"
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"gorm.io/gorm"
+
+	"github.com/gofiber/fiber/v2/log"
+)
+
+var (
+	ErrPlanNotAvailable  = errors.New("membership plan is not available")
+	ErrAlreadySubscribed = errors.New("user is already on this membership plan")
+)
+
+const (
+	renewalBatchSize    = 50
+	renewalPollInterval = 15 * time.Minute
+)
+
+type MembershipService interface {
+	Start(ctx context.Context)
+	GetPlans(activeOnly bool) ([]*model.MembershipPlan, error)
+	CreatePlan(req *model.MembershipPlanRequest) (*model.MembershipPlan, error)
+	UpdatePlan(id string, req *model.MembershipPlanRequest) (*model.MembershipPlan, error)
+	GetSubscription(userID string) (*model.SubscriptionResponse, error)
+	Subscribe(userID string, req *model.SubscriptionRequest) (*model.SubscriptionResponse, error)
+	CancelSubscription(userID string, immediately bool) (*model.SubscriptionResponse, error)
+}
+
+type membershipService struct {
+	membershipRepo repository.MembershipRepository
+	userRepo       repository.UserRepository
+}
+
+func NewMembershipService(membershipRepo repository.MembershipRepository, userRepo repository.UserRepository) MembershipService {
+	return &membershipService{
+		membershipRepo: membershipRepo,
+		userRepo:       userRepo,
+	}
+}
+
+func (s *membershipService) GetPlans(activeOnly bool) ([]*model.MembershipPlan, error) {
+	plans, err := s.membershipRepo.GetPlans(activeOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get membership plans: %w", err)
+	}
+
+	return plans, nil
+}
+
+func (s *membershipService) CreatePlan(req *model.MembershipPlanRequest) (*model.MembershipPlan, error) {
+	plan := &model.MembershipPlan{
+		Name:            req.Name,
+		Description:     req.Description,
+		Currency:        quoteCurrency(req.Currency),
+		MonthlyPrice:    req.MonthlyPrice,
+		IncludedShots:   req.IncludedShots,
+		DiscountPercent: req.DiscountPercent,
+		Active:          req.Active == nil || *req.Active,
+	}
+
+	if err := s.membershipRepo.CreatePlan(plan); err != nil {
+		return nil, fmt.Errorf("failed to create membership plan: %w", err)
+	}
+
+	return plan, nil
+}
+
+// UpdatePlan changes a plan for its current subscribers too. Deactivating a plan only stops new
+// subscriptions to it
+func (s *membershipService) UpdatePlan(id string, req *model.MembershipPlanRequest) (*model.MembershipPlan, error) {
+	plan, err := s.membershipRepo.GetPlanByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find membership plan: %w", err)
+	}
+
+	plan.Name = req.Name
+	plan.Description = req.Description
+	plan.Currency = quoteCurrency(req.Currency)
+	plan.MonthlyPrice = req.MonthlyPrice
+	plan.IncludedShots = req.IncludedShots
+	plan.DiscountPercent = req.DiscountPercent
+
+	if req.Active != nil {
+		plan.Active = *req.Active
+	}
+
+	if err := s.membershipRepo.UpdatePlan(plan); err != nil {
+		return nil, fmt.Errorf("failed to update membership plan: %w", err)
+	}
+
+	return plan, nil
+}
+
+// GetSubscription returns the active subscription of a user, renewed first when its period ended
+func (s *membershipService) GetSubscription(userID string) (*model.SubscriptionResponse, error) {
+	subscription, err := activeSubscription(s.membershipRepo, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if subscription == nil {
+		return nil, fmt.Errorf("failed to find subscription: %w", gorm.ErrRecordNotFound)
+	}
+
+	return mapSubscriptionToResponse(subscription), nil
+}
+
+// Subscribe puts a user on a plan starting now, replacing the plan they were on
+func (s *membershipService) Subscribe(userID string, req *model.SubscriptionRequest) (*model.SubscriptionResponse, error) {
+	if _, err := s.userRepo.GetByID(userID); err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	plan, err := s.membershipRepo.GetPlanByID(req.PlanID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPlanNotAvailable
+		}
+
+		return nil, fmt.Errorf("failed to find membership plan: %w", err)
+	}
+
+	if !plan.Active {
+		return nil, ErrPlanNotAvailable
+	}
+
+	now := time.Now()
+
+	current, err := activeSubscription(s.membershipRepo, userID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if current != nil && current.PlanID == plan.ID && !current.CancelAtPeriodEnd {
+		return nil, ErrAlreadySubscribed
+	}
+
+	subscription := &model.UserSubscription{
+		UserID:             userID,
+		PlanID:             plan.ID,
+		Plan:               *plan,
+		Status:             model.SubscriptionStatusActive,
+		StartedAt:          now,
+		CurrentPeriodStart: now,
+		RenewsAt:           addMonths(now, 1),
+	}
+
+	if err := s.membershipRepo.Subscribe(subscription); err != nil {
+		return nil, fmt.Errorf("failed to subscribe: %w", err)
+	}
+
+	log.Infof("Subscribed user %s to membership plan %s", userID, plan.Name)
+
+	return mapSubscriptionToResponse(subscription), nil
+}
+
+// CancelSubscription ends the subscription of a user at the end of the current period, when the
+// shots left in it can still be used, or right away
+func (s *membershipService) CancelSubscription(userID string, immediately bool) (*model.SubscriptionResponse, error) {
+	now := time.Now()
+
+	subscription, err := activeSubscription(s.membershipRepo, userID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if subscription == nil {
+		return nil, fmt.Errorf("failed to find subscription: %w", gorm.ErrRecordNotFound)
+	}
+
+	subscription.CancelAtPeriodEnd = true
+
+	if immediately {
+		subscription.Status = model.SubscriptionStatusCancelled
+		subscription.EndedAt = &now
+	}
+
+	if err := s.membershipRepo.SaveSubscription(subscription); err != nil {
+		return nil, fmt.Errorf("failed to cancel subscription: %w", err)
+	}
+
+	return mapSubscriptionToResponse(subscription), nil
+}
+
+// Start renews subscriptions whose period ended, and expires the cancelled ones, until ctx is cancelled
+func (s *membershipService) Start(ctx context.Context) {
+	ticker := time.NewTicker(renewalPollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.processDue()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *membershipService) processDue() {
+	now := time.Now()
+
+	subscriptions, err := s.membershipRepo.GetDueRenewals(now, renewalBatchSize)
+	if err != nil {
+		log.Errorf("Failed to find subscriptions due for renewal: %v", err)
+		return
+	}
+
+	for _, subscription := range subscriptions {
+		if _, err := renewSubscription(s.membershipRepo, subscription, now); err != nil {
+			log.Errorf("Failed to renew subscription %s: %v", subscription.ID, err)
+		}
+	}
+}
+
+// activeSubscription returns the active subscription of a user, renewing it first when its
+// period ended by now. It returns nil when the user is not on a plan
+func activeSubscription(membershipRepo repository.MembershipRepository, userID string, now time.Time) (*model.UserSubscription, error) {
+	subscription, err := membershipRepo.GetActiveSubscription(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to find subscription: %w", err)
+	}
+
+	if !now.Before(subscription.RenewsAt) {
+		saved, err := renewSubscription(membershipRepo, subscription, now)
+		if err != nil {
+			return nil, err
+		}
+
+		// The worker renewed it in the meantime, so read it again
+		if !saved {
+			subscription, err = membershipRepo.GetActiveSubscription(userID)
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, nil
+			}
+
+			if err != nil {
+				return nil, fmt.Errorf("failed to find subscription: %w", err)
+			}
+		}
+	}
+
+	if subscription.Status != model.SubscriptionStatusActive {
+		return nil, nil
+	}
+
+	return subscription, nil
+}
+
+// renewSubscription saves a subscription moved into the period containing now, unless it did
+// not end yet. It reports whether the subscription was saved
+func renewSubscription(membershipRepo repository.MembershipRepository, subscription *model.UserSubscription, now time.Time) (bool, error) {
+	previousRenewsAt := subscription.RenewsAt
+
+	if !advanceSubscription(subscription, now) {
+		return false, nil
+	}
+
+	saved, err := membershipRepo.SaveRenewal(subscription, previousRenewsAt)
+	if err != nil {
+		return false, fmt.Errorf("failed to renew subscription: %w", err)
+	}
+
+	if saved && subscription.Status == model.SubscriptionStatusExpired {
+		log.Infof("Subscription %s of user %s expired", subscription.ID, subscription.UserID)
+	}
+
+	return saved, nil
+}
+
+// advanceSubscription moves an active subscription whose period ended by now into the period
+// containing now and resets its usage, or expires it when it was cancelled at the period end.
+// It reports whether the subscription changed
+func advanceSubscription(subscription *model.UserSubscription, now time.Time) bool {
+	if subscription.Status != model.SubscriptionStatusActive || now.Before(subscription.RenewsAt) {
+		return false
+	}
+
+	if subscription.CancelAtPeriodEnd {
+		endedAt := subscription.RenewsAt
+
+		subscription.Status = model.SubscriptionStatusExpired
+		subscription.EndedAt = &endedAt
+
+		return true
+	}
+
+	for !now.Before(subscription.RenewsAt) {
+		subscription.CurrentPeriodStart = subscription.RenewsAt
+		subscription.RenewsAt = nextRenewal(subscription.StartedAt, subscription.RenewsAt)
+	}
+
+	subscription.ShotsUsed = 0
+
+	return true
+}
+
+// nextRenewal returns the renewal that follows renewsAt. Renewals are counted in whole months from
+// the start of the subscription, so one started on the 31st renews on the last day of shorter
+// months and goes back to the 31st after them
+func nextRenewal(startedAt, renewsAt time.Time) time.Time {
+	for months := 1; ; months++ {
+		if renewal := addMonths(startedAt, months); renewal.After(renewsAt) {
+			return renewal
+		}
+	}
+}
+
+// addMonths moves t by months, on the same day of the month or the last day of the target month
+// when it is shorter. time.AddDate would overflow into the next month instead
+func addMonths(t time.Time, months int) time.Time {
+	year, month, day := t.Date()
+
+	first := time.Date(year, month+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	lastDay := first.AddDate(0, 1, -1).Day()
+
+	return first.AddDate(0, 0, min(day, lastDay)-1)
+}
+
+// orderShotCount is the number of shots an order uses up from a plan: every shot picked, or one
+// when none were, for each unit ordered
+func orderShotCount(order *model.Order) int {
+	return max(order.Quantity, 1) * max(len(order.Shots), 1)
+}
+
+func mapSubscriptionToResponse(subscription *model.UserSubscription) *model.SubscriptionResponse {
+	return &model.SubscriptionResponse{
+		ID:                 subscription.ID,
+		UserID:             subscription.UserID,
+		Plan:               subscription.Plan,
+		Status:             subscription.Status,
+		StartedAt:          subscription.StartedAt,
+		CurrentPeriodStart: subscription.CurrentPeriodStart,
+		RenewsAt:           subscription.RenewsAt,
+		EndedAt:            subscription.EndedAt,
+		CancelAtPeriodEnd:  subscription.CancelAtPeriodEnd,
+		ShotsUsed:          subscription.ShotsUsed,
+		ShotsRemaining:     subscription.ShotsRemaining(),
+	}
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MogboPython/belvaphilips_backend/internal/repository"
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type stubMembershipRepository struct {
+	repository.MembershipRepository
+	subscription *model.UserSubscription
+	renewals     int
+}
+
+func (r *stubMembershipRepository) GetActiveSubscription(userID string) (*model.UserSubscription, error) {
+	if r.subscription == nil || r.subscription.UserID != userID || r.subscription.Status != model.SubscriptionStatusActive {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	subscription := *r.subscription
+
+	return &subscription, nil
+}
+
+func (r *stubMembershipRepository) SaveRenewal(subscription *model.UserSubscription, previousRenewsAt time.Time) (bool, error) {
+	if !r.subscription.RenewsAt.Equal(previousRenewsAt) {
+		return false, nil
+	}
+
+	r.renewals++
+	saved := *subscription
+	r.subscription = &saved
+
+	return true, nil
+}
+
+func newTestSubscription(renewsAt time.Time, shotsUsed int) *model.UserSubscription {
+	return &model.UserSubscription{
+		ID:                 "subscription-1",
+		UserID:             "user-1",
+		PlanID:             "plan-1",
+		Plan:               model.MembershipPlan{ID: "plan-1", Name: "Studio", IncludedShots: 10, DiscountPercent: 15},
+		Status:             model.SubscriptionStatusActive,
+		StartedAt:          renewsAt.AddDate(0, -1, 0),
+		CurrentPeriodStart: renewsAt.AddDate(0, -1, 0),
+		RenewsAt:           renewsAt,
+		ShotsUsed:          shotsUsed,
+	}
+}
+
+func TestAdvanceSubscription(t *testing.T) {
+	t.Parallel()
+
+	renewsAt := time.Date(2026, time.March, 10, 9, 0, 0, 0, time.UTC)
+
+	t.Run("Should leave a subscription alone until its period ends", func(t *testing.T) {
+		t.Parallel()
+
+		subscription := newTestSubscription(renewsAt, 4)
+
+		assert.False(t, advanceSubscription(subscription, renewsAt.Add(-time.Second)))
+		assert.Equal(t, 4, subscription.ShotsUsed)
+	})
+
+	t.Run("Should catch up on every period missed and reset the usage", func(t *testing.T) {
+		t.Parallel()
+
+		subscription := newTestSubscription(renewsAt, 4)
+
+		assert.True(t, advanceSubscription(subscription, renewsAt.AddDate(0, 2, 1)))
+		assert.Equal(t, renewsAt.AddDate(0, 2, 0), subscription.CurrentPeriodStart)
+		assert.Equal(t, renewsAt.AddDate(0, 3, 0), subscription.RenewsAt)
+		assert.Equal(t, 0, subscription.ShotsUsed)
+		assert.Equal(t, model.SubscriptionStatusActive, subscription.Status)
+	})
+
+	t.Run("Should renew on the last day of shorter months and go back to the day it started", func(t *testing.T) {
+		t.Parallel()
+
+		startedAt := time.Date(2026, time.January, 31, 9, 0, 0, 0, time.UTC)
+		subscription := newTestSubscription(addMonths(startedAt, 1), 4)
+		subscription.StartedAt = startedAt
+		subscription.CurrentPeriodStart = startedAt
+
+		assert.Equal(t, time.Date(2026, time.February, 28, 9, 0, 0, 0, time.UTC), subscription.RenewsAt)
+
+		assert.True(t, advanceSubscription(subscription, time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)))
+		assert.Equal(t, time.Date(2026, time.February, 28, 9, 0, 0, 0, time.UTC), subscription.CurrentPeriodStart)
+		assert.Equal(t, time.Date(2026, time.March, 31, 9, 0, 0, 0, time.UTC), subscription.RenewsAt)
+
+		assert.True(t, advanceSubscription(subscription, time.Date(2026, time.May, 1, 0, 0, 0, 0, time.UTC)))
+		assert.Equal(t, time.Date(2026, time.April, 30, 9, 0, 0, 0, time.UTC), subscription.CurrentPeriodStart)
+		assert.Equal(t, time.Date(2026, time.May, 31, 9, 0, 0, 0, time.UTC), subscription.RenewsAt)
+	})
+
+	t.Run("Should expire a subscription cancelled at the end of the period", func(t *testing.T) {
+		t.Parallel()
+
+		subscription := newTestSubscription(renewsAt, 4)
+		subscription.CancelAtPeriodEnd = true
+
+		assert.True(t, advanceSubscription(subscription, renewsAt.AddDate(0, 0, 3)))
+		assert.Equal(t, model.SubscriptionStatusExpired, subscription.Status)
+		assert.Equal(t, renewsAt, *subscription.EndedAt)
+	})
+}
+
+func TestApplySubscription(t *testing.T) {
+	t.Parallel()
+
+	newOrder := func(quantity int, shots ...string) *model.Order {
+		return &model.Order{UserID: "user-1", Quantity: quantity, Shots: pq.StringArray(shots), MembershipType: "Enterprise"}
+	}
+
+	t.Run("Should place an order covered by the plan under it", func(t *testing.T) {
+		t.Parallel()
+
+		s := &orderService{membershipRepo: &stubMembershipRepository{subscription: newTestSubscription(time.Now().Add(time.Hour), 4)}}
+		order := newOrder(3, "Front", "Back")
+
+		assert.NoError(t, s.applySubscription(order))
+		assert.Equal(t, "Studio", order.MembershipType)
+		assert.Equal(t, "subscription-1", *order.SubscriptionID)
+		assert.Equal(t, 6, order.PlanShots)
+		assert.Equal(t, 15, order.DiscountPercent)
+	})
+
+	t.Run("Should fall back to pay as you go when the plan has too few shots left", func(t *testing.T) {
+		t.Parallel()
+
+		s := &orderService{membershipRepo: &stubMembershipRepository{subscription: newTestSubscription(time.Now().Add(time.Hour), 8)}}
+		order := newOrder(3)
+
+		assert.NoError(t, s.applySubscription(order))
+		assert.Equal(t, model.OrderMembershipPayAsYouGo, order.MembershipType)
+		assert.Nil(t, order.SubscriptionID)
+		assert.Zero(t, order.DiscountPercent)
+	})
+
+	t.Run("Should count the order against a new period once the last one ended", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &stubMembershipRepository{subscription: newTestSubscription(time.Now().Add(-time.Hour), 10)}
+		s := &orderService{membershipRepo: repo}
+		order := newOrder(2)
+
+		assert.NoError(t, s.applySubscription(order))
+		assert.Equal(t, "Studio", order.MembershipType)
+		assert.Equal(t, 1, repo.renewals)
+		assert.Equal(t, 0, repo.subscription.ShotsUsed)
+	})
+
+	t.Run("Should use the plan of the member who places an organization order", func(t *testing.T) {
+		t.Parallel()
+
+		s := &orderService{membershipRepo: &stubMembershipRepository{subscription: newTestSubscription(time.Now().Add(time.Hour), 4)}}
+		organizationID := "org-1"
+		order := newOrder(1)
+		order.OrganizationID = &organizationID
+
+		assert.NoError(t, s.applySubscription(order))
+		assert.Equal(t, "Studio", order.MembershipType)
+		assert.Equal(t, "subscription-1", *order.SubscriptionID)
+	})
+
+	t.Run("Should not trust the membership type sent by the customer", func(t *testing.T) {
+		t.Parallel()
+
+		s := &orderService{membershipRepo: &stubMembershipRepository{}}
+		order := newOrder(1)
+
+		assert.NoError(t, s.applySubscription(order))
+		assert.Equal(t, model.OrderMembershipPayAsYouGo, order.MembershipType)
+	})
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/google/uuid"
+)
+
+var ErrInvalidOrderFilter = errors.New("invalid order filter")
+
+// orderStatusGroups are the groups of statuses the order list can be filtered by besides a
+// single status
+var orderStatusGroups = map[string]bool{
+	"active":    true,
+	"pending":   true,
+	"completed": true,
+	"deleted":   true,
+}
+
+// orderSortColumns are the columns the order list can be sorted by
+var orderSortColumns = map[string]bool{
+	"created_at":    true,
+	"updated_at":    true,
+	"order_name":    true,
+	"product_name":  true,
+	"status":        true,
+	"quantity":      true,
+	"quoted_amount": true,
+}
+
+// parseOrderFilter checks the query parameters of the order list. Orders are listed newest first
+// unless asked otherwise
+func parseOrderFilter(request *model.OrderFilterRequest) (*model.OrderFilter, error) {
+	filter := &model.OrderFilter{
+		Status:         strings.ToLower(strings.TrimSpace(request.Status)),
+		UserID:         strings.TrimSpace(request.UserID),
+		ShootType:      strings.TrimSpace(request.ShootType),
+		DeliverySpeed:  strings.TrimSpace(request.DeliverySpeed),
+		MembershipType: strings.TrimSpace(request.MembershipType),
+		Search:         strings.TrimSpace(request.Search),
+		Sort:           strings.ToLower(strings.TrimSpace(request.Sort)),
+		Descending:     true,
+	}
+
+	if filter.Status != "" && !orderStatusGroups[filter.Status] && !isValidOrderStatus(filter.Status) {
+		return nil, fmt.Errorf("%w: unknown status %q", ErrInvalidOrderFilter, request.Status)
+	}
+
+	if filter.UserID != "" {
+		if _, err := uuid.Parse(filter.UserID); err != nil {
+			return nil, fmt.Errorf("%w: user_id must be a UUID", ErrInvalidOrderFilter)
+		}
+	}
+
+	if filter.Sort == "" {
+		filter.Sort = "created_at"
+	}
+
+	if !orderSortColumns[filter.Sort] {
+		return nil, fmt.Errorf("%w: cannot sort by %q", ErrInvalidOrderFilter, request.Sort)
+	}
+
+	switch strings.ToLower(strings.TrimSpace(request.Order)) {
+	case "", "desc":
+	case "asc":
+		filter.Descending = false
+	default:
+		return nil, fmt.Errorf("%w: order must be asc or desc", ErrInvalidOrderFilter)
+	}
+
+	var err error
+
+	if filter.CreatedFrom, err = parseFilterTime(request.CreatedFrom, false); err != nil {
+		return nil, fmt.Errorf("%w: created_from %v", ErrInvalidOrderFilter, err)
+	}
+
+	if filter.CreatedTo, err = parseFilterTime(request.CreatedTo, true); err != nil {
+		return nil, fmt.Errorf("%w: created_to %v", ErrInvalidOrderFilter, err)
+	}
+
+	if filter.CreatedFrom != nil && filter.CreatedTo != nil && !filter.CreatedFrom.Before(*filter.CreatedTo) {
+		return nil, fmt.Errorf("%w: created_from must be before created_to", ErrInvalidOrderFilter)
+	}
+
+	return filter, nil
+}
+
+// parseFilterTime parses a date or an RFC 3339 time. When endOfDay is set, a date is moved to
+// the start of the next day so the whole day is included
+func parseFilterTime(value string, endOfDay bool) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, errors.New("must be a date (2006-01-02) or an RFC 3339 time")
+	}
+
+	if endOfDay {
+		t = t.AddDate(0, 0, 1)
+	}
+
+	return &t, nil
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseOrderFilter(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Should list the newest orders first by default", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parseOrderFilter(&model.OrderFilterRequest{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "created_at", filter.Sort)
+		assert.True(t, filter.Descending)
+		assert.Nil(t, filter.CreatedFrom)
+		assert.Nil(t, filter.CreatedTo)
+	})
+
+	t.Run("Should accept a single status and a group of statuses", func(t *testing.T) {
+		t.Parallel()
+
+		for _, status := range []string{model.OrderStatusShooting, "Pending", "deleted"} {
+			_, err := parseOrderFilter(&model.OrderFilterRequest{Status: status})
+
+			assert.NoError(t, err, status)
+		}
+	})
+
+	t.Run("Should accept the ID of a customer", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parseOrderFilter(&model.OrderFilterRequest{UserID: " 6f1c2a3e-8b7d-4c5e-9f0a-1b2c3d4e5f60 "})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "6f1c2a3e-8b7d-4c5e-9f0a-1b2c3d4e5f60", filter.UserID)
+	})
+
+	t.Run("Should include the whole last day of a date range", func(t *testing.T) {
+		t.Parallel()
+
+		filter, err := parseOrderFilter(&model.OrderFilterRequest{CreatedFrom: "2026-03-01", CreatedTo: "2026-03-31", Order: "ASC"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC), *filter.CreatedFrom)
+		assert.Equal(t, time.Date(2026, time.April, 1, 0, 0, 0, 0, time.UTC), *filter.CreatedTo)
+		assert.False(t, filter.Descending)
+	})
+
+	t.Run("Should refuse filters it cannot apply", func(t *testing.T) {
+		t.Parallel()
+
+		for _, request := range []*model.OrderFilterRequest{
+			{Status: "lost"},
+			{UserID: "not-a-uuid"},
+			{Sort: "user_id; DROP TABLE orders"},
+			{Order: "sideways"},
+			{CreatedFrom: "01/03/2026"},
+			{CreatedFrom: "2026-03-31", CreatedTo: "2026-03-01"},
+		} {
+			_, err := parseOrderFilter(request)
+
+			assert.ErrorIs(t, err, ErrInvalidOrderFilter, request)
+		}
+	})
+}
@@ -0,0 +1,260 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/MogboPython/belvaphilips_backend/internal/config"
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/MogboPython/belvaphilips_backend/pkg/utils"
+	"github.com/lib/pq"
+	"gorm.io/datatypes"
+)
+
+var (
+	ErrOrderNotEditable   = errors.New("order can only be changed before it is quoted")
+	ErrPlanShotsExhausted = errors.New("membership plan does not have enough shots left for this change")
+)
+
+// UpdateOrder changes the options of an order that was not quoted yet, records what changed and
+// lets the admin know. An order placed under a membership plan uses up or gives back plan shots
+// as its shot count changes
+func (s *orderService) UpdateOrder(orderID string, actor *model.Actor, request *model.OrderUpdateRequest) (*model.OrderResponse, error) {
+	order, err := s.orderRepo.GetByOrderID(orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find order: %w", err)
+	}
+
+	if order.Status != model.OrderStatusQuoteReceived {
+		return nil, ErrOrderNotEditable
+	}
+
+	before := *order
+
+	if err := s.applyOrderUpdate(order, request); err != nil {
+		return nil, err
+	}
+
+	changes, err := diffOrderOptions(&before, order)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(changes) == 0 {
+		return mapOrderToResponse(order), nil
+	}
+
+	shotsDelta := 0
+
+	if order.SubscriptionID != nil && order.PlanShots > 0 {
+		order.PlanShots = orderShotCount(order)
+		shotsDelta = order.PlanShots - before.PlanShots
+	}
+
+	changesJSON, err := json.Marshal(changes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal order changes: %w", err)
+	}
+
+	revision := &model.OrderRevision{
+		OrderID:       order.ID,
+		ChangedBy:     actor.ID,
+		ChangedByRole: actor.Role,
+		Changes:       datatypes.JSON(changesJSON),
+	}
+
+	emails, err := newOrderRevisionEmails(order, changes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.orderRepo.Revise(order, revision, shotsDelta, emails); err != nil {
+		if strings.Contains(err.Error(), "membership plan has no shots left") {
+			return nil, ErrPlanShotsExhausted
+		}
+
+		return nil, fmt.Errorf("failed to update order: %w", err)
+	}
+
+	return mapOrderToResponse(order), nil
+}
+
+func (s *orderService) GetOrderRevisions(orderID string) ([]*model.OrderRevision, error) {
+	if _, err := s.orderRepo.GetByOrderID(orderID); err != nil {
+		return nil, fmt.Errorf("failed to find order: %w", err)
+	}
+
+	revisions, err := s.orderRepo.GetRevisions(orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order revisions: %w", err)
+	}
+
+	return revisions, nil
+}
+
+// applyOrderUpdate sets the options sent in the request on the order, as spelled in the catalog
+func (s *orderService) applyOrderUpdate(order *model.Order, request *model.OrderUpdateRequest) error {
+	if request.FinishType != nil {
+		finishType := strings.TrimSpace(*request.FinishType)
+
+		if finishType != "" {
+			var err error
+
+			finishType, err = canonicalCatalogName(s.catalogRepo, model.CatalogCategoryFinishType, finishType)
+			if err != nil {
+				return err
+			}
+		}
+
+		order.FinishType = finishType
+	}
+
+	if request.Shots != nil {
+		shots := make(pq.StringArray, len(*request.Shots))
+
+		for i, shot := range *request.Shots {
+			var err error
+
+			shots[i], err = canonicalCatalogName(s.catalogRepo, model.CatalogCategoryShot, shot)
+			if err != nil {
+				return err
+			}
+		}
+
+		order.Shots = shots
+	}
+
+	if request.Quantity != nil {
+		order.Quantity = *request.Quantity
+	}
+
+	if request.Details != nil {
+		details, err := json.Marshal(*request.Details)
+		if err != nil {
+			return fmt.Errorf("failed to marshal details: %w", err)
+		}
+
+		order.Details = datatypes.JSON(details)
+	}
+
+	return nil
+}
+
+// diffOrderOptions returns the editable options that differ between two versions of an order,
+// keyed by their JSON name. Details are compared by value, not by their JSON encoding
+func diffOrderOptions(before, after *model.Order) (map[string]model.OrderFieldChange, error) {
+	changes := map[string]model.OrderFieldChange{}
+
+	if before.Quantity != after.Quantity {
+		changes["quantity"] = model.OrderFieldChange{From: before.Quantity, To: after.Quantity}
+	}
+
+	if before.FinishType != after.FinishType {
+		changes["finish_type"] = model.OrderFieldChange{From: before.FinishType, To: after.FinishType}
+	}
+
+	if !slices.Equal(before.Shots, after.Shots) {
+		changes["shots"] = model.OrderFieldChange{From: []string(before.Shots), To: []string(after.Shots)}
+	}
+
+	beforeDetails, err := decodeDetails(before.Details)
+	if err != nil {
+		return nil, err
+	}
+
+	afterDetails, err := decodeDetails(after.Details)
+	if err != nil {
+		return nil, err
+	}
+
+	if !reflect.DeepEqual(beforeDetails, afterDetails) {
+		changes["details"] = model.OrderFieldChange{From: beforeDetails, To: afterDetails}
+	}
+
+	return changes, nil
+}
+
+// decodeDetails decodes the details of an order, treating empty and null details as no details
+func decodeDetails(details datatypes.JSON) (map[string]any, error) {
+	var decoded map[string]any
+
+	if len(details) == 0 {
+		return nil, nil
+	}
+
+	if err := json.Unmarshal(details, &decoded); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal details: %w", err)
+	}
+
+	if len(decoded) == 0 {
+		return nil, nil
+	}
+
+	return decoded, nil
+}
+
+// newOrderRevisionEmails builds the alert telling the admin which options of an order changed
+func newOrderRevisionEmails(order *model.Order, changes map[string]model.OrderFieldChange) ([]*model.EmailOutbox, error) {
+	type changeLine struct {
+		Field, From, To string
+	}
+
+	fields := make([]string, 0, len(changes))
+	for field := range changes {
+		fields = append(fields, field)
+	}
+
+	slices.Sort(fields)
+
+	lines := make([]changeLine, len(fields))
+	for i, field := range fields {
+		lines[i] = changeLine{
+			Field: strings.ReplaceAll(field, "_", " "),
+			From:  formatChangeValue(changes[field].From),
+			To:    formatChangeValue(changes[field].To),
+		}
+	}
+
+	body, err := utils.ParseTemplate("order_modified_notification.html", map[string]any{
+		"OrderName":   order.OrderName,
+		"ProductName": order.ProductName,
+		"Changes":     lines,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse order modified email template: %w", err)
+	}
+
+	return []*model.EmailOutbox{
+		newOutboxEmail(config.Config("ADMIN_EMAIL"), "Order Modified - "+order.OrderName, body),
+	}, nil
+}
+
+func formatChangeValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "(none)"
+	case string:
+		if v == "" {
+			return "(none)"
+		}
+
+		return v
+	case []string:
+		if len(v) == 0 {
+			return "(none)"
+		}
+
+		return strings.Join(v, ", ")
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return string(encoded)
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/datatypes"
+)
+
+func TestDiffOrderOptions(t *testing.T) {
+	t.Parallel()
+
+	newOrder := func() *model.Order {
+		return &model.Order{
+			Quantity:   2,
+			FinishType: "Matte",
+			Shots:      pq.StringArray{"Front", "Back"},
+			Details:    datatypes.JSON(`{"size": "large", "colors": ["red", "blue"]}`),
+		}
+	}
+
+	t.Run("Should report nothing when the order did not change", func(t *testing.T) {
+		t.Parallel()
+
+		after := newOrder()
+		after.Details = datatypes.JSON(`{"colors":["red","blue"],"size":"large"}`)
+
+		changes, err := diffOrderOptions(newOrder(), after)
+
+		assert.NoError(t, err)
+		assert.Empty(t, changes)
+	})
+
+	t.Run("Should report every changed option with its old and new value", func(t *testing.T) {
+		t.Parallel()
+
+		after := newOrder()
+		after.Quantity = 3
+		after.FinishType = ""
+		after.Shots = pq.StringArray{"Front"}
+
+		changes, err := diffOrderOptions(newOrder(), after)
+
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]model.OrderFieldChange{
+			"quantity":    {From: 2, To: 3},
+			"finish_type": {From: "Matte", To: ""},
+			"shots":       {From: []string{"Front", "Back"}, To: []string{"Front"}},
+		}, changes)
+	})
+
+	t.Run("Should treat empty details like no details", func(t *testing.T) {
+		t.Parallel()
+
+		before := newOrder()
+		before.Details = nil
+
+		after := newOrder()
+		after.Details = datatypes.JSON(`{}`)
+
+		changes, err := diffOrderOptions(before, after)
+
+		assert.NoError(t, err)
+		assert.Empty(t, changes)
+	})
+
+	t.Run("Should report changed details", func(t *testing.T) {
+		t.Parallel()
+
+		after := newOrder()
+		after.Details = datatypes.JSON(`{"size": "small", "colors": ["red", "blue"]}`)
+
+		changes, err := diffOrderOptions(newOrder(), after)
+
+		assert.NoError(t, err)
+		assert.Contains(t, changes, "details")
+		assert.Equal(t, "small", changes["details"].To.(map[string]any)["size"])
+	})
+}
+
+func TestUpdateOrder(t *testing.T) {
+	t.Parallel()
+
+	actor := &model.Actor{ID: "user-1", Role: model.RoleAuthenticated}
+	quantity := 4
+
+	t.Run("Should refuse to change an order once it is quoted", func(t *testing.T) {
+		t.Parallel()
+
+		for _, status := range []string{model.OrderStatusQuoted, model.OrderStatusShooting, model.OrderStatusCancelled} {
+			repo := &lifecycleOrderRepository{stubOrderRepository: &stubOrderRepository{}, order: &model.Order{ID: "order-1", Status: status, Quantity: 1}}
+			s := &orderService{orderRepo: repo}
+
+			_, err := s.UpdateOrder("order-1", actor, &model.OrderUpdateRequest{Quantity: &quantity})
+
+			assert.ErrorIs(t, err, ErrOrderNotEditable, status)
+			assert.Equal(t, 1, repo.order.Quantity, status)
+		}
+	})
+
+	t.Run("Should not record a revision when nothing changed", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &lifecycleOrderRepository{stubOrderRepository: &stubOrderRepository{}, order: &model.Order{ID: "order-1", Status: model.OrderStatusQuoteReceived, Quantity: 4}}
+		s := &orderService{orderRepo: repo}
+
+		order, err := s.UpdateOrder("order-1", actor, &model.OrderUpdateRequest{Quantity: &quantity})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 4, order.Quantity)
+	})
+
+	t.Run("Should record the revision and let the admin know", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &lifecycleOrderRepository{stubOrderRepository: &stubOrderRepository{}, order: &model.Order{ID: "order-1", OrderName: "BELVA-0001", Status: model.OrderStatusQuoteReceived, Quantity: 2}}
+		s := &orderService{orderRepo: repo}
+
+		order, err := s.UpdateOrder("order-1", actor, &model.OrderUpdateRequest{Quantity: &quantity})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 4, order.Quantity)
+		assert.Equal(t, "order-1", repo.revision.OrderID)
+		assert.Equal(t, "user-1", repo.revision.ChangedBy)
+		assert.JSONEq(t, `{"quantity": {"from": 2, "to": 4}}`, string(repo.revision.Changes))
+		assert.Len(t, repo.emails, 1)
+		assert.Contains(t, repo.emails[0].Subject, "BELVA-0001")
+		assert.Zero(t, repo.shotsDelta)
+	})
+
+	t.Run("Should use up and give back plan shots as the shot count changes", func(t *testing.T) {
+		t.Parallel()
+
+		subscriptionID := "subscription-1"
+
+		for _, tc := range []struct {
+			quantity int
+			delta    int
+		}{{quantity: 5, delta: 3}, {quantity: 1, delta: -1}} {
+			repo := &lifecycleOrderRepository{stubOrderRepository: &stubOrderRepository{}, order: &model.Order{
+				ID: "order-1", Status: model.OrderStatusQuoteReceived, Quantity: 2, SubscriptionID: &subscriptionID, PlanShots: 2,
+			}}
+			s := &orderService{orderRepo: repo}
+
+			order, err := s.UpdateOrder("order-1", actor, &model.OrderUpdateRequest{Quantity: &tc.quantity})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.delta, repo.shotsDelta)
+			assert.Equal(t, tc.quantity, repo.order.PlanShots)
+			assert.Equal(t, tc.quantity, order.Quantity)
+		}
+	})
+
+	t.Run("Should refuse a change the plan does not have the shots for", func(t *testing.T) {
+		t.Parallel()
+
+		subscriptionID := "subscription-1"
+		repo := &lifecycleOrderRepository{
+			stubOrderRepository: &stubOrderRepository{},
+			order:               &model.Order{ID: "order-1", Status: model.OrderStatusQuoteReceived, Quantity: 2, SubscriptionID: &subscriptionID, PlanShots: 2},
+			reviseErr:           errors.New("membership plan has no shots left"),
+		}
+		s := &orderService{orderRepo: repo}
+
+		_, err := s.UpdateOrder("order-1", actor, &model.OrderUpdateRequest{Quantity: &quantity})
+
+		assert.ErrorIs(t, err, ErrPlanShotsExhausted)
+	})
+}
@@ -2,8 +2,9 @@ package service
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"sync"
+	"strings"
 	"time"
 
 	"github.com/MogboPython/belvaphilips_backend/internal/config"
@@ -11,31 +12,85 @@ import (
 	"github.com/MogboPython/belvaphilips_backend/pkg/model"
 	"github.com/MogboPython/belvaphilips_backend/pkg/utils"
 	"github.com/gofiber/fiber/v2/log"
+	"github.com/google/uuid"
 	"github.com/lib/pq"
 	"gorm.io/datatypes"
+	"gorm.io/gorm"
 )
 
 type OrderService interface {
 	CreateOrder(req *model.OrderRequest) (*model.OrderResponse, error)
 	GetOrderByID(id string) (*model.OrderResponse, error)
-	GetAllOrders(page, limit, status string) (model.TotalOrderResponse, error)
+	GetOrderOwner(id string) (*model.ResourceOwner, error)
+	GetAllOrders(page, limit string, request *model.OrderFilterRequest) (model.TotalOrderResponse, error)
 	GetOrdersByUserID(userID, pageStr, limitStr string) ([]*model.OrderResponse, error)
-	UpdateOrderStatus(orderID string, request *model.OrderStatusChangeRequest) (*model.OrderResponse, error)
-	// TODO: DeleteOrder(id int64) error
+	GetOrdersByOrganizationID(organizationID, pageStr, limitStr string) ([]*model.OrderResponse, error)
+	UpdateOrderStatus(orderID string, actor *model.Actor, request *model.OrderStatusChangeRequest) (*model.OrderResponse, error)
+	GetOrderStatusHistory(orderID string) ([]*model.OrderStatusHistoryResponse, error)
+	GetStatusEmailSettings() ([]*model.OrderStatusEmailSetting, error)
+	UpdateStatusEmailSetting(status string, request *model.OrderStatusEmailSettingRequest) (*model.OrderStatusEmailSetting, error)
+	PreviewQuote(orderID string) (*model.QuoteResponse, error)
+	IssueQuote(orderID string, actor *model.Actor, request *model.QuoteRequest) (*model.QuoteResponse, error)
+	GetQuote(orderID string) (*model.QuoteResponse, error)
+	AcceptQuote(orderID string, actor *model.Actor) (*model.OrderResponse, error)
+	RejectQuote(orderID string, actor *model.Actor, request *model.QuoteRejectRequest) (*model.OrderResponse, error)
+	CancelOrder(orderID string, actor *model.Actor, request *model.OrderCancelRequest) (*model.OrderResponse, error)
+	DeleteOrder(orderID string) error
+	RestoreOrder(orderID string) (*model.OrderResponse, error)
+	UpdateOrder(orderID string, actor *model.Actor, request *model.OrderUpdateRequest) (*model.OrderResponse, error)
+	GetOrderRevisions(orderID string) ([]*model.OrderRevision, error)
 }
 
 type orderService struct {
-	orderRepo repository.OrderRepository
+	orderRepo        repository.OrderRepository
+	userRepo         repository.UserRepository
+	catalogRepo      repository.CatalogRepository
+	organizationRepo repository.OrganizationRepository
+	preferenceRepo   repository.NotificationPreferenceRepository
+	membershipRepo   repository.MembershipRepository
+	prices           PriceList
 }
 
-func NewOrderService(orderRepo repository.OrderRepository) OrderService {
+func NewOrderService(orderRepo repository.OrderRepository, userRepo repository.UserRepository, catalogRepo repository.CatalogRepository, organizationRepo repository.OrganizationRepository, preferenceRepo repository.NotificationPreferenceRepository, membershipRepo repository.MembershipRepository) OrderService {
 	return &orderService{
-		orderRepo: orderRepo,
+		orderRepo:        orderRepo,
+		userRepo:         userRepo,
+		catalogRepo:      catalogRepo,
+		organizationRepo: organizationRepo,
+		preferenceRepo:   preferenceRepo,
+		membershipRepo:   membershipRepo,
+		prices:           NewCatalogPriceList(catalogRepo),
 	}
 }
 
 func (s *orderService) CreateOrder(request *model.OrderRequest) (*model.OrderResponse, error) {
-	var mails = 2
+	user, err := s.userRepo.GetByID(request.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("failed to find user")
+		}
+
+		return nil, err
+	}
+
+	if err := s.normalizeOrderOptions(request); err != nil {
+		return nil, err
+	}
+
+	var organizationID *string
+
+	if request.OrganizationID != "" {
+		member, err := s.organizationRepo.IsMember(request.OrganizationID, user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check organization membership: %w", err)
+		}
+
+		if !member {
+			return nil, ErrNotOrganizationMember
+		}
+
+		organizationID = &request.OrganizationID
+	}
 
 	detailsBytes, err := json.Marshal(request.Details)
 	if err != nil {
@@ -47,96 +102,175 @@ func (s *orderService) CreateOrder(request *model.OrderRequest) (*model.OrderRes
 	shotsArray := pq.StringArray(request.Shots)
 
 	order := &model.Order{
+		ID:                 uuid.New().String(),
+		CreatedAt:          time.Now(),
+		User:               *user,
 		UserID:             request.UserID,
+		OrganizationID:     organizationID,
 		ProductName:        request.ProductName,
 		ProductDescription: request.ProductDescription,
 		Details:            detailsJSON,
 		FinishType:         request.FinishType,
 		Quantity:           request.Quantity,
 		ShootType:          request.ShootType,
-		Status:             request.Status,
-		MembershipType:     request.MembershipType,
+		Status:             model.OrderStatusQuoteReceived,
 		Shots:              shotsArray,
 		DeliverySpeed:      request.DeliverySpeed,
 	}
 
-	if err := s.orderRepo.Create(order); err != nil {
-		log.Error("error saving order: ", err)
+	emails, err := newOrderNotificationEmails(s.preferenceRepo, order)
+	if err != nil {
 		return nil, err
 	}
 
-	s.sendOrderNotificationEmailsConcurrently(order, mails)
+	if err := s.applySubscription(order); err != nil {
+		return nil, err
+	}
+
+	err = s.orderRepo.Create(order, emails)
+
+	// Another order used up the plan in the meantime
+	if err != nil && strings.Contains(err.Error(), "membership plan has no shots left") {
+		payAsYouGo(order)
+
+		err = s.orderRepo.Create(order, emails)
+	}
+
+	if err != nil {
+		log.Error("error saving order: ", err)
+		return nil, err
+	}
 
 	return mapOrderToResponse(order), nil
 }
 
-func (*orderService) sendOrderNotificationEmailsConcurrently(order *model.Order, mailsToSend int) {
-	// TODO: read this
-	var wg sync.WaitGroup
+// applySubscription places an order under the active plan of its user when the plan still has
+// shots for all of it, and as pay as you go otherwise. Plans without included shots only give
+// their discount. Plans belong to people rather than organizations, so an organization order
+// uses up the plan of the member who places it
+func (s *orderService) applySubscription(order *model.Order) error {
+	payAsYouGo(order)
 
-	wg.Add(mailsToSend)
+	subscription, err := activeSubscription(s.membershipRepo, order.UserID, time.Now())
+	if err != nil {
+		return err
+	}
 
-	go func() {
-		defer wg.Done()
+	if subscription == nil {
+		return nil
+	}
 
-		to := order.User.Email
-		subject := "Your Quote Request at BelvaPhilips Imagery - Confirmation!"
-		data := map[string]string{
-			"Name":        order.User.Name,
-			"ProductName": order.ProductName,
-			"OrderDate":   order.CreatedAt.Format(time.UnixDate),
-		}
-		body, err := utils.ParseTemplate("user_confirmation.html", data)
+	shots := 0
 
-		if err != nil {
-			log.Errorf("Failed to parse user confirmation email template for Order ID %v: %v", order.ID, err)
-			return
-		}
+	if subscription.Plan.IncludedShots > 0 {
+		shots = orderShotCount(order)
 
-		_, err = utils.SendEmail(to, subject, body)
-		if err != nil {
-			log.Errorf("Failed to send confirmation email to user %s for Order ID %v: %v", to, order.ID, err)
-			return
+		if shots > subscription.ShotsRemaining() {
+			return nil
 		}
+	}
 
-		log.Infof("Successfully sent confirmation email to user %s for Order ID: %v", to, order.ID)
-	}()
+	order.MembershipType = subscription.Plan.Name
+	order.SubscriptionID = &subscription.ID
+	order.PlanShots = shots
+	order.DiscountPercent = subscription.Plan.DiscountPercent
 
-	go func() {
-		defer wg.Done()
+	return nil
+}
 
-		toAdmin := config.Config("ADMIN_EMAIL")
-		subjectAdmin := "New Quote Request - Immediate Action Required!"
-		dataAdmin := map[string]string{
-			"OrderID":     order.ID,
-			"ProductName": order.ProductName,
-			"OrderDate":   order.CreatedAt.Format(time.UnixDate),
+func payAsYouGo(order *model.Order) {
+	order.MembershipType = model.OrderMembershipPayAsYouGo
+	order.SubscriptionID = nil
+	order.PlanShots = 0
+	order.DiscountPercent = 0
+}
+
+// normalizeOrderOptions rejects options that are not in the active catalog and
+// rewrites the accepted ones as spelled in the catalog
+func (s *orderService) normalizeOrderOptions(request *model.OrderRequest) error {
+	var err error
+
+	request.ShootType, err = canonicalCatalogName(s.catalogRepo, model.CatalogCategoryShootType, request.ShootType)
+	if err != nil {
+		return err
+	}
+
+	if request.FinishType != "" {
+		request.FinishType, err = canonicalCatalogName(s.catalogRepo, model.CatalogCategoryFinishType, request.FinishType)
+		if err != nil {
+			return err
 		}
-		bodyAdmin, err := utils.ParseTemplate("order_notification.html", dataAdmin)
+	}
 
+	if request.DeliverySpeed != "" {
+		request.DeliverySpeed, err = canonicalCatalogName(s.catalogRepo, model.CatalogCategoryDeliverySpeed, request.DeliverySpeed)
 		if err != nil {
-			log.Errorf("Failed to parse admin notification email template for Order ID %v: %v", order.ID, err)
-			return
+			return err
 		}
+	}
 
-		_, err = utils.SendEmail(toAdmin, subjectAdmin, bodyAdmin)
+	for i, shot := range request.Shots {
+		request.Shots[i], err = canonicalCatalogName(s.catalogRepo, model.CatalogCategoryShot, shot)
 		if err != nil {
-			log.Errorf("Failed to send notification email to admin %s for Order ID %v: %v", toAdmin, order.ID, err)
-			return
+			return err
 		}
+	}
+
+	return nil
+}
+
+// newOrderNotificationEmails builds the alert for the admin and the confirmation email for the
+// customer, unless they turned off order updates by email
+func newOrderNotificationEmails(preferenceRepo repository.NotificationPreferenceRepository, order *model.Order) ([]*model.EmailOutbox, error) {
+	dataAdmin := map[string]string{
+		"OrderID":     order.ID,
+		"ProductName": order.ProductName,
+		"OrderDate":   order.CreatedAt.Format(time.UnixDate),
+	}
+
+	bodyAdmin, err := utils.ParseTemplate("order_notification.html", dataAdmin)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse admin notification email template: %w", err)
+	}
+
+	emails := []*model.EmailOutbox{
+		newOutboxEmail(config.Config("ADMIN_EMAIL"), "New Quote Request - Immediate Action Required!", bodyAdmin),
+	}
+
+	allowed, err := notificationAllowed(preferenceRepo, order.UserID, model.NotificationChannelEmail, model.NotificationCategoryOrderUpdates)
+	if err != nil {
+		return nil, err
+	}
+
+	if !allowed {
+		return emails, nil
+	}
 
-		log.Infof("Successfully sent notification email to admin %s for Order ID: %v", toAdmin, order.ID)
-	}()
+	data := map[string]string{
+		"Name":        order.User.Name,
+		"ProductName": order.ProductName,
+		"OrderDate":   order.CreatedAt.Format(time.UnixDate),
+	}
 
-	wg.Wait()
+	body, err := utils.ParseTemplate("user_confirmation.html", data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse user confirmation email template: %w", err)
+	}
+
+	return append(emails, forUser(order.UserID, newOutboxEmail(order.User.Email, "Your Quote Request at BelvaPhilips Imagery - Confirmation!", body))), nil
 }
 
-func (s *orderService) GetAllOrders(pageStr, limitStr, status string) (model.TotalOrderResponse, error) {
+func (s *orderService) GetAllOrders(pageStr, limitStr string, request *model.OrderFilterRequest) (model.TotalOrderResponse, error) {
 	var totalOrderResponse model.TotalOrderResponse
 
+	filter, err := parseOrderFilter(request)
+	if err != nil {
+		return totalOrderResponse, err
+	}
+
 	offset, limit := utils.GetPageAndLimitInt(pageStr, limitStr)
 
-	orders, ordersCount, err := s.orderRepo.GetAll(offset, limit, status)
+	orders, ordersCount, err := s.orderRepo.GetAll(offset, limit, filter)
 	if err != nil {
 		return totalOrderResponse, fmt.Errorf("failed to get orders: %w", err)
 	}
@@ -161,10 +295,21 @@ func (s *orderService) GetOrderByID(id string) (*model.OrderResponse, error) {
 	return mapOrderToResponse(order), nil
 }
 
+// GetOrderOwner returns the user who placed an order and the organization it was placed for
+func (s *orderService) GetOrderOwner(id string) (*model.ResourceOwner, error) {
+	order, err := s.orderRepo.GetByOrderID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find order: %w", err)
+	}
+
+	return orderOwner(order), nil
+}
+
+// GetOrdersByUserID returns the orders a user placed and the orders of their organizations
 func (s *orderService) GetOrdersByUserID(userID, pageStr, limitStr string) ([]*model.OrderResponse, error) {
 	offset, limit := utils.GetPageAndLimitInt(pageStr, limitStr)
 
-	orders, err := s.orderRepo.GetByUserID(userID, offset, limit)
+	orders, err := s.orderRepo.GetVisibleToUser(userID, offset, limit)
 	if err != nil {
 		return nil, fmt.Errorf("failed to find orders: %w", err)
 	}
@@ -177,15 +322,530 @@ func (s *orderService) GetOrdersByUserID(userID, pageStr, limitStr string) ([]*m
 	return orderResponses, nil
 }
 
-func (s *orderService) UpdateOrderStatus(orderID string, request *model.OrderStatusChangeRequest) (*model.OrderResponse, error) {
-	order, err := s.orderRepo.Update(orderID, request.Status)
+// GetOrdersByOrganizationID returns the orders placed for an organization by any of its members
+func (s *orderService) GetOrdersByOrganizationID(organizationID, pageStr, limitStr string) ([]*model.OrderResponse, error) {
+	offset, limit := utils.GetPageAndLimitInt(pageStr, limitStr)
+
+	orders, err := s.orderRepo.GetByOrganizationID(organizationID, offset, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find orders: %w", err)
+	}
+
+	orderResponses := make([]*model.OrderResponse, len(orders))
+	for i, order := range orders {
+		orderResponses[i] = mapOrderToResponse(order)
+	}
+
+	return orderResponses, nil
+}
+
+func orderOwner(order *model.Order) *model.ResourceOwner {
+	owner := &model.ResourceOwner{UserID: order.UserID}
+	if order.OrganizationID != nil {
+		owner.OrganizationID = *order.OrganizationID
+	}
+
+	return owner
+}
+
+func (s *orderService) UpdateOrderStatus(orderID string, actor *model.Actor, request *model.OrderStatusChangeRequest) (*model.OrderResponse, error) {
+	order, err := s.orderRepo.GetByOrderID(orderID)
 	if err != nil {
+		return nil, fmt.Errorf("failed to find order: %w", err)
+	}
+
+	// Only a successful payment marks an order paid, together with its invoice
+	if request.Status == model.OrderStatusPaid {
+		return nil, fmt.Errorf("%w: orders are marked paid by their payment", ErrInvalidStatusTransition)
+	}
+
+	if err := validateStatusTransition(order.Status, request.Status); err != nil {
+		return nil, err
+	}
+
+	history := &model.OrderStatusHistory{
+		OrderID:       order.ID,
+		FromStatus:    order.Status,
+		ToStatus:      request.Status,
+		ChangedBy:     actor.ID,
+		ChangedByRole: actor.Role,
+		Note:          request.Note,
+	}
+
+	emails, err := newOrderStatusNotifications(s.orderRepo, s.preferenceRepo, order, request.Status, request.Note)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.orderRepo.UpdateStatus(order, history, emails); err != nil {
 		return nil, fmt.Errorf("failed to update order: %w", err)
 	}
 
 	return mapOrderToResponse(order), nil
 }
 
+var (
+	ErrOrderNotCancellable = errors.New("order can no longer be cancelled")
+	ErrOrderInProgress     = errors.New("order is in progress and cannot be deleted")
+)
+
+// customerCancellableStatuses are the statuses an order can be cancelled from by its customer,
+// which is until the shoot starts and as long as nothing was paid. Paid orders go through a
+// refund by staff, who can still cancel later orders through UpdateOrderStatus
+var customerCancellableStatuses = map[string]bool{
+	model.OrderStatusQuoteReceived:   true,
+	model.OrderStatusQuoted:          true,
+	model.OrderStatusAccepted:        true,
+	model.OrderStatusProductReceived: true,
+}
+
+// deletableOrderStatuses are the statuses of orders with no invoice or payment still open
+var deletableOrderStatuses = map[string]bool{
+	model.OrderStatusQuoteReceived: true,
+	model.OrderStatusQuoted:        true,
+	model.OrderStatusMarkCompleted: true,
+	model.OrderStatusCancelled:     true,
+}
+
+// CancelOrder cancels an order for the customer who placed it, or a member of its organization,
+// before the shoot starts
+func (s *orderService) CancelOrder(orderID string, actor *model.Actor, request *model.OrderCancelRequest) (*model.OrderResponse, error) {
+	order, err := s.orderRepo.GetByOrderID(orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find order: %w", err)
+	}
+
+	if !customerCancellableStatuses[order.Status] {
+		return nil, fmt.Errorf("%w: the order is %s", ErrOrderNotCancellable, order.Status)
+	}
+
+	reason := strings.TrimSpace(request.Reason)
+
+	history := &model.OrderStatusHistory{
+		OrderID:       order.ID,
+		FromStatus:    order.Status,
+		ToStatus:      model.OrderStatusCancelled,
+		ChangedBy:     actor.ID,
+		ChangedByRole: actor.Role,
+		Note:          "Cancelled by customer: " + reason,
+	}
+
+	emails, err := newOrderStatusNotifications(s.orderRepo, s.preferenceRepo, order, model.OrderStatusCancelled, reason)
+	if err != nil {
+		return nil, err
+	}
+
+	bodyAdmin, err := utils.ParseTemplate("order_cancelled_notification.html", map[string]string{
+		"OrderName":      order.OrderName,
+		"ProductName":    order.ProductName,
+		"PreviousStatus": order.Status,
+		"Reason":         reason,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse cancellation email template: %w", err)
+	}
+
+	emails = append(emails, newOutboxEmail(config.Config("ADMIN_EMAIL"), "Order Cancelled - "+order.OrderName, bodyAdmin))
+
+	order.CancellationReason = reason
+
+	if err := s.orderRepo.Cancel(order, history, emails); err != nil {
+		// Products can be received before the invoice is paid, so payment is only known for sure here
+		if strings.Contains(err.Error(), "order has a paid invoice") {
+			return nil, fmt.Errorf("%w: the order was paid, contact us for a refund", ErrOrderNotCancellable)
+		}
+
+		return nil, fmt.Errorf("failed to cancel order: %w", err)
+	}
+
+	return mapOrderToResponse(order), nil
+}
+
+// DeleteOrder hides an order from customers and staff until it is restored. Orders with an
+// invoice or payment that may still change are refused
+func (s *orderService) DeleteOrder(orderID string) error {
+	order, err := s.orderRepo.GetByOrderID(orderID)
+	if err != nil {
+		return fmt.Errorf("failed to find order: %w", err)
+	}
+
+	if !deletableOrderStatuses[order.Status] {
+		return fmt.Errorf("%w: the order is %s", ErrOrderInProgress, order.Status)
+	}
+
+	if err := s.orderRepo.Delete(orderID); err != nil {
+		return fmt.Errorf("failed to delete order: %w", err)
+	}
+
+	log.Infof("Deleted order %s", orderID)
+
+	return nil
+}
+
+func (s *orderService) RestoreOrder(orderID string) (*model.OrderResponse, error) {
+	order, err := s.orderRepo.Restore(orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore order: %w", err)
+	}
+
+	log.Infof("Restored order %s", orderID)
+
+	return mapOrderToResponse(order), nil
+}
+
+// orderStatusEmailSubjects holds the subject of the email sent to the customer for each status.
+// The body is rendered from templates/order_status_<status>.html
+var orderStatusEmailSubjects = map[string]string{
+	model.OrderStatusQuoted:          "Your Photography Quote Is Ready - BelvaPhilips Imagery",
+	model.OrderStatusAccepted:        "Your Order Is Confirmed - BelvaPhilips Imagery",
+	model.OrderStatusPaid:            "We Have Received Your Payment - BelvaPhilips Imagery",
+	model.OrderStatusProductReceived: "We Have Received Your Products - BelvaPhilips Imagery",
+	model.OrderStatusShooting:        "Your Shoot Has Started - BelvaPhilips Imagery",
+	model.OrderStatusEditing:         "Your Photos Are Being Edited - BelvaPhilips Imagery",
+	model.OrderStatusDelivered:       "Your Photos Have Been Delivered - BelvaPhilips Imagery",
+	model.OrderStatusMarkCompleted:   "Your Order Is Complete - BelvaPhilips Imagery",
+	model.OrderStatusCancelled:       "Your Order Has Been Cancelled - BelvaPhilips Imagery",
+}
+
+// orderStatusTextMessages holds the SMS and WhatsApp message sent to customers who turned them
+// on when an order enters a status. %s is the name of the order
+var orderStatusTextMessages = map[string]string{
+	model.OrderStatusProductReceived: "BelvaPhilips Imagery: we have received the products for order %s. We will let you know when your photos are ready.",
+	model.OrderStatusDelivered:       "BelvaPhilips Imagery: the photos of order %s have been delivered. Sign in to your account to download them.",
+}
+
+// newOrderStatusNotifications builds the messages for a customer whose order enters status: the
+// email, unless it is disabled for the status, and an SMS or WhatsApp message for the statuses
+// that have one. Each is only sent on the channels the customer turned on for order updates
+func newOrderStatusNotifications(orderRepo repository.OrderRepository, preferenceRepo repository.NotificationPreferenceRepository, order *model.Order, status, note string) ([]*model.EmailOutbox, error) {
+	_, hasEmail := orderStatusEmailSubjects[status]
+	_, hasText := orderStatusTextMessages[status]
+
+	if !hasEmail && !hasText {
+		return nil, nil
+	}
+
+	preference, err := preferenceRepo.Get(order.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find notification preferences of user %s: %w", order.UserID, err)
+	}
+
+	messages, err := newOrderStatusEmails(orderRepo, preference, order, status, note)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(messages, newOrderStatusTextMessages(preference, order, status)...), nil
+}
+
+// newOrderStatusEmails builds the customer email for an order entering status, unless it is
+// disabled or the customer turned off order updates by email
+func newOrderStatusEmails(orderRepo repository.OrderRepository, preference *model.NotificationPreference, order *model.Order, status, note string) ([]*model.EmailOutbox, error) {
+	subject, ok := orderStatusEmailSubjects[status]
+	if !ok {
+		return nil, nil
+	}
+
+	if !preference.Allows(model.NotificationChannelEmail, model.NotificationCategoryOrderUpdates) {
+		log.Infof("User %s turned off order update emails, skipping Order ID: %v", order.UserID, order.ID)
+		return nil, nil
+	}
+
+	setting, err := orderRepo.GetStatusEmailSetting(status)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to read email setting for status %s: %w", status, err)
+	}
+
+	if setting != nil && !setting.EmailEnabled {
+		log.Infof("Status emails for %s are disabled, skipping Order ID: %v", status, order.ID)
+		return nil, nil
+	}
+
+	data := map[string]string{
+		"Name":        order.User.Name,
+		"OrderName":   order.OrderName,
+		"ProductName": order.ProductName,
+		"UpdatedDate": time.Now().Format(time.UnixDate),
+		"Note":        note,
+	}
+
+	if order.QuotedAmount > 0 {
+		data["QuoteTotal"] = utils.FormatAmount(order.Currency, order.QuotedAmount)
+	}
+
+	body, err := utils.ParseTemplate(fmt.Sprintf("order_status_%s.html", status), data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s email template: %w", status, err)
+	}
+
+	return []*model.EmailOutbox{forUser(order.UserID, newOutboxEmail(order.User.Email, subject, body))}, nil
+}
+
+// newOrderStatusTextMessages builds the SMS and WhatsApp messages for an order entering status
+// on the channels the customer turned on. Customers without a phone number get none
+func newOrderStatusTextMessages(preference *model.NotificationPreference, order *model.Order, status string) []*model.EmailOutbox {
+	text, ok := orderStatusTextMessages[status]
+	if !ok || order.User.PhoneNumber == "" {
+		return nil
+	}
+
+	var messages []*model.EmailOutbox
+
+	for _, channel := range []string{model.NotificationChannelWhatsApp, model.NotificationChannelSMS} {
+		if preference.Allows(channel, model.NotificationCategoryOrderUpdates) {
+			message := newOutboxTextMessage(channel, order.User.PhoneNumber, fmt.Sprintf(text, order.OrderName))
+			messages = append(messages, forUser(order.UserID, message))
+		}
+	}
+
+	return messages
+}
+
+func (s *orderService) GetStatusEmailSettings() ([]*model.OrderStatusEmailSetting, error) {
+	settings, err := s.orderRepo.GetStatusEmailSettings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get status email settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+func (s *orderService) UpdateStatusEmailSetting(status string, request *model.OrderStatusEmailSettingRequest) (*model.OrderStatusEmailSetting, error) {
+	if _, ok := orderStatusEmailSubjects[status]; !ok {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidOrderStatus, status)
+	}
+
+	setting := &model.OrderStatusEmailSetting{
+		Status:       status,
+		EmailEnabled: *request.EmailEnabled,
+	}
+
+	if err := s.orderRepo.SaveStatusEmailSetting(setting); err != nil {
+		return nil, fmt.Errorf("failed to update status email setting: %w", err)
+	}
+
+	return setting, nil
+}
+
+func (s *orderService) GetOrderStatusHistory(orderID string) ([]*model.OrderStatusHistoryResponse, error) {
+	if _, err := s.orderRepo.GetByOrderID(orderID); err != nil {
+		return nil, fmt.Errorf("failed to find order: %w", err)
+	}
+
+	history, err := s.orderRepo.GetStatusHistory(orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order history: %w", err)
+	}
+
+	historyResponses := make([]*model.OrderStatusHistoryResponse, len(history))
+	for i, entry := range history {
+		historyResponses[i] = &model.OrderStatusHistoryResponse{
+			ID:            entry.ID,
+			OrderID:       entry.OrderID,
+			FromStatus:    entry.FromStatus,
+			ToStatus:      entry.ToStatus,
+			ChangedBy:     entry.ChangedBy,
+			ChangedByRole: entry.ChangedByRole,
+			Note:          entry.Note,
+			CreatedAt:     entry.CreatedAt,
+		}
+	}
+
+	return historyResponses, nil
+}
+
+var ErrNoPendingQuote = errors.New("order has no pending quote")
+
+const defaultCurrency = "NGN"
+
+// PreviewQuote prices an order from its options without saving anything, so an admin can review
+// and adjust the line items before issuing the quote
+func (s *orderService) PreviewQuote(orderID string) (*model.QuoteResponse, error) {
+	order, err := s.orderRepo.GetByOrderID(orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find order: %w", err)
+	}
+
+	lineItems, err := buildQuoteLineItems(order, s.prices)
+	if err != nil && !errors.Is(err, ErrMissingPrice) {
+		return nil, err
+	}
+
+	lineItems = withPlanDiscount(order, lineItems)
+
+	quote := &model.Quote{
+		OrderID:   order.ID,
+		Status:    model.QuoteStatusPending,
+		Currency:  quoteCurrency(""),
+		LineItems: lineItems,
+		Total:     sumLineItems(lineItems),
+	}
+
+	// Options without a price are listed in the notes for the admin to fill in
+	if err != nil {
+		quote.Notes = err.Error()
+	}
+
+	return mapQuoteToResponse(quote), nil
+}
+
+func (s *orderService) IssueQuote(orderID string, actor *model.Actor, request *model.QuoteRequest) (*model.QuoteResponse, error) {
+	order, err := s.orderRepo.GetByOrderID(orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find order: %w", err)
+	}
+
+	if err := validateStatusTransition(order.Status, model.OrderStatusQuoted); err != nil {
+		return nil, err
+	}
+
+	lineItems := lineItemsFromRequest(request.LineItems)
+	if len(lineItems) == 0 {
+		lineItems, err = buildQuoteLineItems(order, s.prices)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	lineItems = withPlanDiscount(order, lineItems)
+
+	quote := &model.Quote{
+		OrderID:   order.ID,
+		Status:    model.QuoteStatusPending,
+		Currency:  quoteCurrency(request.Currency),
+		Notes:     request.Notes,
+		IssuedBy:  actor.ID,
+		LineItems: lineItems,
+		Total:     sumLineItems(lineItems),
+	}
+
+	history := &model.OrderStatusHistory{
+		OrderID:       order.ID,
+		FromStatus:    order.Status,
+		ToStatus:      model.OrderStatusQuoted,
+		ChangedBy:     actor.ID,
+		ChangedByRole: actor.Role,
+		Note:          "Quote issued",
+	}
+
+	order.QuotedAmount = quote.Total
+	order.Currency = quote.Currency
+
+	emails, err := newOrderStatusNotifications(s.orderRepo, s.preferenceRepo, order, model.OrderStatusQuoted, request.Notes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.orderRepo.CreateQuote(order, quote, history, emails); err != nil {
+		return nil, fmt.Errorf("failed to save quote: %w", err)
+	}
+
+	return mapQuoteToResponse(quote), nil
+}
+
+func (s *orderService) GetQuote(orderID string) (*model.QuoteResponse, error) {
+	quote, err := s.orderRepo.GetLatestQuote(orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find quote: %w", err)
+	}
+
+	return mapQuoteToResponse(quote), nil
+}
+
+func (s *orderService) AcceptQuote(orderID string, actor *model.Actor) (*model.OrderResponse, error) {
+	return s.respondToQuote(orderID, actor, model.QuoteStatusAccepted, "")
+}
+
+func (s *orderService) RejectQuote(orderID string, actor *model.Actor, request *model.QuoteRejectRequest) (*model.OrderResponse, error) {
+	return s.respondToQuote(orderID, actor, model.QuoteStatusRejected, request.Reason)
+}
+
+// respondToQuote records the customer's answer to the pending quote. Accepting moves the order
+// forward, rejecting sends it back to quote_received so a new quote can be issued
+func (s *orderService) respondToQuote(orderID string, actor *model.Actor, quoteStatus, reason string) (*model.OrderResponse, error) {
+	order, err := s.orderRepo.GetByOrderID(orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find order: %w", err)
+	}
+
+	quote, err := s.orderRepo.GetLatestQuote(orderID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to find quote: %w", err)
+	}
+
+	if quote == nil || quote.Status != model.QuoteStatusPending || order.Status != model.OrderStatusQuoted {
+		return nil, ErrNoPendingQuote
+	}
+
+	nextStatus, note := model.OrderStatusAccepted, "Quote accepted"
+	if quoteStatus == model.QuoteStatusRejected {
+		nextStatus, note = model.OrderStatusQuoteReceived, "Quote rejected: "+reason
+	}
+
+	now := time.Now()
+	quote.Status = quoteStatus
+	quote.RejectionReason = reason
+	quote.RespondedAt = &now
+
+	history := &model.OrderStatusHistory{
+		OrderID:       order.ID,
+		FromStatus:    order.Status,
+		ToStatus:      nextStatus,
+		ChangedBy:     actor.ID,
+		ChangedByRole: actor.Role,
+		Note:          note,
+	}
+
+	emails, err := newOrderStatusNotifications(s.orderRepo, s.preferenceRepo, order, nextStatus, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.orderRepo.RespondToQuote(order, quote, history, emails); err != nil {
+		return nil, fmt.Errorf("failed to update quote: %w", err)
+	}
+
+	return mapOrderToResponse(order), nil
+}
+
+func quoteCurrency(currency string) string {
+	if currency != "" {
+		return strings.ToUpper(currency)
+	}
+
+	if configured := config.Config("DEFAULT_CURRENCY"); configured != "" {
+		return configured
+	}
+
+	return defaultCurrency
+}
+
+func mapQuoteToResponse(quote *model.Quote) *model.QuoteResponse {
+	lineItems := make([]*model.QuoteLineItemResponse, len(quote.LineItems))
+	for i, item := range quote.LineItems {
+		lineItems[i] = &model.QuoteLineItemResponse{
+			Kind:        item.Kind,
+			Description: item.Description,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice,
+			Amount:      item.Amount,
+		}
+	}
+
+	return &model.QuoteResponse{
+		ID:              quote.ID,
+		OrderID:         quote.OrderID,
+		Status:          quote.Status,
+		Currency:        quote.Currency,
+		Notes:           quote.Notes,
+		RejectionReason: quote.RejectionReason,
+		LineItems:       lineItems,
+		Total:           quote.Total,
+		RespondedAt:     quote.RespondedAt,
+		CreatedAt:       quote.CreatedAt,
+	}
+}
+
 func mapOrderToResponse(order *model.Order) *model.OrderResponse {
 	var detailsMap map[string]any
 
@@ -199,10 +859,16 @@ func mapOrderToResponse(order *model.Order) *model.OrderResponse {
 		}
 	}
 
+	var deletedAt *time.Time
+	if order.DeletedAt.Valid {
+		deletedAt = &order.DeletedAt.Time
+	}
+
 	return &model.OrderResponse{
 		ID:                   order.ID,
 		OrderName:            order.OrderName,
 		UserID:               order.User.ID,
+		OrganizationID:       order.OrganizationID,
 		UserEmail:            order.User.Email,
 		UserMembershipStatus: order.User.MembershipStatus,
 		ProductName:          order.ProductName,
@@ -214,8 +880,14 @@ func mapOrderToResponse(order *model.Order) *model.OrderResponse {
 		Shots:                shotsStringArray,
 		DeliverySpeed:        order.DeliverySpeed,
 		Status:               order.Status,
+		CancellationReason:   order.CancellationReason,
 		MembershipType:       order.MembershipType,
+		PlanShots:            order.PlanShots,
+		DiscountPercent:      order.DiscountPercent,
+		QuotedAmount:         order.QuotedAmount,
+		Currency:             order.Currency,
 		CreatedAt:            order.CreatedAt,
 		UpdatedAt:            order.UpdatedAt,
+		DeletedAt:            deletedAt,
 	}
 }
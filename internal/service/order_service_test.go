@@ -0,0 +1,179 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// lifecycleOrderRepository holds a single order and records the orders deleted and the
+// cancellations and revisions saved. cancelErr and reviseErr are returned by Cancel and Revise,
+// as for an order with a paid invoice or a plan without shots left
+type lifecycleOrderRepository struct {
+	cancelErr error
+	reviseErr error
+	*stubOrderRepository
+	order      *model.Order
+	cancelled  *model.Order
+	history    *model.OrderStatusHistory
+	revision   *model.OrderRevision
+	deleted    []string
+	emails     []*model.EmailOutbox
+	shotsDelta int
+}
+
+func (r *lifecycleOrderRepository) GetByOrderID(orderID string) (*model.Order, error) {
+	if r.order == nil || r.order.ID != orderID {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return r.order, nil
+}
+
+func (r *lifecycleOrderRepository) Cancel(order *model.Order, history *model.OrderStatusHistory, emails []*model.EmailOutbox) error {
+	if r.cancelErr != nil {
+		return r.cancelErr
+	}
+
+	copied := *order
+	r.cancelled, r.history, r.emails = &copied, history, emails
+	order.Status = history.ToStatus
+
+	return nil
+}
+
+func (r *lifecycleOrderRepository) Revise(order *model.Order, revision *model.OrderRevision, shotsDelta int, emails []*model.EmailOutbox) error {
+	if r.reviseErr != nil {
+		return r.reviseErr
+	}
+
+	r.revision, r.shotsDelta, r.emails = revision, shotsDelta, emails
+
+	return nil
+}
+
+func (r *lifecycleOrderRepository) Delete(id string) error {
+	r.deleted = append(r.deleted, id)
+
+	return nil
+}
+
+func TestCancelOrder(t *testing.T) {
+	t.Parallel()
+
+	actor := &model.Actor{ID: "user-1", Role: model.RoleAuthenticated}
+
+	t.Run("Should cancel the order with the reason and notify the admin", func(t *testing.T) {
+		t.Parallel()
+
+		subscriptionID := "subscription-1"
+		repo := &lifecycleOrderRepository{stubOrderRepository: &stubOrderRepository{}, order: &model.Order{
+			ID:             "order-1",
+			OrderName:      "BELVA-0001",
+			Status:         model.OrderStatusAccepted,
+			SubscriptionID: &subscriptionID,
+			PlanShots:      4,
+			User:           model.User{ID: "user-1", Email: "client@example.com"},
+		}}
+		s := &orderService{orderRepo: repo, preferenceRepo: &stubPreferenceRepository{preferences: map[string]*model.NotificationPreference{}}}
+
+		order, err := s.CancelOrder("order-1", actor, &model.OrderCancelRequest{Reason: "  Changed my mind "})
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.OrderStatusCancelled, order.Status)
+		assert.Equal(t, "Changed my mind", repo.cancelled.CancellationReason)
+		assert.Equal(t, model.OrderStatusAccepted, repo.history.FromStatus)
+		assert.Equal(t, model.OrderStatusCancelled, repo.history.ToStatus)
+		assert.Equal(t, "user-1", repo.history.ChangedBy)
+		assert.Equal(t, "Cancelled by customer: Changed my mind", repo.history.Note)
+		assert.Len(t, repo.emails, 1)
+		assert.Contains(t, repo.emails[0].Body, "Changed my mind")
+
+		// The repository releases the plan shots of the subscription and voids the open invoice
+		assert.Equal(t, &subscriptionID, repo.cancelled.SubscriptionID)
+		assert.Equal(t, 4, repo.cancelled.PlanShots)
+	})
+
+	t.Run("Should refuse to cancel an order with a paid invoice", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &lifecycleOrderRepository{
+			stubOrderRepository: &stubOrderRepository{},
+			order:               &model.Order{ID: "order-1", Status: model.OrderStatusProductReceived},
+			cancelErr:           errors.New("order has a paid invoice"),
+		}
+		s := &orderService{orderRepo: repo, preferenceRepo: &stubPreferenceRepository{preferences: map[string]*model.NotificationPreference{}}}
+
+		_, err := s.CancelOrder("order-1", actor, &model.OrderCancelRequest{Reason: "Changed my mind"})
+
+		assert.ErrorIs(t, err, ErrOrderNotCancellable)
+	})
+
+	t.Run("Should refuse to cancel an order once it was paid or the shoot started", func(t *testing.T) {
+		t.Parallel()
+
+		for _, status := range []string{model.OrderStatusPaid, model.OrderStatusShooting, model.OrderStatusDelivered, model.OrderStatusCancelled} {
+			repo := &lifecycleOrderRepository{stubOrderRepository: &stubOrderRepository{}, order: &model.Order{ID: "order-1", Status: status}}
+			s := &orderService{orderRepo: repo}
+
+			_, err := s.CancelOrder("order-1", actor, &model.OrderCancelRequest{Reason: "Changed my mind"})
+
+			assert.ErrorIs(t, err, ErrOrderNotCancellable, status)
+		}
+	})
+
+	t.Run("Should report orders that do not exist", func(t *testing.T) {
+		t.Parallel()
+
+		s := &orderService{orderRepo: &lifecycleOrderRepository{stubOrderRepository: &stubOrderRepository{}}}
+
+		_, err := s.CancelOrder("order-1", actor, &model.OrderCancelRequest{Reason: "Changed my mind"})
+
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+}
+
+func TestDeleteOrder(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Should delete orders without an open invoice", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &lifecycleOrderRepository{stubOrderRepository: &stubOrderRepository{}, order: &model.Order{ID: "order-1", Status: model.OrderStatusCancelled}}
+		s := &orderService{orderRepo: repo}
+
+		assert.NoError(t, s.DeleteOrder("order-1"))
+		assert.Equal(t, []string{"order-1"}, repo.deleted)
+	})
+
+	t.Run("Should refuse to delete an order in progress", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &lifecycleOrderRepository{stubOrderRepository: &stubOrderRepository{}, order: &model.Order{ID: "order-1", Status: model.OrderStatusPaid}}
+		s := &orderService{orderRepo: repo}
+
+		assert.ErrorIs(t, s.DeleteOrder("order-1"), ErrOrderInProgress)
+		assert.Empty(t, repo.deleted)
+	})
+}
+
+func TestUpdateOrderStatus(t *testing.T) {
+	t.Parallel()
+
+	admin := &model.Actor{ID: "admin-1", Role: model.RoleAdmin}
+
+	t.Run("Should refuse to mark an order paid", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &lifecycleOrderRepository{stubOrderRepository: &stubOrderRepository{}, order: &model.Order{ID: "order-1", Status: model.OrderStatusAccepted}}
+		s := &orderService{orderRepo: repo}
+
+		_, err := s.UpdateOrderStatus("order-1", admin, &model.OrderStatusChangeRequest{Status: model.OrderStatusPaid})
+
+		assert.ErrorIs(t, err, ErrInvalidStatusTransition)
+		assert.Equal(t, model.OrderStatusAccepted, repo.order.Status)
+	})
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+)
+
+var (
+	ErrInvalidOrderStatus      = errors.New("invalid order status")
+	ErrInvalidStatusTransition = errors.New("invalid order status transition")
+)
+
+// orderStatusTransitions maps every order status to the statuses it may move to next.
+// Terminal statuses have no next status. A rejected quote sends the order back to quote_received.
+// Orders paid outside the payment provider can skip paid and go straight to product_received
+var orderStatusTransitions = map[string][]string{
+	model.OrderStatusQuoteReceived:   {model.OrderStatusQuoted, model.OrderStatusCancelled},
+	model.OrderStatusQuoted:          {model.OrderStatusAccepted, model.OrderStatusQuoteReceived, model.OrderStatusCancelled},
+	model.OrderStatusAccepted:        {model.OrderStatusPaid, model.OrderStatusProductReceived, model.OrderStatusCancelled},
+	model.OrderStatusPaid:            {model.OrderStatusProductReceived, model.OrderStatusCancelled},
+	model.OrderStatusProductReceived: {model.OrderStatusShooting, model.OrderStatusCancelled},
+	model.OrderStatusShooting:        {model.OrderStatusEditing, model.OrderStatusCancelled},
+	model.OrderStatusEditing:         {model.OrderStatusDelivered, model.OrderStatusCancelled},
+	model.OrderStatusDelivered:       {model.OrderStatusMarkCompleted},
+	model.OrderStatusMarkCompleted:   nil,
+	model.OrderStatusCancelled:       nil,
+}
+
+func isValidOrderStatus(status string) bool {
+	_, ok := orderStatusTransitions[status]
+	return ok
+}
+
+// validateStatusTransition checks that an order currently in from is allowed to move to to
+func validateStatusTransition(from, to string) error {
+	if !isValidOrderStatus(to) {
+		return fmt.Errorf("%w: %q", ErrInvalidOrderStatus, to)
+	}
+
+	for _, next := range orderStatusTransitions[from] {
+		if next == to {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: %s -> %s", ErrInvalidStatusTransition, from, to)
+}
@@ -0,0 +1,236 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestValidateStatusTransition(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Should allow moving along the lifecycle", func(t *testing.T) {
+		t.Parallel()
+
+		lifecycle := []string{
+			model.OrderStatusQuoteReceived,
+			model.OrderStatusQuoted,
+			model.OrderStatusAccepted,
+			model.OrderStatusPaid,
+			model.OrderStatusProductReceived,
+			model.OrderStatusShooting,
+			model.OrderStatusEditing,
+			model.OrderStatusDelivered,
+			model.OrderStatusMarkCompleted,
+		}
+
+		for i := 0; i < len(lifecycle)-1; i++ {
+			assert.NoError(t, validateStatusTransition(lifecycle[i], lifecycle[i+1]))
+		}
+	})
+
+	t.Run("Should allow receiving products for an order paid offline", func(t *testing.T) {
+		t.Parallel()
+
+		assert.NoError(t, validateStatusTransition(model.OrderStatusAccepted, model.OrderStatusProductReceived))
+	})
+
+	t.Run("Should allow cancelling an order in progress", func(t *testing.T) {
+		t.Parallel()
+
+		assert.NoError(t, validateStatusTransition(model.OrderStatusQuoteReceived, model.OrderStatusCancelled))
+		assert.NoError(t, validateStatusTransition(model.OrderStatusEditing, model.OrderStatusCancelled))
+	})
+
+	t.Run("Should reject moving backwards or skipping steps", func(t *testing.T) {
+		t.Parallel()
+
+		assert.ErrorIs(t, validateStatusTransition(model.OrderStatusMarkCompleted, model.OrderStatusQuoteReceived), ErrInvalidStatusTransition)
+		assert.ErrorIs(t, validateStatusTransition(model.OrderStatusQuoteReceived, model.OrderStatusShooting), ErrInvalidStatusTransition)
+		assert.ErrorIs(t, validateStatusTransition(model.OrderStatusCancelled, model.OrderStatusQuoted), ErrInvalidStatusTransition)
+	})
+
+	t.Run("Should reject unknown statuses", func(t *testing.T) {
+		t.Parallel()
+
+		assert.ErrorIs(t, validateStatusTransition(model.OrderStatusQuoteReceived, "qouted"), ErrInvalidOrderStatus)
+	})
+}
+
+// statusEmailsOrderRepository has status emails switched on for every status
+type statusEmailsOrderRepository struct {
+	*stubOrderRepository
+}
+
+func (*statusEmailsOrderRepository) GetStatusEmailSetting(status string) (*model.OrderStatusEmailSetting, error) {
+	return &model.OrderStatusEmailSetting{Status: status, EmailEnabled: true}, nil
+}
+
+func TestOrderStatusNotifications(t *testing.T) {
+	t.Parallel()
+
+	orderRepo := &statusEmailsOrderRepository{&stubOrderRepository{}}
+
+	newOrder := func(phone string) *model.Order {
+		user := newTestUser()
+		user.PhoneNumber = phone
+
+		return &model.Order{ID: "order-1", OrderName: "BELVA-0001", UserID: user.ID, User: *user}
+	}
+
+	t.Run("Should skip emails the customer turned off", func(t *testing.T) {
+		t.Parallel()
+
+		preferences := &stubPreferenceRepository{preferences: map[string]*model.NotificationPreference{
+			"user-1": {UserID: "user-1", Email: true, OrderUpdates: false, Marketing: true},
+		}}
+
+		messages, err := newOrderStatusNotifications(orderRepo, preferences, newOrder("+2348012345678"), model.OrderStatusShooting, "")
+
+		assert.NoError(t, err)
+		assert.Empty(t, messages)
+	})
+
+	t.Run("Should send delivery updates on the text channels the customer turned on", func(t *testing.T) {
+		t.Parallel()
+
+		preferences := &stubPreferenceRepository{preferences: map[string]*model.NotificationPreference{
+			"user-1": {UserID: "user-1", WhatsApp: true, OrderUpdates: true},
+		}}
+
+		messages, err := newOrderStatusNotifications(orderRepo, preferences, newOrder("+2348012345678"), model.OrderStatusDelivered, "")
+
+		assert.NoError(t, err)
+		assert.Len(t, messages, 1)
+		assert.Equal(t, model.NotificationChannelWhatsApp, messages[0].Channel)
+		assert.Equal(t, "+2348012345678", messages[0].Recipient)
+		assert.Contains(t, messages[0].Body, "BELVA-0001")
+		assert.Equal(t, "user-1", *messages[0].UserID)
+	})
+
+	t.Run("Should not text customers without a phone number or for other statuses", func(t *testing.T) {
+		t.Parallel()
+
+		preferences := &stubPreferenceRepository{preferences: map[string]*model.NotificationPreference{
+			"user-1": {UserID: "user-1", SMS: true, OrderUpdates: true},
+		}}
+
+		messages, err := newOrderStatusNotifications(orderRepo, preferences, newOrder(""), model.OrderStatusProductReceived, "")
+		assert.NoError(t, err)
+		assert.Empty(t, messages)
+
+		messages, err = newOrderStatusNotifications(orderRepo, preferences, newOrder("+2348012345678"), model.OrderStatusEditing, "")
+		assert.NoError(t, err)
+		assert.Empty(t, messages)
+	})
+}
+
+// statusEmailSettingsOrderRepository holds the status email toggles saved by an admin. Statuses
+// without a saved toggle have no row, as before the settings table was seeded
+type statusEmailSettingsOrderRepository struct {
+	*stubOrderRepository
+	enabled map[string]bool
+	err     error
+}
+
+func (r *statusEmailSettingsOrderRepository) GetStatusEmailSetting(status string) (*model.OrderStatusEmailSetting, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	enabled, ok := r.enabled[status]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return &model.OrderStatusEmailSetting{Status: status, EmailEnabled: enabled}, nil
+}
+
+func TestOrderStatusEmails(t *testing.T) {
+	t.Parallel()
+
+	preference := &model.NotificationPreference{UserID: "user-1", Email: true, OrderUpdates: true}
+
+	newOrder := func() *model.Order {
+		user := newTestUser()
+
+		return &model.Order{ID: "order-1", OrderName: "BELVA-0001", ProductName: "Sneakers", UserID: user.ID, User: *user}
+	}
+
+	for _, tc := range []struct {
+		status  string
+		subject string
+		title   string
+	}{
+		{status: model.OrderStatusQuoted, subject: "Your Photography Quote Is Ready - BelvaPhilips Imagery", title: "Your Photography Quote Is Ready"},
+		{status: model.OrderStatusAccepted, subject: "Your Order Is Confirmed - BelvaPhilips Imagery", title: "Quote Accepted"},
+		{status: model.OrderStatusPaid, subject: "We Have Received Your Payment - BelvaPhilips Imagery", title: "Payment Received"},
+		{status: model.OrderStatusProductReceived, subject: "We Have Received Your Products - BelvaPhilips Imagery", title: "We Have Received Your Products"},
+		{status: model.OrderStatusShooting, subject: "Your Shoot Has Started - BelvaPhilips Imagery", title: "Your Shoot Has Started"},
+		{status: model.OrderStatusEditing, subject: "Your Photos Are Being Edited - BelvaPhilips Imagery", title: "Your Photos Are Being Edited"},
+		{status: model.OrderStatusDelivered, subject: "Your Photos Have Been Delivered - BelvaPhilips Imagery", title: "Your Photos Have Been Delivered"},
+		{status: model.OrderStatusMarkCompleted, subject: "Your Order Is Complete - BelvaPhilips Imagery", title: "Order Completed"},
+		{status: model.OrderStatusCancelled, subject: "Your Order Has Been Cancelled - BelvaPhilips Imagery", title: "Order Cancelled"},
+	} {
+		t.Run("Should send the "+tc.status+" email with its own template and subject", func(t *testing.T) {
+			t.Parallel()
+
+			orderRepo := &statusEmailSettingsOrderRepository{stubOrderRepository: &stubOrderRepository{}, enabled: map[string]bool{tc.status: true}}
+
+			emails, err := newOrderStatusEmails(orderRepo, preference, newOrder(), tc.status, "")
+
+			assert.NoError(t, err)
+			assert.Len(t, emails, 1)
+			assert.Equal(t, "ada@example.com", emails[0].Recipient)
+			assert.Equal(t, tc.subject, emails[0].Subject)
+			assert.Contains(t, emails[0].Body, "<title>"+tc.title)
+			assert.Contains(t, emails[0].Body, "BELVA-0001")
+		})
+	}
+
+	t.Run("Should not email statuses without a template", func(t *testing.T) {
+		t.Parallel()
+
+		orderRepo := &statusEmailSettingsOrderRepository{stubOrderRepository: &stubOrderRepository{}}
+
+		emails, err := newOrderStatusEmails(orderRepo, preference, newOrder(), model.OrderStatusQuoteReceived, "")
+
+		assert.NoError(t, err)
+		assert.Empty(t, emails)
+	})
+
+	t.Run("Should skip statuses an admin turned off", func(t *testing.T) {
+		t.Parallel()
+
+		orderRepo := &statusEmailSettingsOrderRepository{stubOrderRepository: &stubOrderRepository{}, enabled: map[string]bool{model.OrderStatusShooting: false}}
+
+		emails, err := newOrderStatusEmails(orderRepo, preference, newOrder(), model.OrderStatusShooting, "")
+
+		assert.NoError(t, err)
+		assert.Empty(t, emails)
+	})
+
+	t.Run("Should send emails for statuses without a saved setting", func(t *testing.T) {
+		t.Parallel()
+
+		orderRepo := &statusEmailSettingsOrderRepository{stubOrderRepository: &stubOrderRepository{}, enabled: map[string]bool{}}
+
+		emails, err := newOrderStatusEmails(orderRepo, preference, newOrder(), model.OrderStatusShooting, "")
+
+		assert.NoError(t, err)
+		assert.Len(t, emails, 1)
+	})
+
+	t.Run("Should pass on database errors", func(t *testing.T) {
+		t.Parallel()
+
+		orderRepo := &statusEmailSettingsOrderRepository{stubOrderRepository: &stubOrderRepository{}, err: errors.New("database unavailable")}
+
+		_, err := newOrderStatusEmails(orderRepo, preference, newOrder(), model.OrderStatusShooting, "")
+
+		assert.ErrorContains(t, err, "database unavailable")
+	})
+}
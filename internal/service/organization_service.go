@@ -0,0 +1,335 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MogboPython/belvaphilips_backend/internal/repository"
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/MogboPython/belvaphilips_backend/pkg/utils"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrNotOrganizationMember     = errors.New("you are not a member of this organization")
+	ErrNotOrganizationOwner      = errors.New("only organization owners can do this")
+	ErrAlreadyOrganizationMember = errors.New("you are already a member of this organization")
+	ErrInvalidInvitation         = errors.New("invitation is invalid or has expired")
+	ErrInvitationEmailMismatch   = errors.New("this invitation was sent to a different email address")
+)
+
+const invitationTTL = 7 * 24 * time.Hour
+
+type OrganizationService interface {
+	IsMember(organizationID, userID string) (bool, error)
+	CreateOrganization(actor *model.Actor, req *model.OrganizationRequest) (*model.OrganizationResponse, error)
+	GetOrganizations(actor *model.Actor) ([]*model.OrganizationResponse, error)
+	GetOrganization(id string, actor *model.Actor) (*model.OrganizationResponse, error)
+	UpdateOrganization(id string, actor *model.Actor, req *model.OrganizationRequest) (*model.OrganizationResponse, error)
+	GetMembers(id string) ([]*model.OrganizationMemberResponse, error)
+	UpdateMemberRole(id, userID string, actor *model.Actor, req *model.OrganizationMemberRoleRequest) error
+	RemoveMember(id, userID string, actor *model.Actor) error
+	InviteMember(id string, actor *model.Actor, req *model.OrganizationInviteRequest) (*model.OrganizationInvitationResponse, error)
+	GetInvitations(id string, actor *model.Actor) ([]*model.OrganizationInvitationResponse, error)
+	RevokeInvitation(id, invitationID string, actor *model.Actor) error
+	AcceptInvitation(actor *model.Actor, req *model.AcceptInvitationRequest) (*model.OrganizationResponse, error)
+}
+
+type organizationService struct {
+	organizationRepo repository.OrganizationRepository
+	userRepo         repository.UserRepository
+}
+
+func NewOrganizationService(organizationRepo repository.OrganizationRepository, userRepo repository.UserRepository) OrganizationService {
+	return &organizationService{
+		organizationRepo: organizationRepo,
+		userRepo:         userRepo,
+	}
+}
+
+// IsMember reports whether a user belongs to an organization, for middleware.OwnerMemberOrAdmin
+func (s *organizationService) IsMember(organizationID, userID string) (bool, error) {
+	return s.organizationRepo.IsMember(organizationID, userID)
+}
+
+// CreateOrganization creates an organization owned by the user who creates it
+func (s *organizationService) CreateOrganization(actor *model.Actor, req *model.OrganizationRequest) (*model.OrganizationResponse, error) {
+	if _, err := s.userRepo.GetByID(actor.ID); err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	organization := &model.Organization{
+		Name:      strings.TrimSpace(req.Name),
+		CreatedBy: actor.ID,
+	}
+
+	if err := s.organizationRepo.Create(organization); err != nil {
+		return nil, fmt.Errorf("failed to create organization: %w", err)
+	}
+
+	return mapOrganizationToResponse(organization, model.OrganizationRoleOwner), nil
+}
+
+// GetOrganizations returns the organizations the caller is a member of
+func (s *organizationService) GetOrganizations(actor *model.Actor) ([]*model.OrganizationResponse, error) {
+	memberships, err := s.organizationRepo.GetMemberships(actor.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find organizations: %w", err)
+	}
+
+	responses := make([]*model.OrganizationResponse, len(memberships))
+	for i, membership := range memberships {
+		responses[i] = mapOrganizationToResponse(&membership.Organization, membership.Role)
+	}
+
+	return responses, nil
+}
+
+func (s *organizationService) GetOrganization(id string, actor *model.Actor) (*model.OrganizationResponse, error) {
+	organization, err := s.organizationRepo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find organization: %w", err)
+	}
+
+	// Staff can look at any organization without being a member
+	var role string
+	if member, err := s.organizationRepo.GetMember(id, actor.ID); err == nil {
+		role = member.Role
+	}
+
+	return mapOrganizationToResponse(organization, role), nil
+}
+
+func (s *organizationService) UpdateOrganization(id string, actor *model.Actor, req *model.OrganizationRequest) (*model.OrganizationResponse, error) {
+	if err := s.requireOwner(id, actor); err != nil {
+		return nil, err
+	}
+
+	organization, err := s.organizationRepo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find organization: %w", err)
+	}
+
+	organization.Name = strings.TrimSpace(req.Name)
+
+	if err := s.organizationRepo.Update(organization); err != nil {
+		return nil, fmt.Errorf("failed to update organization: %w", err)
+	}
+
+	return mapOrganizationToResponse(organization, model.OrganizationRoleOwner), nil
+}
+
+func (s *organizationService) GetMembers(id string) ([]*model.OrganizationMemberResponse, error) {
+	members, err := s.organizationRepo.GetMembers(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find members: %w", err)
+	}
+
+	responses := make([]*model.OrganizationMemberResponse, len(members))
+	for i, member := range members {
+		responses[i] = &model.OrganizationMemberResponse{
+			UserID:   member.UserID,
+			Name:     member.User.Name,
+			Email:    member.User.Email,
+			Role:     member.Role,
+			JoinedAt: member.CreatedAt,
+		}
+	}
+
+	return responses, nil
+}
+
+func (s *organizationService) UpdateMemberRole(id, userID string, actor *model.Actor, req *model.OrganizationMemberRoleRequest) error {
+	if err := s.requireOwner(id, actor); err != nil {
+		return err
+	}
+
+	if err := s.organizationRepo.UpdateMemberRole(id, userID, req.Role); err != nil {
+		return fmt.Errorf("failed to update member: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveMember takes a member out of an organization. Owners can remove anyone and members can
+// remove themselves
+func (s *organizationService) RemoveMember(id, userID string, actor *model.Actor) error {
+	if userID != actor.ID {
+		if err := s.requireOwner(id, actor); err != nil {
+			return err
+		}
+	}
+
+	if err := s.organizationRepo.RemoveMember(id, userID); err != nil {
+		return fmt.Errorf("failed to remove member: %w", err)
+	}
+
+	return nil
+}
+
+// InviteMember emails a single-use invitation code to join an organization
+func (s *organizationService) InviteMember(id string, actor *model.Actor, req *model.OrganizationInviteRequest) (*model.OrganizationInvitationResponse, error) {
+	if err := s.requireOwner(id, actor); err != nil {
+		return nil, err
+	}
+
+	organization, err := s.organizationRepo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find organization: %w", err)
+	}
+
+	inviter, err := s.userRepo.GetByID(actor.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	token, err := utils.GenerateInvitationToken()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate invitation: %w", err)
+	}
+
+	role := req.Role
+	if role == "" {
+		role = model.OrganizationRoleMember
+	}
+
+	invitation := &model.OrganizationInvitation{
+		OrganizationID: organization.ID,
+		Email:          strings.ToLower(strings.TrimSpace(req.Email)),
+		Role:           role,
+		TokenHash:      utils.HashToken(token),
+		InvitedBy:      actor.ID,
+		ExpiresAt:      time.Now().Add(invitationTTL),
+	}
+
+	body, err := utils.ParseTemplate("organization_invite.html", map[string]string{
+		"InviterName":      inviter.Name,
+		"OrganizationName": organization.Name,
+		"Role":             role,
+		"Token":            token,
+		"ExpiresAt":        invitation.ExpiresAt.Format(time.UnixDate),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse organization invite email template: %w", err)
+	}
+
+	// The token is as good as the invitation itself, so it is not kept once the email left the outbox
+	emails := []*model.EmailOutbox{
+		sensitive(newOutboxEmail(invitation.Email, "You have been invited to "+organization.Name+" - BelvaPhilips Imagery", body)),
+	}
+
+	if err := s.organizationRepo.CreateInvitation(invitation, emails); err != nil {
+		return nil, fmt.Errorf("failed to create invitation: %w", err)
+	}
+
+	return mapInvitationToResponse(invitation), nil
+}
+
+func (s *organizationService) GetInvitations(id string, actor *model.Actor) ([]*model.OrganizationInvitationResponse, error) {
+	if err := s.requireOwner(id, actor); err != nil {
+		return nil, err
+	}
+
+	invitations, err := s.organizationRepo.GetPendingInvitations(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find invitations: %w", err)
+	}
+
+	responses := make([]*model.OrganizationInvitationResponse, len(invitations))
+	for i, invitation := range invitations {
+		responses[i] = mapInvitationToResponse(invitation)
+	}
+
+	return responses, nil
+}
+
+func (s *organizationService) RevokeInvitation(id, invitationID string, actor *model.Actor) error {
+	if err := s.requireOwner(id, actor); err != nil {
+		return err
+	}
+
+	if err := s.organizationRepo.DeleteInvitation(id, invitationID); err != nil {
+		return fmt.Errorf("failed to revoke invitation: %w", err)
+	}
+
+	return nil
+}
+
+// AcceptInvitation adds the caller to the organization of an invitation that was sent to their
+// email address
+func (s *organizationService) AcceptInvitation(actor *model.Actor, req *model.AcceptInvitationRequest) (*model.OrganizationResponse, error) {
+	invitation, err := s.organizationRepo.GetInvitationByHash(utils.HashToken(strings.TrimSpace(req.Token)))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidInvitation
+		}
+
+		return nil, fmt.Errorf("failed to find invitation: %w", err)
+	}
+
+	if invitation.AcceptedAt != nil || time.Now().After(invitation.ExpiresAt) {
+		return nil, ErrInvalidInvitation
+	}
+
+	user, err := s.userRepo.GetByID(actor.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	if !strings.EqualFold(user.Email, invitation.Email) {
+		return nil, ErrInvitationEmailMismatch
+	}
+
+	if err := s.organizationRepo.AcceptInvitation(invitation, user.ID); err != nil {
+		switch {
+		case strings.Contains(err.Error(), "invitation was already used"):
+			return nil, ErrInvalidInvitation
+		case strings.Contains(err.Error(), "already a member"):
+			return nil, ErrAlreadyOrganizationMember
+		default:
+			return nil, fmt.Errorf("failed to accept invitation: %w", err)
+		}
+	}
+
+	return mapOrganizationToResponse(&invitation.Organization, invitation.Role), nil
+}
+
+// requireOwner fails unless the caller is an owner of the organization
+func (s *organizationService) requireOwner(id string, actor *model.Actor) error {
+	member, err := s.organizationRepo.GetMember(id, actor.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotOrganizationMember
+		}
+
+		return fmt.Errorf("failed to find member: %w", err)
+	}
+
+	if member.Role != model.OrganizationRoleOwner {
+		return ErrNotOrganizationOwner
+	}
+
+	return nil
+}
+
+func mapOrganizationToResponse(organization *model.Organization, role string) *model.OrganizationResponse {
+	return &model.OrganizationResponse{
+		ID:        organization.ID,
+		Name:      organization.Name,
+		Role:      role,
+		CreatedAt: organization.CreatedAt,
+	}
+}
+
+func mapInvitationToResponse(invitation *model.OrganizationInvitation) *model.OrganizationInvitationResponse {
+	return &model.OrganizationInvitationResponse{
+		ID:             invitation.ID,
+		OrganizationID: invitation.OrganizationID,
+		Email:          invitation.Email,
+		Role:           invitation.Role,
+		ExpiresAt:      invitation.ExpiresAt,
+		CreatedAt:      invitation.CreatedAt,
+	}
+}
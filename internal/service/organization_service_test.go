@@ -0,0 +1,162 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MogboPython/belvaphilips_backend/internal/repository"
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/MogboPython/belvaphilips_backend/pkg/utils"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type stubOrganizationRepository struct {
+	repository.OrganizationRepository
+	members     map[string]string
+	invitations []*model.OrganizationInvitation
+	removed     []string
+}
+
+func (r *stubOrganizationRepository) GetMember(organizationID, userID string) (*model.OrganizationMember, error) {
+	if role, ok := r.members[userID]; ok {
+		return &model.OrganizationMember{OrganizationID: organizationID, UserID: userID, Role: role}, nil
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *stubOrganizationRepository) RemoveMember(_, userID string) error {
+	r.removed = append(r.removed, userID)
+
+	return nil
+}
+
+func (r *stubOrganizationRepository) GetInvitationByHash(tokenHash string) (*model.OrganizationInvitation, error) {
+	for _, invitation := range r.invitations {
+		if invitation.TokenHash == tokenHash {
+			return invitation, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *stubOrganizationRepository) AcceptInvitation(invitation *model.OrganizationInvitation, userID string) error {
+	now := time.Now()
+	invitation.AcceptedAt = &now
+	r.members[userID] = invitation.Role
+
+	return nil
+}
+
+func newTestInvitation(token, email string, expiresAt time.Time) *model.OrganizationInvitation {
+	return &model.OrganizationInvitation{
+		ID:             "invitation-1",
+		OrganizationID: "org-1",
+		Organization:   model.Organization{ID: "org-1", Name: "Ada Studio"},
+		Email:          email,
+		Role:           model.OrganizationRoleMember,
+		TokenHash:      utils.HashToken(token),
+		ExpiresAt:      expiresAt,
+	}
+}
+
+func TestAcceptInvitation(t *testing.T) {
+	t.Parallel()
+
+	actor := &model.Actor{ID: "user-1", Role: model.RoleAuthenticated}
+
+	t.Run("Should add the invited user to the organization", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &stubOrganizationRepository{
+			members:     map[string]string{},
+			invitations: []*model.OrganizationInvitation{newTestInvitation("code", "ADA@example.com", time.Now().Add(time.Hour))},
+		}
+		s := &organizationService{organizationRepo: repo, userRepo: newStubUserRepository(t, newTestUser())}
+
+		organization, err := s.AcceptInvitation(actor, &model.AcceptInvitationRequest{Token: " code "})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "org-1", organization.ID)
+		assert.Equal(t, model.OrganizationRoleMember, organization.Role)
+		assert.Equal(t, model.OrganizationRoleMember, repo.members["user-1"])
+
+		_, err = s.AcceptInvitation(actor, &model.AcceptInvitationRequest{Token: "code"})
+		assert.ErrorIs(t, err, ErrInvalidInvitation)
+	})
+
+	t.Run("Should refuse invitations sent to someone else", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &stubOrganizationRepository{
+			members:     map[string]string{},
+			invitations: []*model.OrganizationInvitation{newTestInvitation("code", "grace@example.com", time.Now().Add(time.Hour))},
+		}
+		s := &organizationService{organizationRepo: repo, userRepo: newStubUserRepository(t, newTestUser())}
+
+		_, err := s.AcceptInvitation(actor, &model.AcceptInvitationRequest{Token: "code"})
+
+		assert.ErrorIs(t, err, ErrInvitationEmailMismatch)
+		assert.Empty(t, repo.members)
+	})
+
+	t.Run("Should refuse expired and unknown invitations", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &stubOrganizationRepository{
+			members:     map[string]string{},
+			invitations: []*model.OrganizationInvitation{newTestInvitation("code", "ada@example.com", time.Now().Add(-time.Minute))},
+		}
+		s := &organizationService{organizationRepo: repo, userRepo: newStubUserRepository(t, newTestUser())}
+
+		_, err := s.AcceptInvitation(actor, &model.AcceptInvitationRequest{Token: "code"})
+		assert.ErrorIs(t, err, ErrInvalidInvitation)
+
+		_, err = s.AcceptInvitation(actor, &model.AcceptInvitationRequest{Token: "other"})
+		assert.ErrorIs(t, err, ErrInvalidInvitation)
+	})
+}
+
+func TestRemoveMember(t *testing.T) {
+	t.Parallel()
+
+	newService := func() (*organizationService, *stubOrganizationRepository) {
+		repo := &stubOrganizationRepository{members: map[string]string{
+			"owner-1":  model.OrganizationRoleOwner,
+			"member-1": model.OrganizationRoleMember,
+			"member-2": model.OrganizationRoleMember,
+		}}
+
+		return &organizationService{organizationRepo: repo}, repo
+	}
+
+	t.Run("Should let owners remove members", func(t *testing.T) {
+		t.Parallel()
+
+		s, repo := newService()
+
+		assert.NoError(t, s.RemoveMember("org-1", "member-1", &model.Actor{ID: "owner-1"}))
+		assert.Equal(t, []string{"member-1"}, repo.removed)
+	})
+
+	t.Run("Should let members leave", func(t *testing.T) {
+		t.Parallel()
+
+		s, repo := newService()
+
+		assert.NoError(t, s.RemoveMember("org-1", "member-1", &model.Actor{ID: "member-1"}))
+		assert.Equal(t, []string{"member-1"}, repo.removed)
+	})
+
+	t.Run("Should stop members removing each other", func(t *testing.T) {
+		t.Parallel()
+
+		s, repo := newService()
+
+		assert.ErrorIs(t, s.RemoveMember("org-1", "member-2", &model.Actor{ID: "member-1"}), ErrNotOrganizationOwner)
+		assert.ErrorIs(t, s.RemoveMember("org-1", "member-2", &model.Actor{ID: "outsider"}), ErrNotOrganizationMember)
+		assert.Empty(t, repo.removed)
+	})
+}
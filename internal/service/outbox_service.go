@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/MogboPython/belvaphilips_backend/internal/config"
+	"github.com/MogboPython/belvaphilips_backend/internal/mailer"
+	"github.com/MogboPython/belvaphilips_backend/internal/notifier"
+	"github.com/MogboPython/belvaphilips_backend/internal/repository"
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/MogboPython/belvaphilips_backend/pkg/utils"
+	"github.com/gofiber/fiber/v2/log"
+)
+
+const (
+	defaultOutboxMaxAttempts = 8
+	outboxBatchSize          = 20
+	outboxPollInterval       = 10 * time.Second
+	outboxSendLease          = 2 * time.Minute
+	outboxBaseBackoff        = 30 * time.Second
+	outboxMaxBackoff         = 6 * time.Hour
+)
+
+type OutboxService interface {
+	Start(ctx context.Context)
+	GetEmails(pageStr, limitStr, status string) (model.TotalEmailOutboxResponse, error)
+	ResendEmail(id string) (*model.EmailOutboxResponse, error)
+}
+
+type outboxService struct {
+	outboxRepo repository.OutboxRepository
+	mail       mailer.Mailer
+	notify     notifier.Notifier
+}
+
+func NewOutboxService(outboxRepo repository.OutboxRepository, mail mailer.Mailer, notify notifier.Notifier) OutboxService {
+	return &outboxService{
+		outboxRepo: outboxRepo,
+		mail:       mail,
+		notify:     notify,
+	}
+}
+
+// newOutboxEmail builds an email ready to be queued in the outbox
+func newOutboxEmail(to, subject, body string) *model.EmailOutbox {
+	return newOutboxMessage(model.NotificationChannelEmail, to, subject, body)
+}
+
+// newOutboxTextMessage builds an SMS or WhatsApp message ready to be queued in the outbox
+func newOutboxTextMessage(channel, to, body string) *model.EmailOutbox {
+	return newOutboxMessage(channel, to, "", body)
+}
+
+// redactedOutboxBody replaces the body of a sensitive message once it left the outbox
+const redactedOutboxBody = "[removed after delivery]"
+
+// sensitive marks message as carrying a secret that must not be kept after delivery
+func sensitive(message *model.EmailOutbox) *model.EmailOutbox {
+	message.Sensitive = true
+
+	return message
+}
+
+// forUser marks message as addressed to the customer with userID
+func forUser(userID string, message *model.EmailOutbox) *model.EmailOutbox {
+	message.UserID = &userID
+
+	return message
+}
+
+func newOutboxMessage(channel, to, subject, body string) *model.EmailOutbox {
+	maxAttempts, err := strconv.Atoi(config.Config("OUTBOX_MAX_ATTEMPTS"))
+	if err != nil || maxAttempts < 1 {
+		maxAttempts = defaultOutboxMaxAttempts
+	}
+
+	return &model.EmailOutbox{
+		Channel:       channel,
+		Recipient:     to,
+		Subject:       subject,
+		Body:          body,
+		Status:        model.OutboxStatusPending,
+		MaxAttempts:   maxAttempts,
+		NextAttemptAt: time.Now(),
+	}
+}
+
+// Start drains the outbox until ctx is cancelled
+func (s *outboxService) Start(ctx context.Context) {
+	ticker := time.NewTicker(outboxPollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.processDue()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *outboxService) processDue() {
+	emails, err := s.outboxRepo.ClaimDue(outboxBatchSize, outboxSendLease)
+	if err != nil {
+		log.Errorf("Failed to claim outbox emails: %v", err)
+		return
+	}
+
+	for _, email := range emails {
+		s.deliver(email)
+	}
+}
+
+func (s *outboxService) deliver(email *model.EmailOutbox) {
+	email.Attempts++
+
+	err := s.send(email)
+
+	now := time.Now()
+
+	switch {
+	case err == nil:
+		email.Status = model.OutboxStatusSent
+		email.SentAt = &now
+		email.LastError = ""
+	case email.Attempts >= email.MaxAttempts:
+		email.Status = model.OutboxStatusDead
+		email.LastError = err.Error()
+
+		log.Errorf("Giving up on %s %v to %s after %d attempts: %v", outboxChannel(email), email.ID, email.Recipient, email.Attempts, err)
+	default:
+		email.NextAttemptAt = now.Add(outboxBackoff(email.Attempts))
+		email.LastError = err.Error()
+
+		log.Warnf("Failed to send %s %v to %s (attempt %d), retrying at %v: %v", outboxChannel(email), email.ID, email.Recipient, email.Attempts, email.NextAttemptAt, err)
+	}
+
+	if email.Sensitive && email.Status != model.OutboxStatusPending {
+		email.Body = redactedOutboxBody
+	}
+
+	if err := s.outboxRepo.Save(email); err != nil {
+		log.Errorf("Failed to update outbox email %v: %v", email.ID, err)
+	}
+}
+
+// send hands a message to the mailer or, for SMS and WhatsApp, to the notifier
+func (s *outboxService) send(email *model.EmailOutbox) error {
+	if channel := outboxChannel(email); channel != model.NotificationChannelEmail {
+		return s.notify.Send(&notifier.Message{
+			Channel: channel,
+			To:      email.Recipient,
+			Body:    email.Body,
+		})
+	}
+
+	return s.mail.Send(&mailer.Message{
+		To:      email.Recipient,
+		Subject: email.Subject,
+		Body:    email.Body,
+	})
+}
+
+// outboxChannel returns the channel of a message. Messages queued before text messages were
+// supported are emails
+func outboxChannel(email *model.EmailOutbox) string {
+	if email.Channel == "" {
+		return model.NotificationChannelEmail
+	}
+
+	return email.Channel
+}
+
+// outboxBackoff returns how long to wait before the next attempt after the given number of failed attempts
+func outboxBackoff(attempts int) time.Duration {
+	backoff := outboxBaseBackoff
+
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= outboxMaxBackoff {
+			return outboxMaxBackoff
+		}
+	}
+
+	return backoff
+}
+
+func (s *outboxService) GetEmails(pageStr, limitStr, status string) (model.TotalEmailOutboxResponse, error) {
+	var totalEmailResponse model.TotalEmailOutboxResponse
+
+	offset, limit := utils.GetPageAndLimitInt(pageStr, limitStr)
+
+	emails, count, err := s.outboxRepo.GetAll(offset, limit, status)
+	if err != nil {
+		return totalEmailResponse, fmt.Errorf("failed to get emails: %w", err)
+	}
+
+	emailResponses := make([]*model.EmailOutboxResponse, len(emails))
+	for i, email := range emails {
+		emailResponses[i] = mapEmailOutboxToResponse(email)
+	}
+
+	totalEmailResponse.Emails = emailResponses
+	totalEmailResponse.Total = count
+
+	return totalEmailResponse, nil
+}
+
+func (s *outboxService) ResendEmail(id string) (*model.EmailOutboxResponse, error) {
+	email, err := s.outboxRepo.Requeue(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resend email: %w", err)
+	}
+
+	return mapEmailOutboxToResponse(email), nil
+}
+
+func mapEmailOutboxToResponse(email *model.EmailOutbox) *model.EmailOutboxResponse {
+	return &model.EmailOutboxResponse{
+		ID:            email.ID,
+		Channel:       outboxChannel(email),
+		Recipient:     email.Recipient,
+		Subject:       email.Subject,
+		Status:        email.Status,
+		LastError:     email.LastError,
+		Attempts:      email.Attempts,
+		MaxAttempts:   email.MaxAttempts,
+		NextAttemptAt: email.NextAttemptAt,
+		SentAt:        email.SentAt,
+		CreatedAt:     email.CreatedAt,
+		UpdatedAt:     email.UpdatedAt,
+	}
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MogboPython/belvaphilips_backend/internal/mailer"
+	"github.com/MogboPython/belvaphilips_backend/internal/notifier"
+	"github.com/MogboPython/belvaphilips_backend/internal/repository"
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutboxBackoff(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Should double the wait after every failed attempt", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, outboxBaseBackoff, outboxBackoff(1))
+		assert.Equal(t, 2*outboxBaseBackoff, outboxBackoff(2))
+		assert.Equal(t, 8*outboxBaseBackoff, outboxBackoff(4))
+	})
+
+	t.Run("Should never wait longer than the maximum backoff", func(t *testing.T) {
+		t.Parallel()
+
+		assert.Equal(t, outboxMaxBackoff, outboxBackoff(50))
+		assert.LessOrEqual(t, outboxBackoff(10), 6*time.Hour)
+	})
+}
+
+type stubOutboxRepository struct {
+	repository.OutboxRepository
+	saved []*model.EmailOutbox
+}
+
+func (r *stubOutboxRepository) Save(email *model.EmailOutbox) error {
+	r.saved = append(r.saved, email)
+	return nil
+}
+
+func TestOutboxDeliver(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Should mark the email sent once the mailer accepts it", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &stubOutboxRepository{}
+		mail := mailer.NewMemoryMailer()
+		s := &outboxService{outboxRepo: repo, mail: mail}
+
+		s.deliver(&model.EmailOutbox{Recipient: "client@example.com", Subject: "Hello", MaxAttempts: 3})
+
+		assert.Len(t, mail.Messages(), 1)
+		assert.Equal(t, model.OutboxStatusSent, repo.saved[0].Status)
+		assert.NotNil(t, repo.saved[0].SentAt)
+	})
+
+	t.Run("Should schedule a retry when sending fails", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &stubOutboxRepository{}
+		mail := mailer.NewMemoryMailer()
+		mail.FailWith(errors.New("smtp down"))
+		s := &outboxService{outboxRepo: repo, mail: mail}
+
+		s.deliver(&model.EmailOutbox{Recipient: "client@example.com", MaxAttempts: 3, Status: model.OutboxStatusPending})
+
+		assert.Equal(t, model.OutboxStatusPending, repo.saved[0].Status)
+		assert.Equal(t, 1, repo.saved[0].Attempts)
+		assert.Equal(t, "smtp down", repo.saved[0].LastError)
+		assert.True(t, repo.saved[0].NextAttemptAt.After(time.Now()))
+	})
+
+	t.Run("Should dead-letter the email after the last attempt", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &stubOutboxRepository{}
+		mail := mailer.NewMemoryMailer()
+		mail.FailWith(errors.New("smtp down"))
+		s := &outboxService{outboxRepo: repo, mail: mail}
+
+		s.deliver(&model.EmailOutbox{Recipient: "client@example.com", Attempts: 2, MaxAttempts: 3})
+
+		assert.Equal(t, model.OutboxStatusDead, repo.saved[0].Status)
+	})
+
+	t.Run("Should remove the body of sensitive messages once they left the outbox", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &stubOutboxRepository{}
+		mail := mailer.NewMemoryMailer()
+		s := &outboxService{outboxRepo: repo, mail: mail}
+
+		s.deliver(sensitive(newOutboxEmail("staff@example.com", "Your Staff Account", "Password: hunter2")))
+
+		assert.Len(t, mail.Messages(), 1)
+		assert.Equal(t, redactedOutboxBody, repo.saved[0].Body)
+
+		mail.FailWith(errors.New("smtp down"))
+
+		s.deliver(sensitive(newOutboxEmail("staff@example.com", "Your Staff Account", "Password: hunter2")))
+		assert.Equal(t, "Password: hunter2", repo.saved[1].Body)
+
+		s.deliver(sensitive(&model.EmailOutbox{Recipient: "staff@example.com", Body: "Password: hunter2", Attempts: 2, MaxAttempts: 3}))
+		assert.Equal(t, model.OutboxStatusDead, repo.saved[2].Status)
+		assert.Equal(t, redactedOutboxBody, repo.saved[2].Body)
+	})
+
+	t.Run("Should send text messages through the notifier", func(t *testing.T) {
+		t.Parallel()
+
+		repo := &stubOutboxRepository{}
+		mail := mailer.NewMemoryMailer()
+		notify := notifier.NewFakeNotifier()
+		s := &outboxService{outboxRepo: repo, mail: mail, notify: notify}
+
+		s.deliver(newOutboxTextMessage(model.NotificationChannelSMS, "+2348012345678", "Your photos are ready"))
+
+		assert.Empty(t, mail.Messages())
+		assert.Equal(t, []notifier.Message{{Channel: model.NotificationChannelSMS, To: "+2348012345678", Body: "Your photos are ready"}}, notify.Messages())
+		assert.Equal(t, model.OutboxStatusSent, repo.saved[0].Status)
+	})
+}
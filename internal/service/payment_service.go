@@ -0,0 +1,293 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MogboPython/belvaphilips_backend/internal/config"
+	"github.com/MogboPython/belvaphilips_backend/internal/payment"
+	"github.com/MogboPython/belvaphilips_backend/internal/repository"
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvoiceNotPayable     = errors.New("only issued invoices can be paid")
+	ErrOrderCancelled        = errors.New("the order of the invoice was cancelled")
+	ErrPaymentNotRefundable  = errors.New("only successful payments can be refunded")
+	ErrRefundExceedsPayment  = errors.New("refund cannot exceed the amount left on the payment")
+	ErrPaymentAmountMismatch = errors.New("paid amount does not match the payment")
+	ErrPaymentInProgress     = errors.New("a checkout for the invoice is already being opened")
+)
+
+type PaymentService interface {
+	PayInvoice(ctx context.Context, invoiceID string) (*model.PaymentResponse, error)
+	GetInvoicePayments(invoiceID string) ([]*model.PaymentResponse, error)
+	RefundPayment(ctx context.Context, id string, req *model.PaymentRefundRequest) (*model.PaymentResponse, error)
+	HandleWebhook(payload []byte, signature string) error
+	WebhookSignatureHeader() string
+}
+
+type paymentService struct {
+	paymentRepo    repository.PaymentRepository
+	invoiceRepo    repository.InvoiceRepository
+	orderRepo      repository.OrderRepository
+	preferenceRepo repository.NotificationPreferenceRepository
+	provider       payment.Provider
+}
+
+func NewPaymentService(paymentRepo repository.PaymentRepository, invoiceRepo repository.InvoiceRepository, orderRepo repository.OrderRepository, preferenceRepo repository.NotificationPreferenceRepository, provider payment.Provider) PaymentService {
+	return &paymentService{
+		paymentRepo:    paymentRepo,
+		invoiceRepo:    invoiceRepo,
+		orderRepo:      orderRepo,
+		preferenceRepo: preferenceRepo,
+		provider:       provider,
+	}
+}
+
+// PayInvoice opens a checkout session with the payment provider for the total of an issued invoice
+func (s *paymentService) PayInvoice(ctx context.Context, invoiceID string) (*model.PaymentResponse, error) {
+	invoice, err := s.invoiceRepo.GetByID(invoiceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find invoice: %w", err)
+	}
+
+	if invoice.Status != model.InvoiceStatusIssued {
+		return nil, ErrInvoiceNotPayable
+	}
+
+	if invoice.Order.Status == model.OrderStatusCancelled {
+		return nil, ErrOrderCancelled
+	}
+
+	// An invoice has at most one pending payment, so its open checkout is handed out again
+	payments, err := s.paymentRepo.GetByInvoiceID(invoice.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find payments: %w", err)
+	}
+
+	for _, p := range payments {
+		if p.Status != model.PaymentStatusPending {
+			continue
+		}
+
+		if p.CheckoutURL == "" {
+			return nil, ErrPaymentInProgress
+		}
+
+		return mapPaymentToResponse(p), nil
+	}
+
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+
+	// The payment is saved before the checkout is opened so a webhook can never arrive for an unknown reference
+	p := &model.Payment{
+		InvoiceID: invoice.ID,
+		OrderID:   invoice.OrderID,
+		Provider:  s.provider.Name(),
+		Reference: "BELVA-PAY-" + strings.ToUpper(strings.ReplaceAll(id.String(), "-", "")[:12]),
+		Status:    model.PaymentStatusPending,
+		Currency:  invoice.Currency,
+		Amount:    invoice.Total,
+	}
+
+	if err := s.paymentRepo.Create(p); err != nil {
+		return nil, fmt.Errorf("failed to create payment: %w", err)
+	}
+
+	session, err := s.provider.CreateCheckoutSession(ctx, &payment.CheckoutRequest{
+		Reference:   p.Reference,
+		Email:       invoice.Order.User.Email,
+		Currency:    p.Currency,
+		CallbackURL: config.Config("PAYMENT_CALLBACK_URL"),
+		Amount:      p.Amount,
+	})
+	if err != nil {
+		p.FailureReason = err.Error()
+		if markErr := s.paymentRepo.MarkFailed(p); markErr != nil {
+			log.Errorf("Failed to mark payment %s as failed: %v", p.Reference, markErr)
+		}
+
+		return nil, fmt.Errorf("failed to open checkout: %w", err)
+	}
+
+	p.CheckoutURL = session.CheckoutURL
+
+	if err := s.paymentRepo.Update(p); err != nil {
+		return nil, fmt.Errorf("failed to save checkout: %w", err)
+	}
+
+	return mapPaymentToResponse(p), nil
+}
+
+func (s *paymentService) GetInvoicePayments(invoiceID string) ([]*model.PaymentResponse, error) {
+	payments, err := s.paymentRepo.GetByInvoiceID(invoiceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payments: %w", err)
+	}
+
+	paymentResponses := make([]*model.PaymentResponse, len(payments))
+	for i, p := range payments {
+		paymentResponses[i] = mapPaymentToResponse(p)
+	}
+
+	return paymentResponses, nil
+}
+
+// RefundPayment refunds part or all of what is left on a successful payment. Refunding all of it
+// voids the invoice
+func (s *paymentService) RefundPayment(ctx context.Context, id string, req *model.PaymentRefundRequest) (*model.PaymentResponse, error) {
+	p, err := s.paymentRepo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find payment: %w", err)
+	}
+
+	if p.Status != model.PaymentStatusSucceeded {
+		return nil, ErrPaymentNotRefundable
+	}
+
+	remaining := p.Amount - p.RefundedAmount
+
+	amount := req.Amount
+	if amount == 0 {
+		amount = remaining
+	}
+
+	if amount > remaining {
+		return nil, ErrRefundExceedsPayment
+	}
+
+	// The provider is only called once the refund is claimed, and the claim is rolled back when it fails
+	if err := s.paymentRepo.Refund(p, amount, func() error {
+		_, err := s.provider.Refund(ctx, p.Reference, amount)
+		return err
+	}); err != nil {
+		return nil, fmt.Errorf("failed to refund payment: %w", err)
+	}
+
+	return mapPaymentToResponse(p), nil
+}
+
+func (s *paymentService) WebhookSignatureHeader() string {
+	return s.provider.SignatureHeader()
+}
+
+// HandleWebhook verifies a provider notification and reconciles the payment it refers to.
+// Notifications are delivered at least once, so payments that are already settled are left alone
+func (s *paymentService) HandleWebhook(payload []byte, signature string) error {
+	event, err := s.provider.VerifyWebhook(payload, signature)
+	if err != nil {
+		return err
+	}
+
+	if event.Type == "" {
+		return nil
+	}
+
+	p, err := s.paymentRepo.GetByReference(event.Reference)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Warnf("Ignoring %s webhook for unknown payment %s", event.Type, event.Reference)
+			return nil
+		}
+
+		return fmt.Errorf("failed to find payment: %w", err)
+	}
+
+	if p.Status != model.PaymentStatusPending {
+		return nil
+	}
+
+	if event.Type == payment.EventPaymentFailed {
+		p.FailureReason = "declined by " + p.Provider
+		return s.paymentRepo.MarkFailed(p)
+	}
+
+	if err := checkPaymentEvent(p, event); err != nil {
+		p.FailureReason = err.Error()
+		log.Errorf("Payment %s could not be reconciled: %v", p.Reference, err)
+
+		return s.paymentRepo.MarkFailed(p)
+	}
+
+	return s.settleInvoice(p)
+}
+
+// checkPaymentEvent makes sure the provider collected exactly what the payment asked for
+func checkPaymentEvent(p *model.Payment, event *payment.Event) error {
+	if event.Amount != p.Amount || !strings.EqualFold(event.Currency, p.Currency) {
+		return fmt.Errorf("%w: expected %d %s, received %d %s", ErrPaymentAmountMismatch, p.Amount, p.Currency, event.Amount, event.Currency)
+	}
+
+	return nil
+}
+
+// settleInvoice marks a payment successful, its invoice paid and, when the order is waiting
+// on payment, moves the order to paid
+func (s *paymentService) settleInvoice(p *model.Payment) error {
+	invoice, err := s.invoiceRepo.GetByID(p.InvoiceID)
+	if err != nil {
+		return fmt.Errorf("failed to find invoice: %w", err)
+	}
+
+	if invoice.Status != model.InvoiceStatusIssued {
+		log.Warnf("Payment %s succeeded for invoice %s in status %s", p.Reference, invoice.InvoiceNumber, invoice.Status)
+		return s.paymentRepo.MarkSucceeded(p, nil, nil, nil)
+	}
+
+	if err := applyInvoiceStatus(invoice, model.InvoiceStatusPaid, time.Now()); err != nil {
+		return err
+	}
+
+	order := &invoice.Order
+	if order.Status != model.OrderStatusAccepted {
+		return s.paymentRepo.MarkSucceeded(p, invoice, nil, nil)
+	}
+
+	history := &model.OrderStatusHistory{
+		OrderID:       order.ID,
+		FromStatus:    order.Status,
+		ToStatus:      model.OrderStatusPaid,
+		ChangedBy:     p.Provider,
+		ChangedByRole: model.RoleSystem,
+		Note:          fmt.Sprintf("Invoice %s paid (%s)", invoice.InvoiceNumber, p.Reference),
+	}
+
+	emails, err := newOrderStatusNotifications(s.orderRepo, s.preferenceRepo, order, model.OrderStatusPaid, "")
+	if err != nil {
+		return err
+	}
+
+	if err := s.paymentRepo.MarkSucceeded(p, invoice, history, emails); err != nil {
+		return fmt.Errorf("failed to settle payment: %w", err)
+	}
+
+	return nil
+}
+
+func mapPaymentToResponse(p *model.Payment) *model.PaymentResponse {
+	return &model.PaymentResponse{
+		ID:             p.ID,
+		InvoiceID:      p.InvoiceID,
+		OrderID:        p.OrderID,
+		Provider:       p.Provider,
+		Reference:      p.Reference,
+		CheckoutURL:    p.CheckoutURL,
+		Status:         p.Status,
+		Currency:       p.Currency,
+		FailureReason:  p.FailureReason,
+		Amount:         p.Amount,
+		RefundedAmount: p.RefundedAmount,
+		PaidAt:         p.PaidAt,
+		CreatedAt:      p.CreatedAt,
+	}
+}
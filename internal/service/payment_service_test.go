@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/MogboPython/belvaphilips_backend/internal/payment"
+	"github.com/MogboPython/belvaphilips_backend/internal/repository"
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubPaymentRepository struct {
+	repository.PaymentRepository
+	refundErr error
+	payment   *model.Payment
+	invoice   *model.Invoice
+	history   *model.OrderStatusHistory
+	payments  []*model.Payment
+	succeeded int
+}
+
+func (r *stubPaymentRepository) GetByInvoiceID(_ string) ([]*model.Payment, error) {
+	return r.payments, nil
+}
+
+func (r *stubPaymentRepository) GetByID(_ string) (*model.Payment, error) {
+	return r.payment, nil
+}
+
+// Refund fails with refundErr before calling the provider, as when another refund claimed the payment
+func (r *stubPaymentRepository) Refund(p *model.Payment, amount int64, refund func() error) error {
+	if r.refundErr != nil {
+		return r.refundErr
+	}
+
+	if err := refund(); err != nil {
+		return err
+	}
+
+	p.RefundedAmount += amount
+	if p.RefundedAmount == p.Amount {
+		p.Status = model.PaymentStatusRefunded
+	}
+
+	return nil
+}
+
+func (r *stubPaymentRepository) GetByReference(_ string) (*model.Payment, error) {
+	return r.payment, nil
+}
+
+func (r *stubPaymentRepository) MarkSucceeded(p *model.Payment, invoice *model.Invoice, history *model.OrderStatusHistory, _ []*model.EmailOutbox) error {
+	r.succeeded++
+	p.Status = model.PaymentStatusSucceeded
+	r.invoice = invoice
+	r.history = history
+
+	return nil
+}
+
+func (*stubPaymentRepository) MarkFailed(p *model.Payment) error {
+	p.Status = model.PaymentStatusFailed
+	return nil
+}
+
+type stubInvoiceRepository struct {
+	repository.InvoiceRepository
+	invoice *model.Invoice
+}
+
+func (r *stubInvoiceRepository) GetByID(_ string) (*model.Invoice, error) {
+	return r.invoice, nil
+}
+
+// stubOrderRepository has status emails switched off so no template is rendered
+type stubOrderRepository struct {
+	repository.OrderRepository
+	orders []*model.Order
+}
+
+func (r *stubOrderRepository) GetByUserID(userID string, _, _ int) ([]*model.Order, error) {
+	var orders []*model.Order
+
+	for _, order := range r.orders {
+		if order.UserID == userID && !order.DeletedAt.Valid {
+			orders = append(orders, order)
+		}
+	}
+
+	return orders, nil
+}
+
+func (r *stubOrderRepository) GetByUserIDWithDeleted(userID string) ([]*model.Order, error) {
+	var orders []*model.Order
+
+	for _, order := range r.orders {
+		if order.UserID == userID {
+			orders = append(orders, order)
+		}
+	}
+
+	return orders, nil
+}
+
+func (*stubOrderRepository) GetStatusEmailSetting(status string) (*model.OrderStatusEmailSetting, error) {
+	return &model.OrderStatusEmailSetting{Status: status, EmailEnabled: false}, nil
+}
+
+func TestPaymentWebhook(t *testing.T) {
+	t.Parallel()
+
+	newService := func() (*paymentService, *stubPaymentRepository, *payment.FakeProvider) {
+		provider := payment.NewFakeProvider("secret")
+		paymentRepo := &stubPaymentRepository{
+			payment: &model.Payment{ID: "payment-id", InvoiceID: "invoice-id", Reference: "BELVA-PAY-1", Provider: "fake", Status: model.PaymentStatusPending, Currency: "NGN", Amount: 150000},
+		}
+		invoiceRepo := &stubInvoiceRepository{
+			invoice: &model.Invoice{ID: "invoice-id", Status: model.InvoiceStatusIssued, Order: model.Order{ID: "order-id", Status: model.OrderStatusAccepted}},
+		}
+
+		preferences := &stubPreferenceRepository{preferences: map[string]*model.NotificationPreference{}}
+
+		return &paymentService{paymentRepo: paymentRepo, invoiceRepo: invoiceRepo, orderRepo: &stubOrderRepository{}, preferenceRepo: preferences, provider: provider}, paymentRepo, provider
+	}
+
+	signed := func(provider *payment.FakeProvider, event payment.FakeEvent) ([]byte, string) {
+		payload, _ := json.Marshal(event)
+		return payload, provider.Sign(payload)
+	}
+
+	t.Run("Should mark the invoice paid and move the order to paid", func(t *testing.T) {
+		t.Parallel()
+
+		s, repo, provider := newService()
+
+		err := s.HandleWebhook(signed(provider, payment.FakeEvent{Type: payment.EventPaymentSucceeded, Reference: "BELVA-PAY-1", Currency: "NGN", Amount: 150000}))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, repo.succeeded)
+		assert.Equal(t, model.InvoiceStatusPaid, repo.invoice.Status)
+		assert.NotNil(t, repo.invoice.PaidAt)
+		assert.Equal(t, model.OrderStatusPaid, repo.history.ToStatus)
+	})
+
+	t.Run("Should ignore a webhook delivered twice", func(t *testing.T) {
+		t.Parallel()
+
+		s, repo, provider := newService()
+		payload, signature := signed(provider, payment.FakeEvent{Type: payment.EventPaymentSucceeded, Reference: "BELVA-PAY-1", Currency: "NGN", Amount: 150000})
+
+		assert.NoError(t, s.HandleWebhook(payload, signature))
+		assert.NoError(t, s.HandleWebhook(payload, signature))
+		assert.Equal(t, 1, repo.succeeded)
+	})
+
+	t.Run("Should fail the payment when the amount does not match", func(t *testing.T) {
+		t.Parallel()
+
+		s, repo, provider := newService()
+
+		err := s.HandleWebhook(signed(provider, payment.FakeEvent{Type: payment.EventPaymentSucceeded, Reference: "BELVA-PAY-1", Currency: "NGN", Amount: 100}))
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, repo.succeeded)
+		assert.Equal(t, model.PaymentStatusFailed, repo.payment.Status)
+		assert.Contains(t, repo.payment.FailureReason, ErrPaymentAmountMismatch.Error())
+	})
+
+	t.Run("Should reject an unsigned webhook", func(t *testing.T) {
+		t.Parallel()
+
+		s, repo, _ := newService()
+
+		err := s.HandleWebhook([]byte(`{"type":"payment.succeeded","reference":"BELVA-PAY-1"}`), "forged")
+
+		assert.ErrorIs(t, err, payment.ErrInvalidSignature)
+		assert.Equal(t, 0, repo.succeeded)
+	})
+}
+
+func TestPayInvoice(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Should refuse to pay the invoice of a cancelled order", func(t *testing.T) {
+		t.Parallel()
+
+		invoiceRepo := &stubInvoiceRepository{
+			invoice: &model.Invoice{ID: "invoice-id", Status: model.InvoiceStatusIssued, Order: model.Order{ID: "order-id", Status: model.OrderStatusCancelled}},
+		}
+		paymentRepo := &stubPaymentRepository{}
+		s := &paymentService{paymentRepo: paymentRepo, invoiceRepo: invoiceRepo, provider: payment.NewFakeProvider("secret")}
+
+		_, err := s.PayInvoice(context.Background(), "invoice-id")
+
+		assert.ErrorIs(t, err, ErrOrderCancelled)
+		assert.Nil(t, paymentRepo.payment)
+	})
+
+	t.Run("Should return the open checkout instead of opening another", func(t *testing.T) {
+		t.Parallel()
+
+		invoiceRepo := &stubInvoiceRepository{
+			invoice: &model.Invoice{ID: "invoice-id", Status: model.InvoiceStatusIssued, Order: model.Order{ID: "order-id", Status: model.OrderStatusAccepted}},
+		}
+		paymentRepo := &stubPaymentRepository{payments: []*model.Payment{
+			{ID: "payment-2", InvoiceID: "invoice-id", Status: model.PaymentStatusPending, CheckoutURL: "https://checkout.example.com/2"},
+			{ID: "payment-1", InvoiceID: "invoice-id", Status: model.PaymentStatusFailed, CheckoutURL: "https://checkout.example.com/1"},
+		}}
+		s := &paymentService{paymentRepo: paymentRepo, invoiceRepo: invoiceRepo, provider: payment.NewFakeProvider("secret")}
+
+		p, err := s.PayInvoice(context.Background(), "invoice-id")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "payment-2", p.ID)
+		assert.Equal(t, "https://checkout.example.com/2", p.CheckoutURL)
+	})
+
+	t.Run("Should refuse while another request is opening the checkout", func(t *testing.T) {
+		t.Parallel()
+
+		invoiceRepo := &stubInvoiceRepository{
+			invoice: &model.Invoice{ID: "invoice-id", Status: model.InvoiceStatusIssued, Order: model.Order{ID: "order-id", Status: model.OrderStatusAccepted}},
+		}
+		paymentRepo := &stubPaymentRepository{payments: []*model.Payment{{ID: "payment-1", InvoiceID: "invoice-id", Status: model.PaymentStatusPending}}}
+		s := &paymentService{paymentRepo: paymentRepo, invoiceRepo: invoiceRepo, provider: payment.NewFakeProvider("secret")}
+
+		_, err := s.PayInvoice(context.Background(), "invoice-id")
+
+		assert.ErrorIs(t, err, ErrPaymentInProgress)
+	})
+}
+
+func TestRefundPayment(t *testing.T) {
+	t.Parallel()
+
+	newService := func(refundErr error) (*paymentService, *payment.FakeProvider) {
+		provider := payment.NewFakeProvider("secret")
+		paymentRepo := &stubPaymentRepository{
+			refundErr: refundErr,
+			payment:   &model.Payment{ID: "payment-id", Reference: "BELVA-PAY-1", Status: model.PaymentStatusSucceeded, Currency: "NGN", Amount: 150000},
+		}
+
+		return &paymentService{paymentRepo: paymentRepo, provider: provider}, provider
+	}
+
+	t.Run("Should refund everything left when no amount is given", func(t *testing.T) {
+		t.Parallel()
+
+		s, provider := newService(nil)
+
+		p, err := s.RefundPayment(context.Background(), "payment-id", &model.PaymentRefundRequest{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.PaymentStatusRefunded, p.Status)
+		assert.Equal(t, int64(150000), p.RefundedAmount)
+		assert.Len(t, provider.Refunds(), 1)
+	})
+
+	t.Run("Should not reach the provider when another refund claimed the payment", func(t *testing.T) {
+		t.Parallel()
+
+		s, provider := newService(errors.New("payment was refunded concurrently"))
+
+		_, err := s.RefundPayment(context.Background(), "payment-id", &model.PaymentRefundRequest{Amount: 1000})
+
+		assert.Error(t, err)
+		assert.Empty(t, provider.Refunds())
+	})
+
+	t.Run("Should refuse to refund more than is left", func(t *testing.T) {
+		t.Parallel()
+
+		s, provider := newService(nil)
+
+		_, err := s.RefundPayment(context.Background(), "payment-id", &model.PaymentRefundRequest{Amount: 200000})
+
+		assert.ErrorIs(t, err, ErrRefundExceedsPayment)
+		assert.Empty(t, provider.Refunds())
+	})
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+)
+
+var ErrMissingPrice = errors.New("no price configured for order option")
+
+// PriceList looks up the unit price of an order option in minor currency units
+type PriceList interface {
+	UnitPrice(kind, name string) (int64, bool)
+}
+
+// buildQuoteLineItems derives the quote lines of an order from its options: the shoot type,
+// finish type, every shot and the delivery speed are each charged per unit ordered
+func buildQuoteLineItems(order *model.Order, prices PriceList) ([]model.QuoteLineItem, error) {
+	quantity := max(order.Quantity, 1)
+
+	type option struct {
+		kind, name string
+	}
+
+	options := []option{{model.LineItemKindShootType, order.ShootType}}
+	if order.FinishType != "" {
+		options = append(options, option{model.LineItemKindFinishType, order.FinishType})
+	}
+
+	for _, shot := range order.Shots {
+		options = append(options, option{model.LineItemKindShot, shot})
+	}
+
+	if order.DeliverySpeed != "" {
+		options = append(options, option{model.LineItemKindDeliverySpeed, order.DeliverySpeed})
+	}
+
+	var missing []string
+
+	lineItems := make([]model.QuoteLineItem, 0, len(options))
+
+	for _, opt := range options {
+		unitPrice, ok := prices.UnitPrice(opt.kind, opt.name)
+		if !ok {
+			missing = append(missing, fmt.Sprintf("%s %q", opt.kind, opt.name))
+			continue
+		}
+
+		lineItems = append(lineItems, model.QuoteLineItem{
+			Kind:        opt.kind,
+			Description: fmt.Sprintf("%s: %s", strings.ReplaceAll(opt.kind, "_", " "), opt.name),
+			Quantity:    quantity,
+			UnitPrice:   unitPrice,
+			Amount:      unitPrice * int64(quantity),
+			Position:    len(lineItems),
+		})
+	}
+
+	if len(missing) > 0 {
+		return lineItems, fmt.Errorf("%w: %s", ErrMissingPrice, strings.Join(missing, ", "))
+	}
+
+	return lineItems, nil
+}
+
+// withPlanDiscount adds the discount of the membership plan an order was placed under as a
+// negative line taking the percentage off the other lines
+func withPlanDiscount(order *model.Order, lineItems []model.QuoteLineItem) []model.QuoteLineItem {
+	if order.DiscountPercent <= 0 {
+		return lineItems
+	}
+
+	discount := sumLineItems(lineItems) * int64(order.DiscountPercent) / 100
+	if discount <= 0 {
+		return lineItems
+	}
+
+	return append(lineItems, model.QuoteLineItem{
+		Kind:        model.LineItemKindDiscount,
+		Description: fmt.Sprintf("%s membership discount (%d%%)", order.MembershipType, order.DiscountPercent),
+		Quantity:    1,
+		UnitPrice:   -discount,
+		Amount:      -discount,
+		Position:    len(lineItems),
+	})
+}
+
+// lineItemsFromRequest turns manually entered quote lines into line items
+func lineItemsFromRequest(items []model.QuoteLineItemRequest) []model.QuoteLineItem {
+	lineItems := make([]model.QuoteLineItem, len(items))
+
+	for i, item := range items {
+		lineItems[i] = model.QuoteLineItem{
+			Kind:        item.Kind,
+			Description: item.Description,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice,
+			Amount:      item.UnitPrice * int64(item.Quantity),
+			Position:    i,
+		}
+	}
+
+	return lineItems
+}
+
+func sumLineItems(lineItems []model.QuoteLineItem) int64 {
+	var total int64
+
+	for _, item := range lineItems {
+		total += item.Amount
+	}
+
+	return total
+}
@@ -0,0 +1,75 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+type testPriceList map[string]map[string]int64
+
+func (p testPriceList) UnitPrice(kind, name string) (int64, bool) {
+	price, ok := p[kind][strings.ToLower(name)]
+	return price, ok
+}
+
+func TestBuildQuoteLineItems(t *testing.T) {
+	t.Parallel()
+
+	prices := testPriceList{
+		"shoot_type":     {"flat lay": 500000},
+		"finish_type":    {"white background": 100000},
+		"shot":           {"front": 50000, "back": 50000},
+		"delivery_speed": {"express": 200000},
+	}
+
+	t.Run("Should charge every option per unit ordered", func(t *testing.T) {
+		t.Parallel()
+
+		order := &model.Order{
+			ShootType:     "flat lay",
+			FinishType:    "White Background",
+			Shots:         pq.StringArray{"Front", "Back"},
+			DeliverySpeed: "Express",
+			Quantity:      3,
+		}
+
+		lineItems, err := buildQuoteLineItems(order, prices)
+
+		assert.NoError(t, err)
+		assert.Len(t, lineItems, 5)
+		assert.Equal(t, int64(1500000), lineItems[0].Amount)
+		assert.Equal(t, int64(3*(500000+100000+50000+50000+200000)), sumLineItems(lineItems))
+	})
+
+	t.Run("Should report options without a price", func(t *testing.T) {
+		t.Parallel()
+
+		order := &model.Order{ShootType: "Flat Lay", DeliverySpeed: "Same Day", Quantity: 1}
+
+		lineItems, err := buildQuoteLineItems(order, prices)
+
+		assert.ErrorIs(t, err, ErrMissingPrice)
+		assert.Contains(t, err.Error(), `delivery_speed "Same Day"`)
+		assert.Len(t, lineItems, 1)
+	})
+
+	t.Run("Should take the membership discount off the quote", func(t *testing.T) {
+		t.Parallel()
+
+		order := &model.Order{ShootType: "Flat Lay", Quantity: 2, MembershipType: "Studio", DiscountPercent: 15}
+
+		lineItems, err := buildQuoteLineItems(order, prices)
+		assert.NoError(t, err)
+
+		lineItems = withPlanDiscount(order, lineItems)
+
+		assert.Len(t, lineItems, 2)
+		assert.Equal(t, model.LineItemKindDiscount, lineItems[1].Kind)
+		assert.Equal(t, int64(-150000), lineItems[1].Amount)
+		assert.Equal(t, int64(850000), sumLineItems(lineItems))
+	})
+}
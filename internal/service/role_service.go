@@ -0,0 +1,230 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/MogboPython/belvaphilips_backend/internal/repository"
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+)
+
+var (
+	ErrUnknownPermission          = errors.New("unknown permission")
+	ErrOwnerRoleReadOnly          = errors.New("the owner role cannot be changed or deleted")
+	ErrCannotRevokeOwnStaffAccess = errors.New("admins cannot remove their own staff:manage permission")
+)
+
+type RoleService interface {
+	GetPermissions() ([]*model.Permission, error)
+	GetRoles() ([]*model.RoleResponse, error)
+	CreateRole(req *model.RoleRequest) (*model.RoleResponse, error)
+	UpdateRole(id string, req *model.RoleRequest) (*model.RoleResponse, error)
+	DeleteRole(id string) error
+	GetAdminRoles(adminID string) ([]*model.RoleResponse, error)
+	SetAdminRoles(adminID string, actor *model.Actor, req *model.AdminRolesRequest) ([]*model.RoleResponse, error)
+	HasPermissions(adminID string, permissions ...string) (bool, error)
+	MustChangePassword(adminID string) (bool, error)
+}
+
+type roleService struct {
+	roleRepo  repository.RoleRepository
+	adminRepo repository.AdminRepository
+}
+
+func NewRoleService(roleRepo repository.RoleRepository, adminRepo repository.AdminRepository) RoleService {
+	return &roleService{
+		roleRepo:  roleRepo,
+		adminRepo: adminRepo,
+	}
+}
+
+func (s *roleService) GetPermissions() ([]*model.Permission, error) {
+	permissions, err := s.roleRepo.GetPermissions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get permissions: %w", err)
+	}
+
+	return permissions, nil
+}
+
+func (s *roleService) GetRoles() ([]*model.RoleResponse, error) {
+	roles, err := s.roleRepo.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get roles: %w", err)
+	}
+
+	return mapRolesToResponse(roles), nil
+}
+
+func (s *roleService) CreateRole(req *model.RoleRequest) (*model.RoleResponse, error) {
+	permissions, err := s.rolePermissions("", req.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	role := &model.Role{
+		Name:        req.Name,
+		Description: req.Description,
+		Permissions: permissions,
+	}
+
+	if err := s.roleRepo.Create(role); err != nil {
+		return nil, fmt.Errorf("failed to create role: %w", err)
+	}
+
+	return mapRoleToResponse(role), nil
+}
+
+// UpdateRole renames a role and replaces its permissions
+func (s *roleService) UpdateRole(id string, req *model.RoleRequest) (*model.RoleResponse, error) {
+	role, err := s.roleRepo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find role: %w", err)
+	}
+
+	if role.Name == model.OwnerRole {
+		return nil, ErrOwnerRoleReadOnly
+	}
+
+	if role.Permissions, err = s.rolePermissions(role.ID, req.Permissions); err != nil {
+		return nil, err
+	}
+
+	role.Name = req.Name
+	role.Description = req.Description
+
+	if err := s.roleRepo.Update(role); err != nil {
+		return nil, fmt.Errorf("failed to update role: %w", err)
+	}
+
+	return mapRoleToResponse(role), nil
+}
+
+func (s *roleService) DeleteRole(id string) error {
+	role, err := s.roleRepo.GetByID(id)
+	if err != nil {
+		return fmt.Errorf("failed to find role: %w", err)
+	}
+
+	if role.Name == model.OwnerRole {
+		return ErrOwnerRoleReadOnly
+	}
+
+	if err := s.roleRepo.Delete(role.ID); err != nil {
+		return fmt.Errorf("failed to delete role: %w", err)
+	}
+
+	return nil
+}
+
+func (s *roleService) GetAdminRoles(adminID string) ([]*model.RoleResponse, error) {
+	if _, err := s.adminRepo.GetByID(adminID); err != nil {
+		return nil, fmt.Errorf("failed to find admin: %w", err)
+	}
+
+	roles, err := s.roleRepo.GetAdminRoles(adminID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get admin roles: %w", err)
+	}
+
+	return mapRolesToResponse(roles), nil
+}
+
+// SetAdminRoles replaces the roles of a staff account. Admins cannot take away their own
+// access to role management
+func (s *roleService) SetAdminRoles(adminID string, actor *model.Actor, req *model.AdminRolesRequest) ([]*model.RoleResponse, error) {
+	if _, err := s.adminRepo.GetByID(adminID); err != nil {
+		return nil, fmt.Errorf("failed to find admin: %w", err)
+	}
+
+	roleIDs := slices.Compact(slices.Sorted(slices.Values(req.RoleIDs)))
+
+	if adminID == actor.ID {
+		keepsAccess, err := s.grantsPermission(roleIDs, model.PermissionStaffManage)
+		if err != nil {
+			return nil, err
+		}
+
+		if !keepsAccess {
+			return nil, ErrCannotRevokeOwnStaffAccess
+		}
+	}
+
+	if err := s.roleRepo.SetAdminRoles(adminID, roleIDs); err != nil {
+		return nil, fmt.Errorf("failed to set admin roles: %w", err)
+	}
+
+	return s.GetAdminRoles(adminID)
+}
+
+func (s *roleService) HasPermissions(adminID string, permissions ...string) (bool, error) {
+	return s.roleRepo.HasPermissions(adminID, permissions...)
+}
+
+func (s *roleService) MustChangePassword(adminID string) (bool, error) {
+	return s.roleRepo.MustChangePassword(adminID)
+}
+
+// rolePermissions checks the requested permission keys against the known permissions
+func (s *roleService) rolePermissions(roleID string, keys []string) ([]model.RolePermission, error) {
+	known, err := s.roleRepo.GetPermissions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get permissions: %w", err)
+	}
+
+	keys = slices.Compact(slices.Sorted(slices.Values(keys)))
+	permissions := make([]model.RolePermission, len(keys))
+
+	for i, key := range keys {
+		if !slices.ContainsFunc(known, func(p *model.Permission) bool { return p.Key == key }) {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownPermission, key)
+		}
+
+		permissions[i] = model.RolePermission{RoleID: roleID, Permission: key}
+	}
+
+	return permissions, nil
+}
+
+// grantsPermission reports whether any of the roles grants the permission
+func (s *roleService) grantsPermission(roleIDs []string, permission string) (bool, error) {
+	for _, roleID := range roleIDs {
+		role, err := s.roleRepo.GetByID(roleID)
+		if err != nil {
+			return false, fmt.Errorf("failed to find role: %w", err)
+		}
+
+		for _, p := range role.Permissions {
+			if p.Permission == permission {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+func mapRoleToResponse(role *model.Role) *model.RoleResponse {
+	permissions := make([]string, len(role.Permissions))
+	for i, p := range role.Permissions {
+		permissions[i] = p.Permission
+	}
+
+	return &model.RoleResponse{
+		ID:          role.ID,
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: permissions,
+		CreatedAt:   role.CreatedAt,
+	}
+}
+
+func mapRolesToResponse(roles []*model.Role) []*model.RoleResponse {
+	roleResponses := make([]*model.RoleResponse, len(roles))
+	for i, role := range roles {
+		roleResponses[i] = mapRoleToResponse(role)
+	}
+
+	return roleResponses
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/MogboPython/belvaphilips_backend/internal/repository"
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type stubRoleRepository struct {
+	repository.RoleRepository
+	roles      map[string]*model.Role
+	adminRoles map[string][]string
+}
+
+// newStubRoleRepository holds the seeded owner role and an editor role without staff access
+func newStubRoleRepository(t *testing.T) *stubRoleRepository {
+	t.Helper()
+
+	return &stubRoleRepository{
+		roles: map[string]*model.Role{
+			"role-owner": {ID: "role-owner", Name: model.OwnerRole, Permissions: []model.RolePermission{
+				{RoleID: "role-owner", Permission: model.PermissionStaffManage},
+				{RoleID: "role-owner", Permission: model.PermissionPostsPublish},
+			}},
+			"role-editor": {ID: "role-editor", Name: "editor", Permissions: []model.RolePermission{
+				{RoleID: "role-editor", Permission: model.PermissionPostsPublish},
+			}},
+		},
+		adminRoles: map[string][]string{},
+	}
+}
+
+func (*stubRoleRepository) GetPermissions() ([]*model.Permission, error) {
+	return []*model.Permission{{Key: model.PermissionPostsPublish}, {Key: model.PermissionStaffManage}}, nil
+}
+
+func (r *stubRoleRepository) GetByID(id string) (*model.Role, error) {
+	if role, ok := r.roles[id]; ok {
+		return role, nil
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *stubRoleRepository) GetByName(name string) (*model.Role, error) {
+	for _, role := range r.roles {
+		if role.Name == name {
+			return role, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (*stubRoleRepository) Create(role *model.Role) error {
+	role.ID = "role-" + role.Name
+	return nil
+}
+
+func (*stubRoleRepository) Update(*model.Role) error {
+	return nil
+}
+
+func (r *stubRoleRepository) GetAdminRoles(adminID string) ([]*model.Role, error) {
+	roles := make([]*model.Role, len(r.adminRoles[adminID]))
+	for i, id := range r.adminRoles[adminID] {
+		roles[i] = r.roles[id]
+	}
+
+	return roles, nil
+}
+
+func (r *stubRoleRepository) SetAdminRoles(adminID string, roleIDs []string) error {
+	r.adminRoles[adminID] = roleIDs
+	return nil
+}
+
+func TestRoles(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Should reject permissions that do not exist", func(t *testing.T) {
+		t.Parallel()
+
+		s := &roleService{roleRepo: newStubRoleRepository(t)}
+
+		_, err := s.CreateRole(&model.RoleRequest{Name: "intern", Permissions: []string{"orders:delete_everything"}})
+
+		assert.ErrorIs(t, err, ErrUnknownPermission)
+	})
+
+	t.Run("Should create roles without duplicate permissions", func(t *testing.T) {
+		t.Parallel()
+
+		s := &roleService{roleRepo: newStubRoleRepository(t)}
+
+		role, err := s.CreateRole(&model.RoleRequest{Name: "blogger", Permissions: []string{model.PermissionPostsPublish, model.PermissionPostsPublish}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{model.PermissionPostsPublish}, role.Permissions)
+	})
+
+	t.Run("Should keep the owner role read only", func(t *testing.T) {
+		t.Parallel()
+
+		s := &roleService{roleRepo: newStubRoleRepository(t)}
+
+		_, err := s.UpdateRole("role-owner", &model.RoleRequest{Name: model.OwnerRole, Permissions: []string{model.PermissionPostsPublish}})
+		assert.ErrorIs(t, err, ErrOwnerRoleReadOnly)
+
+		assert.ErrorIs(t, s.DeleteRole("role-owner"), ErrOwnerRoleReadOnly)
+	})
+}
+
+func TestSetAdminRoles(t *testing.T) {
+	t.Parallel()
+
+	actor := &model.Actor{ID: "admin-1", Role: model.RoleAdmin}
+
+	t.Run("Should not let admins remove their own staff access", func(t *testing.T) {
+		t.Parallel()
+
+		s := &roleService{roleRepo: newStubRoleRepository(t), adminRepo: newStubAdminRepository(t, &model.Admin{ID: "admin-1", Active: true})}
+
+		_, err := s.SetAdminRoles("admin-1", actor, &model.AdminRolesRequest{RoleIDs: []string{"role-editor"}})
+
+		assert.ErrorIs(t, err, ErrCannotRevokeOwnStaffAccess)
+	})
+
+	t.Run("Should replace the roles of other admins", func(t *testing.T) {
+		t.Parallel()
+
+		roleRepo := newStubRoleRepository(t)
+		s := &roleService{roleRepo: roleRepo, adminRepo: newStubAdminRepository(t, &model.Admin{ID: "admin-2", Active: true})}
+
+		roles, err := s.SetAdminRoles("admin-2", actor, &model.AdminRolesRequest{RoleIDs: []string{"role-editor", "role-editor"}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"role-editor"}, roleRepo.adminRoles["admin-2"])
+		assert.Len(t, roles, 1)
+	})
+}
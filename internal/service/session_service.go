@@ -0,0 +1,170 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MogboPython/belvaphilips_backend/internal/repository"
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/MogboPython/belvaphilips_backend/pkg/utils"
+	"github.com/gofiber/fiber/v2/log"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+
+const refreshTokenTTL = 30 * 24 * time.Hour
+
+type SessionService interface {
+	Refresh(refreshToken string) (*model.TokenResponse, error)
+	Logout(refreshToken string) error
+	RevokeSessions(subjectID string) error
+	IsRevoked(jti string) (bool, error)
+}
+
+type sessionService struct {
+	sessionRepo repository.SessionRepository
+}
+
+func NewSessionService(sessionRepo repository.SessionRepository) SessionService {
+	return &sessionService{
+		sessionRepo: sessionRepo,
+	}
+}
+
+// Refresh trades a refresh token for a new access token and a new refresh token. Presenting a
+// refresh token that was already rotated means it leaked, so the whole session is revoked
+func (s *sessionService) Refresh(refreshToken string) (*model.TokenResponse, error) {
+	current, err := s.sessionRepo.GetByHash(utils.HashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+
+		return nil, fmt.Errorf("failed to find refresh token: %w", err)
+	}
+
+	if current.RevokedAt != nil {
+		s.revokeReusedFamily(current)
+		return nil, ErrInvalidRefreshToken
+	}
+
+	if time.Now().After(current.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	next, tokens, err := newSession(current.SubjectID, current.Role, current.FamilyID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.sessionRepo.Rotate(current, next); err != nil {
+		if strings.Contains(err.Error(), "refresh token was already used") {
+			s.revokeReusedFamily(current)
+			return nil, ErrInvalidRefreshToken
+		}
+
+		return nil, fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+
+	return tokens, nil
+}
+
+// Logout revokes the session of a refresh token together with its current access token
+func (s *sessionService) Logout(refreshToken string) error {
+	current, err := s.sessionRepo.GetByHash(utils.HashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidRefreshToken
+		}
+
+		return fmt.Errorf("failed to find refresh token: %w", err)
+	}
+
+	if err := s.sessionRepo.RevokeFamily(current.FamilyID); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeSessions signs a user or admin out of every device
+func (s *sessionService) RevokeSessions(subjectID string) error {
+	if err := s.sessionRepo.RevokeSubject(subjectID); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+
+	return nil
+}
+
+func (s *sessionService) IsRevoked(jti string) (bool, error) {
+	return s.sessionRepo.IsRevoked(jti)
+}
+
+func (s *sessionService) revokeReusedFamily(token *model.RefreshToken) {
+	log.Warnf("Refresh token of %s was reused, revoking session %s", token.SubjectID, token.FamilyID)
+
+	if err := s.sessionRepo.RevokeFamily(token.FamilyID); err != nil {
+		log.Errorf("Failed to revoke session %s: %v", token.FamilyID, err)
+	}
+}
+
+// issueSession starts a new session for a user or admin and saves its refresh token
+func issueSession(sessionRepo repository.SessionRepository, subjectID, role string) (*model.TokenResponse, error) {
+	familyID, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+
+	token, tokens, err := newSession(subjectID, role, familyID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := sessionRepo.Create(token); err != nil {
+		return nil, fmt.Errorf("failed to save refresh token: %w", err)
+	}
+
+	return tokens, nil
+}
+
+// newSession signs an access token and generates the refresh token that renews it. Only the
+// hash of the refresh token is kept in the returned record
+func newSession(subjectID, role, familyID string) (*model.RefreshToken, *model.TokenResponse, error) {
+	jti, err := uuid.NewRandom()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	accessToken, err := utils.GenerateToken(subjectID, role, jti.String())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	refreshToken, err := utils.GenerateRefreshToken()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	now := time.Now()
+
+	token := &model.RefreshToken{
+		FamilyID:        familyID,
+		SubjectID:       subjectID,
+		Role:            role,
+		TokenHash:       utils.HashToken(refreshToken),
+		AccessTokenID:   jti.String(),
+		AccessExpiresAt: now.Add(utils.AccessTokenTTL),
+		ExpiresAt:       now.Add(refreshTokenTTL),
+	}
+
+	return token, &model.TokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(utils.AccessTokenTTL.Seconds()),
+	}, nil
+}
@@ -0,0 +1,130 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/MogboPython/belvaphilips_backend/internal/repository"
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type stubSessionRepository struct {
+	repository.SessionRepository
+	tokens          map[string]*model.RefreshToken
+	revokedFamilies []string
+	revokedSubjects []string
+	keptTokenIDs    []string
+}
+
+func newStubSessionRepository(t *testing.T) *stubSessionRepository {
+	t.Helper()
+
+	return &stubSessionRepository{tokens: map[string]*model.RefreshToken{}}
+}
+
+func (r *stubSessionRepository) Create(token *model.RefreshToken) error {
+	r.tokens[token.TokenHash] = token
+	return nil
+}
+
+func (r *stubSessionRepository) GetByHash(hash string) (*model.RefreshToken, error) {
+	if token, ok := r.tokens[hash]; ok {
+		return token, nil
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *stubSessionRepository) Rotate(current, next *model.RefreshToken) error {
+	if current.RevokedAt != nil {
+		return errors.New("refresh token was already used")
+	}
+
+	now := time.Now()
+	current.RevokedAt = &now
+	r.tokens[next.TokenHash] = next
+
+	return nil
+}
+
+func (r *stubSessionRepository) RevokeFamily(familyID string) error {
+	r.revokedFamilies = append(r.revokedFamilies, familyID)
+	return nil
+}
+
+func (r *stubSessionRepository) RevokeSubject(subjectID string) error {
+	r.revokedSubjects = append(r.revokedSubjects, subjectID)
+	return nil
+}
+
+func (r *stubSessionRepository) RevokeOtherSessions(subjectID, accessTokenID string) error {
+	r.revokedSubjects = append(r.revokedSubjects, subjectID)
+	r.keptTokenIDs = append(r.keptTokenIDs, accessTokenID)
+
+	return nil
+}
+
+func TestRefreshSession(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Should rotate the refresh token within the same session", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newStubSessionRepository(t)
+		s := &sessionService{sessionRepo: repo}
+
+		first, err := issueSession(repo, "user-1", model.RoleAuthenticated)
+		assert.NoError(t, err)
+
+		second, err := s.Refresh(first.RefreshToken)
+		assert.NoError(t, err)
+		assert.NotEqual(t, first.RefreshToken, second.RefreshToken)
+		assert.NotEqual(t, first.AccessToken, second.AccessToken)
+		assert.Len(t, repo.tokens, 2)
+
+		for _, token := range repo.tokens {
+			assert.Equal(t, "user-1", token.SubjectID)
+			assert.NotContains(t, []string{first.RefreshToken, second.RefreshToken}, token.TokenHash)
+		}
+	})
+
+	t.Run("Should revoke the session when a used refresh token comes back", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newStubSessionRepository(t)
+		s := &sessionService{sessionRepo: repo}
+
+		first, err := issueSession(repo, "user-1", model.RoleAuthenticated)
+		assert.NoError(t, err)
+
+		_, err = s.Refresh(first.RefreshToken)
+		assert.NoError(t, err)
+
+		_, err = s.Refresh(first.RefreshToken)
+		assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+		assert.Len(t, repo.revokedFamilies, 1)
+	})
+
+	t.Run("Should reject unknown and expired refresh tokens", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newStubSessionRepository(t)
+		s := &sessionService{sessionRepo: repo}
+
+		_, err := s.Refresh("not-a-refresh-token")
+		assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+
+		tokens, err := issueSession(repo, "user-1", model.RoleAuthenticated)
+		assert.NoError(t, err)
+
+		for _, token := range repo.tokens {
+			token.ExpiresAt = time.Now().Add(-time.Minute)
+		}
+
+		_, err = s.Refresh(tokens.RefreshToken)
+		assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+	})
+}
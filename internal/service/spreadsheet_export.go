@@ -0,0 +1,260 @@
+package service
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/xuri/excelize/v2"
+)
+
+var ErrUnknownExportFormat = errors.New("export format must be csv or xlsx")
+
+const spreadsheetBatchSize = 500
+
+// ExportOrders returns a spreadsheet of the orders matching the filters of the admin order list,
+// with a column for every key used in their details
+func (s *exportService) ExportOrders(format string, request *model.OrderFilterRequest) (*model.Spreadsheet, error) {
+	filter, err := parseOrderFilter(request)
+	if err != nil {
+		return nil, err
+	}
+
+	detailKeys, err := s.orderRepo.GetDetailKeys(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get order detail keys: %w", err)
+	}
+
+	header := []any{
+		"id", "order_name", "created_at", "status", "customer_name", "customer_email", "company_name",
+		"organization_id", "product_name", "product_description", "shoot_type", "finish_type", "delivery_speed",
+		"membership_type", "quantity", "shots", "currency", "quoted_amount", "discount_percent", "cancellation_reason",
+	}
+
+	for _, key := range detailKeys {
+		header = append(header, "details."+key)
+	}
+
+	return newSpreadsheet("orders", format, header, func(writeRow func([]any) error) error {
+		return s.orderRepo.GetAllInBatches(filter, spreadsheetBatchSize, func(orders []*model.Order) error {
+			for _, order := range orders {
+				row, err := orderSpreadsheetRow(order, detailKeys)
+				if err != nil {
+					return err
+				}
+
+				if err := writeRow(row); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	})
+}
+
+// ExportUsers returns a spreadsheet of every customer, oldest first
+func (s *exportService) ExportUsers(format string) (*model.Spreadsheet, error) {
+	header := []any{
+		"id", "name", "email", "company_name", "phone_number", "membership_status", "created_at", "updated_at", "deleted_at",
+	}
+
+	return newSpreadsheet("users", format, header, func(writeRow func([]any) error) error {
+		return s.userRepo.GetAllInBatches(spreadsheetBatchSize, func(users []*model.User) error {
+			for _, user := range users {
+				deletedAt := ""
+				if user.DeletedAt != nil {
+					deletedAt = user.DeletedAt.Format(time.RFC3339)
+				}
+
+				if err := writeRow([]any{
+					user.ID, user.Name, user.Email, user.CompanyName, user.PhoneNumber, user.MembershipStatus,
+					user.CreatedAt.Format(time.RFC3339), user.UpdatedAt.Format(time.RFC3339), deletedAt,
+				}); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	})
+}
+
+// orderSpreadsheetRow flattens an order into a row, with the value of every detail key in its own
+// column. Nested details are kept as JSON
+func orderSpreadsheetRow(order *model.Order, detailKeys []string) ([]any, error) {
+	organizationID := ""
+	if order.OrganizationID != nil {
+		organizationID = *order.OrganizationID
+	}
+
+	row := []any{
+		order.ID, order.OrderName, order.CreatedAt.Format(time.RFC3339), order.Status, order.User.Name,
+		order.User.Email, order.User.CompanyName, organizationID, order.ProductName, order.ProductDescription,
+		order.ShootType, order.FinishType, order.DeliverySpeed, order.MembershipType, order.Quantity,
+		strings.Join(order.Shots, "\n"), order.Currency, order.QuotedAmount, order.DiscountPercent,
+		order.CancellationReason,
+	}
+
+	details, err := decodeDetails(order.Details)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, key := range detailKeys {
+		switch value := details[key].(type) {
+		case nil:
+			row = append(row, "")
+		case string, float64, bool:
+			row = append(row, value)
+		default:
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal detail %s: %w", key, err)
+			}
+
+			row = append(row, string(encoded))
+		}
+	}
+
+	return row, nil
+}
+
+// newSpreadsheet describes a spreadsheet named after name and today's date. writeRows is called
+// once the download starts, with a function writing a row after the header
+func newSpreadsheet(name, format string, header []any, writeRows func(writeRow func([]any) error) error) (*model.Spreadsheet, error) {
+	spreadsheet := &model.Spreadsheet{
+		FileName: fmt.Sprintf("%s-%s.%s", name, time.Now().Format(time.DateOnly), format),
+	}
+
+	var newWriter func(w io.Writer) (rowWriter, error)
+
+	switch format {
+	case model.SpreadsheetFormatCSV:
+		spreadsheet.ContentType = "text/csv; charset=utf-8"
+		newWriter = newCSVRowWriter
+	case model.SpreadsheetFormatXLSX:
+		spreadsheet.ContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+		newWriter = func(w io.Writer) (rowWriter, error) {
+			writer, err := newXLSXRowWriter(w, name)
+			if err != nil {
+				return nil, err
+			}
+
+			return writer, nil
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownExportFormat, format)
+	}
+
+	spreadsheet.Write = func(w io.Writer) error {
+		writer, err := newWriter(w)
+		if err != nil {
+			return err
+		}
+
+		if err := writer.WriteRow(header); err != nil {
+			writer.Close()
+			return err
+		}
+
+		if err := writeRows(writer.WriteRow); err != nil {
+			writer.Close()
+			return err
+		}
+
+		return writer.Close()
+	}
+
+	return spreadsheet, nil
+}
+
+// rowWriter writes the rows of a spreadsheet one at a time. Close finishes the file
+type rowWriter interface {
+	WriteRow(row []any) error
+	Close() error
+}
+
+type csvRowWriter struct {
+	writer *csv.Writer
+}
+
+func newCSVRowWriter(w io.Writer) (rowWriter, error) {
+	return &csvRowWriter{writer: csv.NewWriter(w)}, nil
+}
+
+func (w *csvRowWriter) WriteRow(row []any) error {
+	record := make([]string, len(row))
+
+	for i, value := range row {
+		switch v := value.(type) {
+		case string:
+			record[i] = csvSafe(v)
+		case float64:
+			record[i] = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			record[i] = fmt.Sprint(v)
+		}
+	}
+
+	return w.writer.Write(record)
+}
+
+func (w *csvRowWriter) Close() error {
+	w.writer.Flush()
+
+	return w.writer.Error()
+}
+
+// xlsxRowWriter streams rows into a single sheet. excelize keeps large sheets in a temporary
+// file rather than in memory until the workbook is written out on Close
+type xlsxRowWriter struct {
+	w      io.Writer
+	file   *excelize.File
+	stream *excelize.StreamWriter
+	rows   int
+}
+
+func newXLSXRowWriter(w io.Writer, sheet string) (*xlsxRowWriter, error) {
+	file := excelize.NewFile()
+
+	if err := file.SetSheetName(file.GetSheetName(0), sheet); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to name sheet: %w", err)
+	}
+
+	stream, err := file.NewStreamWriter(sheet)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to create sheet writer: %w", err)
+	}
+
+	return &xlsxRowWriter{w: w, file: file, stream: stream}, nil
+}
+
+func (w *xlsxRowWriter) WriteRow(row []any) error {
+	w.rows++
+
+	cell, err := excelize.CoordinatesToCellName(1, w.rows)
+	if err != nil {
+		return err
+	}
+
+	return w.stream.SetRow(cell, row)
+}
+
+func (w *xlsxRowWriter) Close() error {
+	defer w.file.Close()
+
+	if err := w.stream.Flush(); err != nil {
+		return err
+	}
+
+	return w.file.Write(w.w)
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/xuri/excelize/v2"
+	"gorm.io/datatypes"
+)
+
+// spreadsheetOrderRepository returns its orders in batches, with the detail keys the test gives it
+type spreadsheetOrderRepository struct {
+	*stubOrderRepository
+	detailKeys []string
+}
+
+func (r *spreadsheetOrderRepository) GetDetailKeys(*model.OrderFilter) ([]string, error) {
+	return r.detailKeys, nil
+}
+
+func (r *spreadsheetOrderRepository) GetAllInBatches(_ *model.OrderFilter, batchSize int, fn func([]*model.Order) error) error {
+	for start := 0; start < len(r.orders); start += batchSize {
+		if err := fn(r.orders[start:min(start+batchSize, len(r.orders))]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func newSpreadsheetOrderRepository() *spreadsheetOrderRepository {
+	createdAt := time.Date(2026, time.March, 2, 10, 0, 0, 0, time.UTC)
+
+	return &spreadsheetOrderRepository{
+		stubOrderRepository: &stubOrderRepository{orders: []*model.Order{
+			{
+				ID: "order-1", OrderName: "BP-001", CreatedAt: createdAt, Status: model.OrderStatusQuoted,
+				User:        model.User{Name: "Ada", Email: "ada@example.com"},
+				ProductName: "=HYPERLINK(\"x\")", Quantity: 2, QuotedAmount: 150000,
+				Shots:   pq.StringArray{"Front", "Back"},
+				Details: datatypes.JSON(`{"size": "large", "weight": 1.5, "colors": ["red"]}`),
+			},
+			{
+				ID: "order-2", OrderName: "BP-002", CreatedAt: createdAt, Status: model.OrderStatusShooting,
+				User:    model.User{Name: "Bola", Email: "bola@example.com"},
+				Details: datatypes.JSON(`{"size": "small"}`),
+			},
+		}},
+		detailKeys: []string{"colors", "size", "weight"},
+	}
+}
+
+func TestExportOrders(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Should flatten the details and shots of every order into a CSV row", func(t *testing.T) {
+		t.Parallel()
+
+		repo := newSpreadsheetOrderRepository()
+		s := &exportService{orderRepo: repo}
+
+		spreadsheet, err := s.ExportOrders(model.SpreadsheetFormatCSV, &model.OrderFilterRequest{})
+		assert.NoError(t, err)
+		assert.Equal(t, "text/csv; charset=utf-8", spreadsheet.ContentType)
+
+		var buf bytes.Buffer
+		assert.NoError(t, spreadsheet.Write(&buf))
+
+		records, err := csv.NewReader(&buf).ReadAll()
+		assert.NoError(t, err)
+		assert.Len(t, records, 3)
+
+		header := records[0]
+		column := func(row []string, name string) string {
+			for i, h := range header {
+				if h == name {
+					return row[i]
+				}
+			}
+
+			t.Fatalf("missing column %s", name)
+
+			return ""
+		}
+
+		assert.Equal(t, "Front\nBack", column(records[1], "shots"))
+		assert.Equal(t, "'=HYPERLINK(\"x\")", column(records[1], "product_name"))
+		assert.Equal(t, "ada@example.com", column(records[1], "customer_email"))
+		assert.Equal(t, "150000", column(records[1], "quoted_amount"))
+		assert.Equal(t, "large", column(records[1], "details.size"))
+		assert.Equal(t, "1.5", column(records[1], "details.weight"))
+		assert.Equal(t, `["red"]`, column(records[1], "details.colors"))
+		assert.Equal(t, "small", column(records[2], "details.size"))
+		assert.Empty(t, column(records[2], "details.weight"))
+	})
+
+	t.Run("Should write the same rows to an XLSX workbook", func(t *testing.T) {
+		t.Parallel()
+
+		s := &exportService{orderRepo: newSpreadsheetOrderRepository()}
+
+		spreadsheet, err := s.ExportOrders(model.SpreadsheetFormatXLSX, &model.OrderFilterRequest{})
+		assert.NoError(t, err)
+
+		var buf bytes.Buffer
+		assert.NoError(t, spreadsheet.Write(&buf))
+
+		file, err := excelize.OpenReader(&buf)
+		assert.NoError(t, err)
+
+		defer file.Close()
+
+		rows, err := file.GetRows("orders")
+		assert.NoError(t, err)
+		assert.Len(t, rows, 3)
+		assert.Equal(t, "BP-002", rows[2][1])
+	})
+
+	t.Run("Should refuse unknown formats and filters before the download starts", func(t *testing.T) {
+		t.Parallel()
+
+		s := &exportService{orderRepo: newSpreadsheetOrderRepository()}
+
+		_, err := s.ExportOrders("pdf", &model.OrderFilterRequest{})
+		assert.ErrorIs(t, err, ErrUnknownExportFormat)
+
+		_, err = s.ExportOrders(model.SpreadsheetFormatCSV, &model.OrderFilterRequest{Sort: "password"})
+		assert.ErrorIs(t, err, ErrInvalidOrderFilter)
+	})
+}
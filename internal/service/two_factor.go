@@ -0,0 +1,265 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/MogboPython/belvaphilips_backend/pkg/utils"
+)
+
+var (
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrTwoFactorNotEnabled     = errors.New("two-factor authentication is not enabled")
+	ErrTwoFactorNotStarted     = errors.New("start two-factor setup before enabling it")
+	ErrInvalidTwoFactorCode    = errors.New("invalid two-factor code")
+	ErrTwoFactorLocked         = errors.New("too many wrong two-factor codes, try again later")
+	ErrInvalidChallenge        = errors.New("invalid or expired sign in challenge")
+)
+
+const (
+	totpIssuer           = "BelvaPhilips Imagery"
+	recoveryCodeCount    = 10
+	maxTwoFactorAttempts = 5
+	twoFactorLockout     = 15 * time.Minute
+)
+
+// VerifyTwoFactorLogin finishes a sign in that was started with the password, using a TOTP code
+// or one of the recovery codes
+func (s *adminService) VerifyTwoFactorLogin(req *model.AdminTwoFactorLoginRequest) (*model.AdminLoginResponse, error) {
+	adminID, err := utils.ParseChallengeToken(req.ChallengeToken)
+	if err != nil {
+		return nil, ErrInvalidChallenge
+	}
+
+	admin, err := s.adminRepo.GetByID(adminID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find admin: %w", err)
+	}
+
+	if !admin.Active {
+		return nil, ErrAdminDisabled
+	}
+
+	if !admin.TOTPEnabled {
+		return nil, ErrInvalidChallenge
+	}
+
+	if err := s.checkTwoFactorCode(admin, req.Code); err != nil {
+		return nil, err
+	}
+
+	return s.startAdminSession(admin)
+}
+
+// SetupTwoFactor generates a TOTP secret for the signed in admin. Sign in is not protected by it
+// until EnableTwoFactor confirms the authenticator app works
+func (s *adminService) SetupTwoFactor(actor *model.Actor) (*model.TwoFactorSetupResponse, error) {
+	admin, err := s.adminRepo.GetByID(actor.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find admin: %w", err)
+	}
+
+	if admin.TOTPEnabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	if admin.TOTPSecret, err = utils.GenerateTOTPSecret(); err != nil {
+		return nil, fmt.Errorf("failed to generate secret: %w", err)
+	}
+
+	if err := s.adminRepo.Update(admin, nil, "totp_secret"); err != nil {
+		return nil, fmt.Errorf("failed to update admin: %w", err)
+	}
+
+	return &model.TwoFactorSetupResponse{
+		Secret:          admin.TOTPSecret,
+		ProvisioningURI: utils.TOTPProvisioningURI(totpIssuer, admin.Email, admin.TOTPSecret),
+	}, nil
+}
+
+// EnableTwoFactor turns on two-factor sign in once the admin proves the authenticator app
+// produces valid codes, and returns the first set of recovery codes
+func (s *adminService) EnableTwoFactor(actor *model.Actor, req *model.TwoFactorCodeRequest) (*model.RecoveryCodesResponse, error) {
+	admin, err := s.adminRepo.GetByID(actor.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find admin: %w", err)
+	}
+
+	if admin.TOTPEnabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	if admin.TOTPSecret == "" {
+		return nil, ErrTwoFactorNotStarted
+	}
+
+	step, ok := utils.ValidateTOTP(admin.TOTPSecret, req.Code, time.Now())
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	admin.TOTPEnabled = true
+	admin.TOTPLastStep = step
+
+	if err := s.adminRepo.Update(admin, nil, "totp_enabled", "totp_last_step"); err != nil {
+		return nil, fmt.Errorf("failed to update admin: %w", err)
+	}
+
+	return s.newRecoveryCodes(admin.ID)
+}
+
+// DisableTwoFactor turns off two-factor sign in for the signed in admin, who has to confirm with
+// both the password and a code. Their other sessions are revoked
+func (s *adminService) DisableTwoFactor(actor *model.Actor, req *model.TwoFactorDisableRequest) error {
+	admin, err := s.adminRepo.GetByID(actor.ID)
+	if err != nil {
+		return fmt.Errorf("failed to find admin: %w", err)
+	}
+
+	if !admin.TOTPEnabled {
+		return ErrTwoFactorNotEnabled
+	}
+
+	if !utils.CheckPasswordHash(req.Password, admin.PasswordHash) {
+		return ErrIncorrectCredentials
+	}
+
+	if err := s.checkTwoFactorCode(admin, req.Code); err != nil {
+		return err
+	}
+
+	if err := s.clearTwoFactor(admin); err != nil {
+		return err
+	}
+
+	if err := s.sessionRepo.RevokeOtherSessions(admin.ID, actor.TokenID); err != nil {
+		return fmt.Errorf("failed to revoke admin sessions: %w", err)
+	}
+
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes of the signed in admin
+func (s *adminService) RegenerateRecoveryCodes(actor *model.Actor, req *model.TwoFactorCodeRequest) (*model.RecoveryCodesResponse, error) {
+	admin, err := s.adminRepo.GetByID(actor.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find admin: %w", err)
+	}
+
+	if !admin.TOTPEnabled {
+		return nil, ErrTwoFactorNotEnabled
+	}
+
+	if err := s.checkTwoFactorCode(admin, req.Code); err != nil {
+		return nil, err
+	}
+
+	return s.newRecoveryCodes(admin.ID)
+}
+
+// ResetTwoFactor turns off two-factor sign in for a staff member who lost their authenticator
+// and their recovery codes. Their sessions are revoked so they sign in again without a code
+func (s *adminService) ResetTwoFactor(id string) (*model.AdminResponse, error) {
+	admin, err := s.adminRepo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find admin: %w", err)
+	}
+
+	if err := s.clearTwoFactor(admin); err != nil {
+		return nil, err
+	}
+
+	if err := s.sessionRepo.RevokeSubject(admin.ID); err != nil {
+		return nil, fmt.Errorf("failed to revoke admin sessions: %w", err)
+	}
+
+	return mapAdminToResponse(admin), nil
+}
+
+// checkTwoFactorCode accepts an unused TOTP code or recovery code. Wrong codes count towards a
+// temporary lockout so the six digits cannot be guessed
+func (s *adminService) checkTwoFactorCode(admin *model.Admin, code string) error {
+	now := time.Now()
+
+	if admin.TOTPLockedUntil != nil && now.Before(*admin.TOTPLockedUntil) {
+		return ErrTwoFactorLocked
+	}
+
+	if step, ok := utils.ValidateTOTP(admin.TOTPSecret, code, now); ok {
+		err := s.adminRepo.ConsumeTOTPStep(admin.ID, step)
+		if err == nil {
+			admin.TOTPLastStep = step
+			admin.TOTPFailedAttempts = 0
+			admin.TOTPLockedUntil = nil
+
+			return nil
+		}
+
+		if !strings.Contains(err.Error(), "two-factor code was already used") {
+			return fmt.Errorf("failed to record two-factor code: %w", err)
+		}
+	} else {
+		err := s.adminRepo.UseRecoveryCode(admin.ID, utils.HashToken(utils.NormalizeRecoveryCode(code)))
+		if err == nil {
+			admin.TOTPFailedAttempts = 0
+			admin.TOTPLockedUntil = nil
+
+			return nil
+		}
+
+		if !strings.Contains(err.Error(), "recovery code not found") {
+			return fmt.Errorf("failed to use recovery code: %w", err)
+		}
+	}
+
+	if err := s.adminRepo.RecordTOTPFailure(admin.ID, maxTwoFactorAttempts, now.Add(twoFactorLockout)); err != nil {
+		return fmt.Errorf("failed to record two-factor failure: %w", err)
+	}
+
+	return ErrInvalidTwoFactorCode
+}
+
+// newRecoveryCodes replaces the recovery codes of an admin and returns them in plain text. Only
+// their hashes are stored
+func (s *adminService) newRecoveryCodes(adminID string) (*model.RecoveryCodesResponse, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+
+	for i := range codes {
+		code, err := utils.GenerateRecoveryCode()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+
+		codes[i] = code
+		hashes[i] = utils.HashToken(utils.NormalizeRecoveryCode(code))
+	}
+
+	if err := s.adminRepo.SetRecoveryCodes(adminID, hashes); err != nil {
+		return nil, fmt.Errorf("failed to save recovery codes: %w", err)
+	}
+
+	return &model.RecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+func (s *adminService) clearTwoFactor(admin *model.Admin) error {
+	admin.TOTPEnabled = false
+	admin.TOTPSecret = ""
+	admin.TOTPLastStep = 0
+	admin.TOTPFailedAttempts = 0
+	admin.TOTPLockedUntil = nil
+
+	if err := s.adminRepo.Update(admin, nil,
+		"totp_enabled", "totp_secret", "totp_last_step", "totp_failed_attempts", "totp_locked_until"); err != nil {
+		return fmt.Errorf("failed to update admin: %w", err)
+	}
+
+	if err := s.adminRepo.SetRecoveryCodes(admin.ID, nil); err != nil {
+		return fmt.Errorf("failed to remove recovery codes: %w", err)
+	}
+
+	return nil
+}
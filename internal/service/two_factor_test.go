@@ -0,0 +1,198 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/MogboPython/belvaphilips_backend/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTwoFactorAdmin returns an admin service whose only admin has two-factor sign in enabled
+func newTwoFactorAdmin(t *testing.T) (*adminService, *stubAdminRepository, *model.Admin) {
+	t.Helper()
+
+	secret, err := utils.GenerateTOTPSecret()
+	assert.NoError(t, err)
+
+	admin := newTestAdmin(t, "correct horse battery", true)
+	admin.TOTPSecret = secret
+	admin.TOTPEnabled = true
+
+	repo := newStubAdminRepository(t, admin)
+
+	return &adminService{adminRepo: repo, sessionRepo: newStubSessionRepository(t)}, repo, admin
+}
+
+func currentTOTPCode(t *testing.T, secret string) string {
+	t.Helper()
+
+	code, err := utils.TOTPCode(secret, time.Now())
+	assert.NoError(t, err)
+
+	return code
+}
+
+func TestTwoFactorLogin(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Should ask for a code before issuing tokens", func(t *testing.T) {
+		t.Parallel()
+
+		s, _, admin := newTwoFactorAdmin(t)
+
+		login, err := s.Login(&model.AdminLoginRequest{Username: "studio", Password: "correct horse battery"})
+		assert.NoError(t, err)
+		assert.True(t, login.TwoFactorRequired)
+		assert.Nil(t, login.TokenResponse)
+		assert.Nil(t, admin.LastLoginAt)
+
+		login, err = s.VerifyTwoFactorLogin(&model.AdminTwoFactorLoginRequest{
+			ChallengeToken: login.ChallengeToken,
+			Code:           currentTOTPCode(t, admin.TOTPSecret),
+		})
+		assert.NoError(t, err)
+		assert.NotEmpty(t, login.AccessToken)
+		assert.NotNil(t, admin.LastLoginAt)
+	})
+
+	t.Run("Should not accept the same code twice", func(t *testing.T) {
+		t.Parallel()
+
+		s, _, admin := newTwoFactorAdmin(t)
+		code := currentTOTPCode(t, admin.TOTPSecret)
+
+		challenge, err := utils.GenerateChallengeToken(admin.ID)
+		assert.NoError(t, err)
+
+		_, err = s.VerifyTwoFactorLogin(&model.AdminTwoFactorLoginRequest{ChallengeToken: challenge, Code: code})
+		assert.NoError(t, err)
+
+		_, err = s.VerifyTwoFactorLogin(&model.AdminTwoFactorLoginRequest{ChallengeToken: challenge, Code: code})
+		assert.ErrorIs(t, err, ErrInvalidTwoFactorCode)
+	})
+
+	t.Run("Should accept each recovery code once", func(t *testing.T) {
+		t.Parallel()
+
+		s, _, admin := newTwoFactorAdmin(t)
+
+		codes, err := s.newRecoveryCodes(admin.ID)
+		assert.NoError(t, err)
+		assert.Len(t, codes.RecoveryCodes, recoveryCodeCount)
+
+		challenge, err := utils.GenerateChallengeToken(admin.ID)
+		assert.NoError(t, err)
+
+		_, err = s.VerifyTwoFactorLogin(&model.AdminTwoFactorLoginRequest{ChallengeToken: challenge, Code: codes.RecoveryCodes[0]})
+		assert.NoError(t, err)
+
+		_, err = s.VerifyTwoFactorLogin(&model.AdminTwoFactorLoginRequest{ChallengeToken: challenge, Code: codes.RecoveryCodes[0]})
+		assert.ErrorIs(t, err, ErrInvalidTwoFactorCode)
+	})
+
+	t.Run("Should lock two-factor sign in after repeated wrong codes", func(t *testing.T) {
+		t.Parallel()
+
+		s, _, admin := newTwoFactorAdmin(t)
+
+		challenge, err := utils.GenerateChallengeToken(admin.ID)
+		assert.NoError(t, err)
+
+		for range maxTwoFactorAttempts {
+			_, err = s.VerifyTwoFactorLogin(&model.AdminTwoFactorLoginRequest{ChallengeToken: challenge, Code: "not-a-code"})
+			assert.ErrorIs(t, err, ErrInvalidTwoFactorCode)
+		}
+
+		_, err = s.VerifyTwoFactorLogin(&model.AdminTwoFactorLoginRequest{
+			ChallengeToken: challenge,
+			Code:           currentTOTPCode(t, admin.TOTPSecret),
+		})
+		assert.ErrorIs(t, err, ErrTwoFactorLocked)
+	})
+
+	t.Run("Should reject access tokens used as challenges", func(t *testing.T) {
+		t.Parallel()
+
+		s, _, admin := newTwoFactorAdmin(t)
+
+		token, err := utils.GenerateToken(admin.ID, model.RoleAdmin, "jti-1")
+		assert.NoError(t, err)
+
+		_, err = s.VerifyTwoFactorLogin(&model.AdminTwoFactorLoginRequest{
+			ChallengeToken: token,
+			Code:           currentTOTPCode(t, admin.TOTPSecret),
+		})
+		assert.ErrorIs(t, err, ErrInvalidChallenge)
+	})
+}
+
+func TestTwoFactorEnrollment(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Should enable two-factor sign in once a code confirms the secret", func(t *testing.T) {
+		t.Parallel()
+
+		admin := newTestAdmin(t, "correct horse battery", true)
+		repo := newStubAdminRepository(t, admin)
+		s := &adminService{adminRepo: repo, sessionRepo: newStubSessionRepository(t)}
+		actor := &model.Actor{ID: admin.ID, Role: model.RoleAdmin}
+
+		_, err := s.EnableTwoFactor(actor, &model.TwoFactorCodeRequest{Code: "123456"})
+		assert.ErrorIs(t, err, ErrTwoFactorNotStarted)
+
+		setup, err := s.SetupTwoFactor(actor)
+		assert.NoError(t, err)
+		assert.Contains(t, setup.ProvisioningURI, "secret="+setup.Secret)
+		assert.False(t, admin.TOTPEnabled)
+
+		codes, err := s.EnableTwoFactor(actor, &model.TwoFactorCodeRequest{Code: currentTOTPCode(t, setup.Secret)})
+		assert.NoError(t, err)
+		assert.True(t, admin.TOTPEnabled)
+		assert.Len(t, repo.recoveryCodes[admin.ID], recoveryCodeCount)
+		assert.NotContains(t, repo.recoveryCodes[admin.ID], codes.RecoveryCodes[0])
+
+		_, err = s.SetupTwoFactor(actor)
+		assert.ErrorIs(t, err, ErrTwoFactorAlreadyEnabled)
+	})
+
+	t.Run("Should require the password and a code to disable two-factor sign in", func(t *testing.T) {
+		t.Parallel()
+
+		s, repo, admin := newTwoFactorAdmin(t)
+		actor := &model.Actor{ID: admin.ID, Role: model.RoleAdmin, TokenID: "jti-1"}
+
+		codes, err := s.newRecoveryCodes(admin.ID)
+		assert.NoError(t, err)
+
+		err = s.DisableTwoFactor(actor, &model.TwoFactorDisableRequest{Password: "wrong", Code: codes.RecoveryCodes[0]})
+		assert.ErrorIs(t, err, ErrIncorrectCredentials)
+
+		err = s.DisableTwoFactor(actor, &model.TwoFactorDisableRequest{Password: "correct horse battery", Code: codes.RecoveryCodes[0]})
+		assert.NoError(t, err)
+		assert.False(t, admin.TOTPEnabled)
+		assert.Empty(t, admin.TOTPSecret)
+		assert.Empty(t, repo.recoveryCodes[admin.ID])
+
+		sessions := s.sessionRepo.(*stubSessionRepository)
+		assert.Equal(t, []string{admin.ID}, sessions.revokedSubjects)
+		assert.Equal(t, []string{"jti-1"}, sessions.keptTokenIDs)
+	})
+
+	t.Run("Should sign a staff member out everywhere when an admin resets two-factor sign in", func(t *testing.T) {
+		t.Parallel()
+
+		s, repo, admin := newTwoFactorAdmin(t)
+
+		_, err := s.ResetTwoFactor(admin.ID)
+
+		assert.NoError(t, err)
+		assert.False(t, admin.TOTPEnabled)
+		assert.Empty(t, repo.recoveryCodes[admin.ID])
+
+		sessions := s.sessionRepo.(*stubSessionRepository)
+		assert.Equal(t, []string{admin.ID}, sessions.revokedSubjects)
+		assert.Empty(t, sessions.keptTokenIDs)
+	})
+}
@@ -1,33 +1,74 @@
 package service
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 
+	"github.com/MogboPython/belvaphilips_backend/internal/auth"
 	"github.com/MogboPython/belvaphilips_backend/internal/repository"
 	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"gorm.io/gorm"
 
 	"github.com/gofiber/fiber/v2/log"
 )
 
+var (
+	ErrUserDeleted      = errors.New("this account has been deleted")
+	ErrUserNameRequired = errors.New("name cannot be empty")
+
+	ErrPhoneNumberRequired = errors.New("add a phone number before turning on SMS or WhatsApp notifications")
+)
+
 // UserService interface defines methods for user business logic
 type UserService interface {
+	CreateAccessToken(upstreamToken string) (*model.TokenResponse, error)
 	CreateUser(req *model.CreateUserRequest) (*model.UserResponse, error)
 	GetUserByID(id string) (*model.UserResponse, error)
-	UpdateUserMembershipStatusChange(userID string, request *model.MembershipStatusChangeRequest) (*model.UserResponse, error)
-	// UpdateUser(id int64, req *model.UpdateUserRequest) (*model.UserResponse, error)
-	// DeleteUser(id int64) error
+	UpdateUser(id string, req *model.UpdateUserRequest) (*model.UserResponse, error)
+	DeleteUser(id string) error
+	GetNotificationPreferences(id string) (*model.NotificationPreference, error)
+	UpdateNotificationPreferences(id string, req *model.NotificationPreferenceRequest) (*model.NotificationPreference, error)
+	IsDeleted(id string) (bool, error)
 }
 
 // userService implements UserService interface
 type userService struct {
-	userRepo repository.UserRepository
+	userRepo       repository.UserRepository
+	sessionRepo    repository.SessionRepository
+	preferenceRepo repository.NotificationPreferenceRepository
+	verifier       *auth.Verifier
 }
 
 // NewUserService creates a new user service
-func NewUserService(userRepo repository.UserRepository) UserService {
+func NewUserService(userRepo repository.UserRepository, sessionRepo repository.SessionRepository, preferenceRepo repository.NotificationPreferenceRepository, verifier *auth.Verifier) UserService {
 	return &userService{
-		userRepo: userRepo,
+		userRepo:       userRepo,
+		sessionRepo:    sessionRepo,
+		preferenceRepo: preferenceRepo,
+		verifier:       verifier,
+	}
+}
+
+// CreateAccessToken exchanges an access token issued by the identity provider for a session of
+// the user in its subject
+func (s *userService) CreateAccessToken(upstreamToken string) (*model.TokenResponse, error) {
+	userID, err := s.verifier.Verify(upstreamToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// Users sign in before they register, so only an existing deleted account is refused
+	user, err := s.userRepo.GetByID(userID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to find user: %w", err)
 	}
+
+	if user != nil && user.DeletedAt != nil {
+		return nil, ErrUserDeleted
+	}
+
+	return issueSession(s.sessionRepo, userID, model.RoleAuthenticated)
 }
 
 // CreateUser creates a new user
@@ -55,50 +96,150 @@ func (s *userService) GetUserByID(id string) (*model.UserResponse, error) {
 		return nil, fmt.Errorf("failed to find user: %w", err)
 	}
 
-	return mapUserToResponse(user), nil
+	preference, err := s.preferenceRepo.Get(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find notification preferences: %w", err)
+	}
+
+	response := mapUserToResponse(user)
+	response.NotificationPreferences = preference
+
+	return response, nil
 }
 
-func (s *userService) UpdateUserMembershipStatusChange(userID string, request *model.MembershipStatusChangeRequest) (*model.UserResponse, error) {
-	user, err := s.userRepo.UpdateMembership(userID, request.Status)
+// UpdateUser changes the profile fields sent in the request
+func (s *userService) UpdateUser(id string, req *model.UpdateUserRequest) (*model.UserResponse, error) {
+	user, err := s.userRepo.GetByID(id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update order: %w", err)
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	if user.DeletedAt != nil {
+		return nil, ErrUserDeleted
+	}
+
+	if req.Name != nil {
+		user.Name = strings.TrimSpace(*req.Name)
+	}
+
+	if req.CompanyName != nil {
+		user.CompanyName = strings.TrimSpace(*req.CompanyName)
+	}
+
+	if req.Phone != nil {
+		user.PhoneNumber = strings.TrimSpace(*req.Phone)
+	}
+
+	if user.Name == "" {
+		return nil, ErrUserNameRequired
+	}
+
+	if err := s.userRepo.Update(user); err != nil {
+		return nil, fmt.Errorf("failed to update user: %w", err)
 	}
 
 	return mapUserToResponse(user), nil
 }
 
-// UpdateUser updates an existing user
-// func (s *userService) UpdateUser(id int64, req *model.UpdateUserRequest) (*model.UserResponse, error) {
-// 	if req == nil {
-// 		return nil, errors.New("invalid request")
-// 	}
-
-// 	existingUser, err := s.userRepo.GetByID(id)
-// 	if err != nil {
-// 		return nil, err
-// 	}
-
-// 	if req.Username != "" {
-// 		existingUser.Username = req.Username
-// 	}
-// 	if req.Email != "" {
-// 		existingUser.Email = req.Email
-// 	}
-// 	if req.Phone != "" {
-// 		existingUser.Phone = req.Phone
-// 	}
-
-// 	if err := s.userRepo.Update(id, existingUser); err != nil {
-// 		return nil, err
-// 	}
-
-// 	return mapUserToResponse(existingUser), nil
-// }
-
-// DeleteUser deletes a user by ID
-// func (s *userService) DeleteUser(id int64) error {
-// 	return s.userRepo.Delete(id)
-// }
+// DeleteUser anonymizes the account of a user. Orders and invoices are kept for accounting but
+// no longer carry the name, email or phone number of the customer
+func (s *userService) DeleteUser(id string) error {
+	user, err := s.userRepo.GetByID(id)
+	if err != nil {
+		return fmt.Errorf("failed to find user: %w", err)
+	}
+
+	if user.DeletedAt != nil {
+		return ErrUserDeleted
+	}
+
+	if err := s.userRepo.Anonymize(user); err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+
+	log.Infof("Anonymized account of user %s", user.ID)
+
+	return nil
+}
+
+// IsDeleted reports whether the account of a user was deleted, for middleware.Protected
+func (s *userService) IsDeleted(id string) (bool, error) {
+	return s.userRepo.IsDeleted(id)
+}
+
+func (s *userService) GetNotificationPreferences(id string) (*model.NotificationPreference, error) {
+	if _, err := s.userRepo.GetByID(id); err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	preference, err := s.preferenceRepo.Get(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find notification preferences: %w", err)
+	}
+
+	return preference, nil
+}
+
+// UpdateNotificationPreferences switches the channels and categories sent in the request on or off
+func (s *userService) UpdateNotificationPreferences(id string, req *model.NotificationPreferenceRequest) (*model.NotificationPreference, error) {
+	user, err := s.userRepo.GetByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find user: %w", err)
+	}
+
+	if user.DeletedAt != nil {
+		return nil, ErrUserDeleted
+	}
+
+	preference, err := s.preferenceRepo.Get(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find notification preferences: %w", err)
+	}
+
+	if req.Email != nil {
+		preference.Email = *req.Email
+	}
+
+	if req.SMS != nil {
+		preference.SMS = *req.SMS
+	}
+
+	if req.WhatsApp != nil {
+		preference.WhatsApp = *req.WhatsApp
+	}
+
+	if req.OrderUpdates != nil {
+		preference.OrderUpdates = *req.OrderUpdates
+	}
+
+	if req.Marketing != nil {
+		preference.Marketing = *req.Marketing
+	}
+
+	if req.Blog != nil {
+		preference.Blog = *req.Blog
+	}
+
+	if (preference.SMS || preference.WhatsApp) && strings.TrimSpace(user.PhoneNumber) == "" {
+		return nil, ErrPhoneNumberRequired
+	}
+
+	if err := s.preferenceRepo.Save(preference); err != nil {
+		return nil, fmt.Errorf("failed to update notification preferences: %w", err)
+	}
+
+	return preference, nil
+}
+
+// notificationAllowed reports whether a user wants notifications of category over channel
+func notificationAllowed(preferenceRepo repository.NotificationPreferenceRepository, userID, channel, category string) (bool, error) {
+	preference, err := preferenceRepo.Get(userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to find notification preferences of user %s: %w", userID, err)
+	}
+
+	return preference.Allows(channel, category), nil
+}
 
 func mapUserToResponse(user *model.User) *model.UserResponse {
 	return &model.UserResponse{
@@ -109,5 +250,6 @@ func mapUserToResponse(user *model.User) *model.UserResponse {
 		CompanyName: user.CompanyName,
 		CreatedAt:   user.CreatedAt,
 		UpdatedAt:   user.UpdatedAt,
+		DeletedAt:   user.DeletedAt,
 	}
 }
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/MogboPython/belvaphilips_backend/internal/repository"
+	"github.com/MogboPython/belvaphilips_backend/pkg/model"
+	"github.com/MogboPython/belvaphilips_backend/pkg/validator"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+type stubUserRepository struct {
+	repository.UserRepository
+	users      map[string]*model.User
+	anonymized []string
+}
+
+func newStubUserRepository(t *testing.T, users ...*model.User) *stubUserRepository {
+	t.Helper()
+
+	repo := &stubUserRepository{users: map[string]*model.User{}}
+	for _, user := range users {
+		repo.users[user.ID] = user
+	}
+
+	return repo
+}
+
+func (r *stubUserRepository) GetByID(id string) (*model.User, error) {
+	if user, ok := r.users[id]; ok {
+		return user, nil
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (*stubUserRepository) Update(*model.User) error {
+	return nil
+}
+
+func (r *stubUserRepository) Anonymize(user *model.User) error {
+	now := time.Now()
+	user.DeletedAt = &now
+	r.anonymized = append(r.anonymized, user.ID)
+
+	return nil
+}
+
+func newTestUser() *model.User {
+	return &model.User{
+		ID:          "user-1",
+		Name:        "Ada",
+		Email:       "ada@example.com",
+		CompanyName: "Ada Studio",
+		PhoneNumber: "+2348012345678",
+	}
+}
+
+func TestUpdateUser(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Should only change the fields that are sent", func(t *testing.T) {
+		t.Parallel()
+
+		s := &userService{userRepo: newStubUserRepository(t, newTestUser())}
+		phone := " +2348098765432 "
+
+		user, err := s.UpdateUser("user-1", &model.UpdateUserRequest{Phone: &phone})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "+2348098765432", user.Phone)
+		assert.Equal(t, "Ada", user.Name)
+		assert.Equal(t, "Ada Studio", user.CompanyName)
+	})
+
+	t.Run("Should clear the phone number when it is sent empty", func(t *testing.T) {
+		t.Parallel()
+
+		s := &userService{userRepo: newStubUserRepository(t, newTestUser())}
+		phone := ""
+		req := &model.UpdateUserRequest{Phone: &phone}
+
+		assert.NoError(t, validator.New().Validate(req))
+
+		user, err := s.UpdateUser("user-1", req)
+
+		assert.NoError(t, err)
+		assert.Empty(t, user.Phone)
+	})
+
+	t.Run("Should not blank out the name", func(t *testing.T) {
+		t.Parallel()
+
+		s := &userService{userRepo: newStubUserRepository(t, newTestUser())}
+		name := "   "
+
+		_, err := s.UpdateUser("user-1", &model.UpdateUserRequest{Name: &name})
+
+		assert.ErrorIs(t, err, ErrUserNameRequired)
+	})
+
+	t.Run("Should not update deleted accounts", func(t *testing.T) {
+		t.Parallel()
+
+		user := newTestUser()
+		now := time.Now()
+		user.DeletedAt = &now
+		s := &userService{userRepo: newStubUserRepository(t, user)}
+		name := "Ada Lovelace"
+
+		_, err := s.UpdateUser("user-1", &model.UpdateUserRequest{Name: &name})
+
+		assert.ErrorIs(t, err, ErrUserDeleted)
+	})
+}
+
+func TestDeleteUser(t *testing.T) {
+	t.Parallel()
+
+	repo := newStubUserRepository(t, newTestUser())
+	s := &userService{userRepo: repo}
+
+	assert.NoError(t, s.DeleteUser("user-1"))
+	assert.Equal(t, []string{"user-1"}, repo.anonymized)
+
+	assert.ErrorIs(t, s.DeleteUser("user-1"), ErrUserDeleted)
+	assert.ErrorIs(t, s.DeleteUser("user-2"), gorm.ErrRecordNotFound)
+}
+
+type stubPreferenceRepository struct {
+	repository.NotificationPreferenceRepository
+	preferences map[string]*model.NotificationPreference
+}
+
+func (r *stubPreferenceRepository) Get(userID string) (*model.NotificationPreference, error) {
+	if preference, ok := r.preferences[userID]; ok {
+		copied := *preference
+		return &copied, nil
+	}
+
+	return model.DefaultNotificationPreference(userID), nil
+}
+
+func (r *stubPreferenceRepository) Save(preference *model.NotificationPreference) error {
+	r.preferences[preference.UserID] = preference
+
+	return nil
+}
+
+func TestUpdateNotificationPreferences(t *testing.T) {
+	t.Parallel()
+
+	on, off := true, false
+
+	t.Run("Should only switch the preferences that are sent", func(t *testing.T) {
+		t.Parallel()
+
+		preferences := &stubPreferenceRepository{preferences: map[string]*model.NotificationPreference{}}
+		s := &userService{userRepo: newStubUserRepository(t, newTestUser()), preferenceRepo: preferences}
+
+		preference, err := s.UpdateNotificationPreferences("user-1", &model.NotificationPreferenceRequest{WhatsApp: &on, Marketing: &on})
+
+		assert.NoError(t, err)
+		assert.True(t, preference.Email)
+		assert.True(t, preference.WhatsApp)
+		assert.True(t, preference.OrderUpdates)
+		assert.True(t, preference.Marketing)
+		assert.False(t, preference.SMS)
+		assert.False(t, preference.Blog)
+		assert.Equal(t, preference, preferences.preferences["user-1"])
+	})
+
+	t.Run("Should need a phone number for SMS and WhatsApp", func(t *testing.T) {
+		t.Parallel()
+
+		user := newTestUser()
+		user.PhoneNumber = ""
+		preferences := &stubPreferenceRepository{preferences: map[string]*model.NotificationPreference{}}
+		s := &userService{userRepo: newStubUserRepository(t, user), preferenceRepo: preferences}
+
+		_, err := s.UpdateNotificationPreferences("user-1", &model.NotificationPreferenceRequest{SMS: &on})
+		assert.ErrorIs(t, err, ErrPhoneNumberRequired)
+		assert.Empty(t, preferences.preferences)
+
+		preference, err := s.UpdateNotificationPreferences("user-1", &model.NotificationPreferenceRequest{Email: &off})
+		assert.NoError(t, err)
+		assert.False(t, preference.Email)
+	})
+}
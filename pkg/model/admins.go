@@ -0,0 +1,59 @@
+package model
+
+import "time"
+
+// Admin is a staff account that signs in to the admin dashboard
+type Admin struct {
+	CreatedAt          time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt          time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+	LastLoginAt        *time.Time `json:"last_login_at"`
+	ID                 string     `gorm:"default:uuid_generate_v4()" json:"id"`
+	Username           string     `gorm:"unique;not null" json:"username"`
+	Email              string     `gorm:"unique;not null" json:"email"`
+	Name               string     `gorm:"not null" json:"name"`
+	PasswordHash       string     `gorm:"not null" json:"-"`
+	Active             bool       `gorm:"not null;default:true" json:"active"`
+	MustChangePassword bool       `gorm:"not null;default:false" json:"must_change_password"`
+	// TOTPSecret is set when enrollment starts and only protects sign in once TOTPEnabled is true
+	TOTPSecret         string     `gorm:"column:totp_secret;not null;default:''" json:"-"`
+	TOTPEnabled        bool       `gorm:"column:totp_enabled;not null;default:false" json:"totp_enabled"`
+	TOTPLastStep       int64      `gorm:"column:totp_last_step;not null;default:0" json:"-"`
+	TOTPFailedAttempts int        `gorm:"column:totp_failed_attempts;not null;default:0" json:"-"`
+	TOTPLockedUntil    *time.Time `gorm:"column:totp_locked_until" json:"-"`
+}
+
+// AdminRecoveryCode is a hashed one-time code that replaces a TOTP code when the authenticator is lost
+type AdminRecoveryCode struct {
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	ID        string     `gorm:"default:uuid_generate_v4()" json:"id"`
+	AdminID   string     `gorm:"type:uuid;not null" json:"admin_id"`
+	CodeHash  string     `gorm:"not null" json:"-"`
+}
+
+type AdminInviteRequest struct {
+	Username string   `json:"username" validate:"required,min=3,max=50"`
+	Email    string   `json:"email" validate:"required,email"`
+	Name     string   `json:"name" validate:"required"`
+	RoleIDs  []string `json:"role_ids" validate:"omitempty,dive,uuid"`
+}
+
+// AdminTwoFactorLoginRequest completes a sign in with a TOTP code or a recovery code
+type AdminTwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token" validate:"required"`
+	Code           string `json:"code" validate:"required"`
+}
+
+type TwoFactorCodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type TwoFactorDisableRequest struct {
+	Password string `json:"password" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
+
+type AdminPasswordChangeRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,min=12,max=72"`
+}
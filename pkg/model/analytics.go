@@ -0,0 +1,57 @@
+package model
+
+import "time"
+
+const (
+	AnalyticsIntervalDay   = "day"
+	AnalyticsIntervalWeek  = "week"
+	AnalyticsIntervalMonth = "month"
+)
+
+// AnalyticsRequest holds the query parameters of the admin analytics. Dates are given as
+// 2006-01-02 or RFC 3339, and a to date includes the whole day
+type AnalyticsRequest struct {
+	From     string `query:"from"`
+	To       string `query:"to"`
+	Interval string `query:"interval"`
+}
+
+// AnalyticsPeriod counts what happened in the day, week or month starting at Start
+type AnalyticsPeriod struct {
+	Start time.Time `json:"start"`
+	Count int64     `json:"count"`
+}
+
+type AnalyticsBreakdown struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+type TopCustomer struct {
+	UserID      string `json:"user_id"`
+	Name        string `json:"name"`
+	Email       string `json:"email"`
+	CompanyName string `json:"company_name"`
+	OrderCount  int64  `json:"order_count"`
+}
+
+// OrderCompletionTime is how long the orders first completed in a date range took from the moment
+// they were placed. AverageHours is nil when no order was completed
+type OrderCompletionTime struct {
+	AverageHours    *float64 `json:"average_hours"`
+	CompletedOrders int64    `json:"completed_orders"`
+}
+
+// AnalyticsResponse describes the orders placed and the users who signed up from From until To.
+// Every period of the range is listed, including the empty ones
+type AnalyticsResponse struct {
+	From                  time.Time            `json:"from"`
+	To                    time.Time            `json:"to"`
+	Interval              string               `json:"interval"`
+	OrdersOverTime        []AnalyticsPeriod    `json:"orders_over_time"`
+	OrdersByShootType     []AnalyticsBreakdown `json:"orders_by_shoot_type"`
+	OrdersByDeliverySpeed []AnalyticsBreakdown `json:"orders_by_delivery_speed"`
+	CompletionTime        OrderCompletionTime  `json:"completion_time"`
+	NewUsersOverTime      []AnalyticsPeriod    `json:"new_users_over_time"`
+	TopCustomers          []TopCustomer        `json:"top_customers"`
+}
@@ -1,10 +1,26 @@
 package model
 
+const (
+	RoleAdmin         = "admin"
+	RoleAuthenticated = "authenticated"
+	// RoleSystem marks changes made by the application itself, such as payment webhooks
+	RoleSystem = "system"
+)
+
+// TokenRequestPayload exchanges an identity provider access token for an API token
 type TokenRequestPayload struct {
-	UserSessionID string `json:"sessionId"`
+	AccessToken string `json:"access_token" validate:"required"`
 }
 
 type AdminLoginRequest struct {
 	Username string `json:"username" validate:"required"`
 	Password string `json:"password" validate:"required"`
 }
+
+// Actor identifies the authenticated caller behind a request. TokenID is the jti of the access
+// token of the request, empty for identity provider tokens
+type Actor struct {
+	ID      string
+	Role    string
+	TokenID string
+}
@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+const (
+	CatalogCategoryShootType     = "shoot_type"
+	CatalogCategoryFinishType    = "finish_type"
+	CatalogCategoryShot          = "shot"
+	CatalogCategoryDeliverySpeed = "delivery_speed"
+)
+
+// CatalogItem is an option customers can pick when ordering. BasePrice is in minor currency units
+type CatalogItem struct {
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	ID          string    `gorm:"default:uuid_generate_v4()" json:"id"`
+	Category    string    `gorm:"not null" json:"category"`
+	Name        string    `gorm:"not null" json:"name"`
+	Description string    `gorm:"type:text" json:"description"`
+	BasePrice   int64     `gorm:"not null;default:0" json:"base_price"`
+	SortOrder   int       `gorm:"not null;default:0" json:"sort_order"`
+	Active      bool      `gorm:"not null;default:true" json:"active"`
+}
+
+type CatalogItemRequest struct {
+	Active      *bool  `json:"active" validate:"omitempty"`
+	Category    string `json:"category" validate:"required,oneof=shoot_type finish_type shot delivery_speed"`
+	Name        string `json:"name" validate:"required"`
+	Description string `json:"description" validate:"omitempty"`
+	BasePrice   int64  `json:"base_price" validate:"min=0"`
+	SortOrder   int    `json:"sort_order" validate:"omitempty"`
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"io"
+	"time"
+)
+
+const (
+	DataExportStatusPending    = "pending"
+	DataExportStatusProcessing = "processing"
+	DataExportStatusReady      = "ready"
+	DataExportStatusFailed     = "failed"
+)
+
+const (
+	SpreadsheetFormatCSV  = "csv"
+	SpreadsheetFormatXLSX = "xlsx"
+)
+
+// DataExport is a ZIP archive of everything held about a user, built in the background
+type DataExport struct {
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+	ClaimedAt   *time.Time `json:"claimed_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	ID          string     `gorm:"default:uuid_generate_v4()" json:"id"`
+	UserID      string     `gorm:"type:uuid;not null" json:"user_id"`
+	RequestedBy string     `gorm:"not null" json:"requested_by"`
+	Status      string     `gorm:"default:pending" json:"status"`
+	FileName    string     `json:"file_name"`
+	LastError   string     `json:"last_error"`
+	Archive     []byte     `gorm:"type:bytea" json:"-"`
+	Attempts    int        `gorm:"not null;default:0" json:"attempts"`
+}
+
+// Spreadsheet is a table streamed to the client as a download. Write runs once the response
+// started, so the errors it returns can only be logged
+type Spreadsheet struct {
+	Write       func(w io.Writer) error
+	FileName    string
+	ContentType string
+}
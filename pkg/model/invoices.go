@@ -0,0 +1,62 @@
+package model
+
+import "time"
+
+const (
+	InvoiceStatusDraft  = "draft"
+	InvoiceStatusIssued = "issued"
+	InvoiceStatusPaid   = "paid"
+	InvoiceStatusVoid   = "void"
+)
+
+const (
+	InvoiceLineKindItem     = "item"
+	InvoiceLineKindDiscount = "discount"
+	InvoiceLineKindTax      = "tax"
+)
+
+// Invoice bills the accepted quote of an order. Amounts are in minor currency units and
+// TaxRateBasisPoints is the tax rate in hundredths of a percent (750 is 7.5%)
+type Invoice struct {
+	CreatedAt          time.Time         `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt          time.Time         `gorm:"autoUpdateTime" json:"updated_at"`
+	IssuedAt           *time.Time        `json:"issued_at"`
+	DueAt              *time.Time        `json:"due_at"`
+	PaidAt             *time.Time        `json:"paid_at"`
+	Order              Order             `gorm:"foreignKey:OrderID" json:"order"`
+	ID                 string            `gorm:"default:uuid_generate_v4()" json:"id"`
+	OrderID            string            `gorm:"type:uuid;not null" json:"order_id"`
+	QuoteID            string            `gorm:"type:uuid;not null" json:"quote_id"`
+	InvoiceNumber      string            `gorm:"unique;not null" json:"invoice_number"`
+	Status             string            `gorm:"default:draft" json:"status"`
+	Currency           string            `gorm:"not null" json:"currency"`
+	Notes              string            `json:"notes"`
+	LineItems          []InvoiceLineItem `gorm:"foreignKey:InvoiceID" json:"line_items"`
+	Subtotal           int64             `gorm:"not null" json:"subtotal"`
+	DiscountAmount     int64             `gorm:"not null" json:"discount_amount"`
+	TaxAmount          int64             `gorm:"not null" json:"tax_amount"`
+	Total              int64             `gorm:"not null" json:"total"`
+	TaxRateBasisPoints int               `gorm:"not null" json:"tax_rate_basis_points"`
+}
+
+type InvoiceLineItem struct {
+	ID          string `gorm:"default:uuid_generate_v4()" json:"id"`
+	InvoiceID   string `gorm:"type:uuid;not null" json:"invoice_id"`
+	Kind        string `gorm:"not null" json:"kind"`
+	Description string `gorm:"not null" json:"description"`
+	Quantity    int    `gorm:"not null" json:"quantity"`
+	UnitPrice   int64  `gorm:"not null" json:"unit_price"`
+	Amount      int64  `gorm:"not null" json:"amount"`
+	Position    int    `gorm:"not null" json:"position"`
+}
+
+type InvoiceRequest struct {
+	TaxRateBasisPoints  *int   `json:"tax_rate_basis_points" validate:"omitempty,min=0,max=10000"`
+	DiscountDescription string `json:"discount_description" validate:"omitempty"`
+	Notes               string `json:"notes" validate:"omitempty"`
+	DiscountAmount      int64  `json:"discount_amount" validate:"min=0"`
+}
+
+type InvoiceStatusChangeRequest struct {
+	Status string `json:"status" validate:"required,oneof=issued paid void"`
+}
@@ -0,0 +1,83 @@
+package model
+
+import "time"
+
+const (
+	SubscriptionStatusActive    = "active"
+	SubscriptionStatusCancelled = "cancelled"
+	SubscriptionStatusExpired   = "expired"
+)
+
+const (
+	// MembershipPAYG is the membership status of users without an active plan
+	MembershipPAYG = "PAYG"
+	// OrderMembershipPayAsYouGo is the membership type of orders not covered by a plan
+	OrderMembershipPayAsYouGo = "PAY AS YOU GO"
+)
+
+// MembershipPlan is a monthly subscription that covers a number of shots per period and
+// discounts the quotes of the orders placed under it. MonthlyPrice is in minor currency units
+type MembershipPlan struct {
+	CreatedAt       time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	ID              string    `gorm:"default:uuid_generate_v4()" json:"id"`
+	Name            string    `gorm:"not null;unique" json:"name"`
+	Description     string    `gorm:"type:text" json:"description"`
+	Currency        string    `gorm:"not null" json:"currency"`
+	MonthlyPrice    int64     `gorm:"not null;default:0" json:"monthly_price"`
+	IncludedShots   int       `gorm:"not null;default:0" json:"included_shots"`
+	DiscountPercent int       `gorm:"not null;default:0" json:"discount_percent"`
+	Active          bool      `gorm:"not null;default:true" json:"active"`
+}
+
+// UserSubscription puts a user on a plan. ShotsUsed counts the shots ordered since
+// CurrentPeriodStart and is reset when the subscription renews at RenewsAt
+type UserSubscription struct {
+	CreatedAt          time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt          time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	StartedAt          time.Time      `gorm:"not null" json:"started_at"`
+	CurrentPeriodStart time.Time      `gorm:"not null" json:"current_period_start"`
+	RenewsAt           time.Time      `gorm:"not null" json:"renews_at"`
+	EndedAt            *time.Time     `json:"ended_at"`
+	Plan               MembershipPlan `gorm:"foreignKey:PlanID" json:"plan"`
+	ID                 string         `gorm:"default:uuid_generate_v4()" json:"id"`
+	UserID             string         `gorm:"type:uuid;not null" json:"user_id"`
+	PlanID             string         `gorm:"type:uuid;not null" json:"plan_id"`
+	Status             string         `gorm:"not null;default:active" json:"status"`
+	CancelAtPeriodEnd  bool           `gorm:"not null;default:false" json:"cancel_at_period_end"`
+	ShotsUsed          int            `gorm:"not null;default:0" json:"shots_used"`
+}
+
+// ShotsRemaining is the number of shots the plan still covers in the current period
+func (s *UserSubscription) ShotsRemaining() int {
+	return max(s.Plan.IncludedShots-s.ShotsUsed, 0)
+}
+
+type MembershipPlanRequest struct {
+	Active          *bool  `json:"active" validate:"omitempty"`
+	Name            string `json:"name" validate:"required,max=100"`
+	Description     string `json:"description" validate:"omitempty"`
+	Currency        string `json:"currency" validate:"omitempty,len=3"`
+	MonthlyPrice    int64  `json:"monthly_price" validate:"min=0"`
+	IncludedShots   int    `json:"included_shots" validate:"min=0"`
+	DiscountPercent int    `json:"discount_percent" validate:"min=0,max=100"`
+}
+
+// SubscriptionRequest puts a user on a plan, replacing the plan they are on
+type SubscriptionRequest struct {
+	PlanID string `json:"plan_id" validate:"required,uuid"`
+}
+
+type SubscriptionResponse struct {
+	StartedAt          time.Time      `json:"started_at"`
+	CurrentPeriodStart time.Time      `json:"current_period_start"`
+	RenewsAt           time.Time      `json:"renews_at"`
+	EndedAt            *time.Time     `json:"ended_at"`
+	Plan               MembershipPlan `json:"plan"`
+	ID                 string         `json:"id"`
+	UserID             string         `json:"user_id"`
+	Status             string         `json:"status"`
+	CancelAtPeriodEnd  bool           `json:"cancel_at_period_end"`
+	ShotsUsed          int            `json:"shots_used"`
+	ShotsRemaining     int            `json:"shots_remaining"`
+}
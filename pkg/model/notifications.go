@@ -0,0 +1,75 @@
+package model
+
+import "time"
+
+const (
+	NotificationChannelEmail    = "email"
+	NotificationChannelSMS      = "sms"
+	NotificationChannelWhatsApp = "whatsapp"
+)
+
+const (
+	NotificationCategoryOrderUpdates = "order_updates"
+	NotificationCategoryMarketing    = "marketing"
+	NotificationCategoryBlog         = "blog"
+)
+
+// NotificationPreference holds the channels a user can be reached on and the kinds of
+// notifications they want. A notification is only sent when both its channel and its category
+// are switched on
+type NotificationPreference struct {
+	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	UserID       string    `gorm:"type:uuid;primaryKey" json:"user_id"`
+	Email        bool      `gorm:"column:email_enabled;not null" json:"email"`
+	SMS          bool      `gorm:"column:sms_enabled;not null" json:"sms"`
+	WhatsApp     bool      `gorm:"column:whatsapp_enabled;not null" json:"whatsapp"`
+	OrderUpdates bool      `gorm:"not null" json:"order_updates"`
+	Marketing    bool      `gorm:"not null" json:"marketing"`
+	Blog         bool      `gorm:"not null" json:"blog"`
+}
+
+// DefaultNotificationPreference is what users who never changed their preferences get: order
+// updates by email and nothing else
+func DefaultNotificationPreference(userID string) *NotificationPreference {
+	return &NotificationPreference{
+		UserID:       userID,
+		Email:        true,
+		OrderUpdates: true,
+	}
+}
+
+// Allows reports whether the user wants notifications of category over channel
+func (p *NotificationPreference) Allows(channel, category string) bool {
+	var channelEnabled, categoryEnabled bool
+
+	switch channel {
+	case NotificationChannelEmail:
+		channelEnabled = p.Email
+	case NotificationChannelSMS:
+		channelEnabled = p.SMS
+	case NotificationChannelWhatsApp:
+		channelEnabled = p.WhatsApp
+	}
+
+	switch category {
+	case NotificationCategoryOrderUpdates:
+		categoryEnabled = p.OrderUpdates
+	case NotificationCategoryMarketing:
+		categoryEnabled = p.Marketing
+	case NotificationCategoryBlog:
+		categoryEnabled = p.Blog
+	}
+
+	return channelEnabled && categoryEnabled
+}
+
+// NotificationPreferenceRequest switches the channels and categories that are sent on or off.
+// Fields left out are not changed
+type NotificationPreferenceRequest struct {
+	Email        *bool `json:"email"`
+	SMS          *bool `json:"sms"`
+	WhatsApp     *bool `json:"whatsapp"`
+	OrderUpdates *bool `json:"order_updates"`
+	Marketing    *bool `json:"marketing"`
+	Blog         *bool `json:"blog"`
+}
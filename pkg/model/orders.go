@@ -5,22 +5,74 @@ import (
 
 	"github.com/lib/pq"
 	"gorm.io/datatypes"
+	"gorm.io/gorm"
 )
 
+const (
+	OrderStatusQuoteReceived   = "quote_received"
+	OrderStatusQuoted          = "quoted"
+	OrderStatusAccepted        = "accepted"
+	OrderStatusPaid            = "paid"
+	OrderStatusProductReceived = "product_received"
+	OrderStatusShooting        = "shooting"
+	OrderStatusEditing         = "editing"
+	OrderStatusDelivered       = "delivered"
+	OrderStatusMarkCompleted   = "mark_completed"
+	OrderStatusCancelled       = "cancelled"
+)
+
+// OrdersCount counts the orders matching a filter. Matching also applies the status filter, the
+// other counts ignore it
 type OrdersCount struct {
 	Total          int64 `json:"total_orders"`
 	ActiveCount    int64 `json:"active_orders"`
 	PendingCount   int64 `json:"pending_orders"`
 	CompletedCount int64 `json:"completed_orders"`
+	Matching       int64 `json:"matching_orders"`
+}
+
+// OrderFilterRequest holds the query parameters of the admin order list. Dates are given as
+// 2006-01-02 or RFC 3339, and a created_to date includes the whole day
+type OrderFilterRequest struct {
+	Status         string `query:"status"`
+	UserID         string `query:"user_id"`
+	ShootType      string `query:"shoot_type"`
+	DeliverySpeed  string `query:"delivery_speed"`
+	MembershipType string `query:"membership_type"`
+	CreatedFrom    string `query:"created_from"`
+	CreatedTo      string `query:"created_to"`
+	Search         string `query:"search"`
+	Sort           string `query:"sort"`
+	Order          string `query:"order"`
+}
+
+// OrderFilter selects and sorts the orders listed to staff. Status is an order status or one of
+// the active, pending, completed and deleted groups. CreatedTo is exclusive and Sort is a column
+// of the orders table
+type OrderFilter struct {
+	CreatedFrom    *time.Time
+	CreatedTo      *time.Time
+	Status         string
+	UserID         string
+	ShootType      string
+	DeliverySpeed  string
+	MembershipType string
+	Search         string
+	Sort           string
+	Descending     bool
 }
 
+// Order is hidden from every query once DeletedAt is set, until an admin restores it
 type Order struct {
 	CreatedAt          time.Time      `gorm:"autoCreateTime" json:"created_at"`
 	UpdatedAt          time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt          gorm.DeletedAt `gorm:"index" json:"deleted_at" swaggertype:"string"`
 	User               User           `gorm:"foreignKey:UserID" json:"user"`
 	ID                 string         `gorm:"default:uuid_generate_v4()" json:"id"`
 	OrderName          string         `gorm:"unique;not null" json:"order_name"`
 	UserID             string         `gorm:"type:uuid;not null" json:"user_id"`
+	OrganizationID     *string        `gorm:"type:uuid" json:"organization_id"`
+	SubscriptionID     *string        `gorm:"type:uuid" json:"subscription_id"`
 	ProductName        string         `gorm:"not null" json:"product_name"`
 	ProductDescription string         `gorm:"type:text" json:"product_description"`
 	ShootType          string         `gorm:"not null" json:"shoot_type"`
@@ -28,25 +80,84 @@ type Order struct {
 	DeliverySpeed      string         `gorm:"default:Standard" json:"delivery_speed"`
 	MembershipType     string         `gorm:"default:PAY AS YOU GO" json:"membership_type"`
 	Status             string         `gorm:"default:quote_received" json:"status"`
+	CancellationReason string         `json:"cancellation_reason"`
 	Details            datatypes.JSON `gorm:"type:jsonb" json:"details"`
 	Shots              pq.StringArray `gorm:"type:text[]" json:"shots"`
+	Currency           string         `json:"currency"`
+	QuotedAmount       int64          `gorm:"not null;default:0" json:"quoted_amount"`
 	Quantity           int            `gorm:"not null" json:"quantity"`
+	PlanShots          int            `gorm:"not null;default:0" json:"plan_shots"`
+	DiscountPercent    int            `gorm:"not null;default:0" json:"discount_percent"`
 }
 
 type OrderRequest struct {
 	UserID             string         `json:"user_id" validate:"required"`
+	OrganizationID     string         `json:"organization_id" validate:"omitempty,uuid"`
 	ProductName        string         `json:"product_name" validate:"required"`
 	ProductDescription string         `json:"product_description" validate:"required"`
 	ShootType          string         `json:"shoot_type" validate:"required"`
 	FinishType         string         `json:"finish_type" validate:"omitempty"`
 	DeliverySpeed      string         `json:"delivery_speed" validate:"omitempty"`
-	Status             string         `json:"status" validate:"omitempty"`
-	MembershipType     string         `json:"membership_type" validate:"omitempty"`
 	Details            map[string]any `json:"details" validate:"omitempty"`
 	Shots              []string       `json:"shots" validate:"omitempty"`
 	Quantity           int            `json:"quantity" validate:"omitempty"`
 }
 
+// OrderUpdateRequest changes the options that are sent of an order that was not quoted yet
+type OrderUpdateRequest struct {
+	FinishType *string         `json:"finish_type" validate:"omitempty"`
+	Details    *map[string]any `json:"details" validate:"omitempty"`
+	Shots      *[]string       `json:"shots" validate:"omitempty"`
+	Quantity   *int            `json:"quantity" validate:"omitempty,min=1"`
+}
+
+// OrderRevision records the fields of an order changed by an edit. Changes maps the JSON name
+// of every changed field to an OrderFieldChange
+type OrderRevision struct {
+	CreatedAt     time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	ID            string         `gorm:"default:uuid_generate_v4()" json:"id"`
+	OrderID       string         `gorm:"type:uuid;not null" json:"order_id"`
+	ChangedBy     string         `gorm:"not null" json:"changed_by"`
+	ChangedByRole string         `json:"changed_by_role"`
+	Changes       datatypes.JSON `gorm:"type:jsonb;not null" json:"changes" swaggertype:"object"`
+}
+
+type OrderFieldChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+type OrderCancelRequest struct {
+	Reason string `json:"reason" validate:"required,max=1000"`
+}
+
 type OrderStatusChangeRequest struct {
 	Status string `json:"status" validate:"required"`
+	Note   string `json:"note" validate:"omitempty"`
+}
+
+type OrderStatusHistory struct {
+	CreatedAt     time.Time `gorm:"autoCreateTime" json:"created_at"`
+	ID            string    `gorm:"default:uuid_generate_v4()" json:"id"`
+	OrderID       string    `gorm:"type:uuid;not null" json:"order_id"`
+	FromStatus    string    `json:"from_status"`
+	ToStatus      string    `gorm:"not null" json:"to_status"`
+	ChangedBy     string    `gorm:"not null" json:"changed_by"`
+	ChangedByRole string    `json:"changed_by_role"`
+	Note          string    `json:"note"`
+}
+
+func (OrderStatusHistory) TableName() string {
+	return "order_status_history"
+}
+
+// OrderStatusEmailSetting controls whether customers are emailed when an order enters a status
+type OrderStatusEmailSetting struct {
+	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	Status       string    `gorm:"primaryKey" json:"status"`
+	EmailEnabled bool      `gorm:"not null;default:true" json:"email_enabled"`
+}
+
+type OrderStatusEmailSettingRequest struct {
+	EmailEnabled *bool `json:"email_enabled" validate:"required"`
 }
@@ -0,0 +1,64 @@
+package model
+
+import "time"
+
+const (
+	OrganizationRoleOwner  = "owner"
+	OrganizationRoleMember = "member"
+)
+
+// Organization is a company account whose members share the orders placed for it
+type Organization struct {
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	ID        string    `gorm:"default:uuid_generate_v4()" json:"id"`
+	Name      string    `gorm:"not null" json:"name"`
+	CreatedBy string    `gorm:"type:uuid;not null" json:"created_by"`
+}
+
+type OrganizationMember struct {
+	CreatedAt      time.Time    `gorm:"autoCreateTime" json:"created_at"`
+	Organization   Organization `gorm:"foreignKey:OrganizationID" json:"-"`
+	User           User         `gorm:"foreignKey:UserID" json:"user"`
+	OrganizationID string       `gorm:"type:uuid;primaryKey" json:"organization_id"`
+	UserID         string       `gorm:"type:uuid;primaryKey" json:"user_id"`
+	Role           string       `gorm:"not null" json:"role"`
+}
+
+// OrganizationInvitation lets the holder of the emailed token join an organization with the
+// email address it was sent to
+type OrganizationInvitation struct {
+	CreatedAt      time.Time    `gorm:"autoCreateTime" json:"created_at"`
+	ExpiresAt      time.Time    `gorm:"not null" json:"expires_at"`
+	AcceptedAt     *time.Time   `json:"accepted_at"`
+	Organization   Organization `gorm:"foreignKey:OrganizationID" json:"-"`
+	ID             string       `gorm:"default:uuid_generate_v4()" json:"id"`
+	OrganizationID string       `gorm:"type:uuid;not null" json:"organization_id"`
+	Email          string       `gorm:"not null" json:"email"`
+	Role           string       `gorm:"not null" json:"role"`
+	TokenHash      string       `gorm:"not null;unique" json:"-"`
+	InvitedBy      string       `gorm:"type:uuid;not null" json:"invited_by"`
+}
+
+// ResourceOwner is the user who owns a resource and the organization it is shared with, if any
+type ResourceOwner struct {
+	UserID         string
+	OrganizationID string
+}
+
+type OrganizationRequest struct {
+	Name string `json:"name" validate:"required,max=255"`
+}
+
+type OrganizationInviteRequest struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"omitempty,oneof=owner member"`
+}
+
+type OrganizationMemberRoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=owner member"`
+}
+
+type AcceptInvitationRequest struct {
+	Token string `json:"token" validate:"required"`
+}
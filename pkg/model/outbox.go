@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+const (
+	OutboxStatusPending = "pending"
+	OutboxStatusSent    = "sent"
+	OutboxStatusDead    = "dead"
+)
+
+// EmailOutbox is an email, SMS or WhatsApp message waiting to be delivered by the outbox worker.
+// Recipient is an email address for emails and a phone number otherwise. Text messages have no
+// subject. UserID is set on messages sent to a customer so they can be dropped when the account
+// is deleted. The body of a Sensitive message, which carries a password or a sign in token, is
+// removed once it was sent or given up on
+type EmailOutbox struct {
+	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+	NextAttemptAt time.Time  `gorm:"not null" json:"next_attempt_at"`
+	SentAt        *time.Time `json:"sent_at"`
+	UserID        *string    `gorm:"type:uuid;index" json:"user_id"`
+	ID            string     `gorm:"default:uuid_generate_v4()" json:"id"`
+	Channel       string     `gorm:"default:email" json:"channel"`
+	Recipient     string     `gorm:"not null" json:"recipient"`
+	Subject       string     `gorm:"not null" json:"subject"`
+	Body          string     `gorm:"type:text;not null" json:"body"`
+	Status        string     `gorm:"default:pending" json:"status"`
+	LastError     string     `json:"last_error"`
+	Sensitive     bool       `gorm:"not null;default:false" json:"sensitive"`
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	MaxAttempts   int        `gorm:"not null" json:"max_attempts"`
+}
+
+func (EmailOutbox) TableName() string {
+	return "email_outbox"
+}
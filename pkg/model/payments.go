@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+const (
+	PaymentStatusPending   = "pending"
+	PaymentStatusSucceeded = "succeeded"
+	PaymentStatusFailed    = "failed"
+	PaymentStatusRefunded  = "refunded"
+)
+
+// Payment is an attempt to settle an invoice through the payment provider. Reference is the
+// reference shared with the provider and is used to match webhooks to the payment
+type Payment struct {
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+	PaidAt         *time.Time `json:"paid_at"`
+	ID             string     `gorm:"default:uuid_generate_v4()" json:"id"`
+	InvoiceID      string     `gorm:"type:uuid;not null" json:"invoice_id"`
+	OrderID        string     `gorm:"type:uuid;not null" json:"order_id"`
+	Provider       string     `gorm:"not null" json:"provider"`
+	Reference      string     `gorm:"unique;not null" json:"reference"`
+	CheckoutURL    string     `json:"checkout_url"`
+	Status         string     `gorm:"default:pending" json:"status"`
+	Currency       string     `gorm:"not null" json:"currency"`
+	FailureReason  string     `json:"failure_reason"`
+	Amount         int64      `gorm:"not null" json:"amount"`
+	RefundedAmount int64      `gorm:"not null;default:0" json:"refunded_amount"`
+}
+
+type PaymentRefundRequest struct {
+	// Amount to refund in minor currency units, the whole remaining amount when zero
+	Amount int64 `json:"amount" validate:"min=0"`
+}
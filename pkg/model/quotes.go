@@ -0,0 +1,64 @@
+package model
+
+import "time"
+
+const (
+	QuoteStatusPending    = "pending"
+	QuoteStatusAccepted   = "accepted"
+	QuoteStatusRejected   = "rejected"
+	QuoteStatusSuperseded = "superseded"
+)
+
+const (
+	LineItemKindShootType     = "shoot_type"
+	LineItemKindFinishType    = "finish_type"
+	LineItemKindShot          = "shot"
+	LineItemKindDeliverySpeed = "delivery_speed"
+	LineItemKindCustom        = "custom"
+	LineItemKindDiscount      = "discount"
+)
+
+// Quote is a priced offer for an order. Amounts are in minor currency units (e.g. kobo)
+type Quote struct {
+	CreatedAt       time.Time       `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
+	RespondedAt     *time.Time      `json:"responded_at"`
+	ID              string          `gorm:"default:uuid_generate_v4()" json:"id"`
+	OrderID         string          `gorm:"type:uuid;not null" json:"order_id"`
+	Status          string          `gorm:"default:pending" json:"status"`
+	Currency        string          `gorm:"not null" json:"currency"`
+	Notes           string          `json:"notes"`
+	RejectionReason string          `json:"rejection_reason"`
+	IssuedBy        string          `json:"issued_by"`
+	LineItems       []QuoteLineItem `gorm:"foreignKey:QuoteID" json:"line_items"`
+	Total           int64           `gorm:"not null" json:"total"`
+}
+
+type QuoteLineItem struct {
+	ID          string `gorm:"default:uuid_generate_v4()" json:"id"`
+	QuoteID     string `gorm:"type:uuid;not null" json:"quote_id"`
+	Kind        string `gorm:"not null" json:"kind"`
+	Description string `gorm:"not null" json:"description"`
+	Quantity    int    `gorm:"not null" json:"quantity"`
+	UnitPrice   int64  `gorm:"not null" json:"unit_price"`
+	Amount      int64  `gorm:"not null" json:"amount"`
+	Position    int    `gorm:"not null" json:"position"`
+}
+
+type QuoteLineItemRequest struct {
+	Kind        string `json:"kind" validate:"required,oneof=shoot_type finish_type shot delivery_speed custom"`
+	Description string `json:"description" validate:"required"`
+	Quantity    int    `json:"quantity" validate:"required,min=1"`
+	UnitPrice   int64  `json:"unit_price" validate:"min=0"`
+}
+
+// QuoteRequest issues a quote. When LineItems is empty they are derived from the order options
+type QuoteRequest struct {
+	Currency  string                 `json:"currency" validate:"omitempty,len=3"`
+	Notes     string                 `json:"notes" validate:"omitempty"`
+	LineItems []QuoteLineItemRequest `json:"line_items" validate:"omitempty,dive"`
+}
+
+type QuoteRejectRequest struct {
+	Reason string `json:"reason" validate:"required"`
+}
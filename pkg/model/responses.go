@@ -11,24 +11,26 @@ type ResponseHTTP struct {
 }
 
 type UserResponse struct {
-	CreatedAt         time.Time `json:"created_at"`
-	UpdatedAt         time.Time `json:"updated_at"`
-	ID                string    `json:"id"`
-	Name              string    `json:"name"`
-	Email             string    `json:"email"`
-	CompanyName       string    `json:"company_name"`
-	Phone             string    `json:"phone_number"`
-	PreferredMode     string    `json:"preferred_mode_of_communication"`
-	WantToReceiveText bool      `json:"want_to_receive_text"`
+	CreatedAt               time.Time               `json:"created_at"`
+	UpdatedAt               time.Time               `json:"updated_at"`
+	ID                      string                  `json:"id"`
+	Name                    string                  `json:"name"`
+	Email                   string                  `json:"email"`
+	CompanyName             string                  `json:"company_name"`
+	Phone                   string                  `json:"phone_number"`
+	DeletedAt               *time.Time              `json:"deleted_at,omitempty"`
+	NotificationPreferences *NotificationPreference `json:"notification_preferences,omitempty"`
 }
 
 type OrderResponse struct {
 	CreatedAt            time.Time      `json:"created_at"`
 	UpdatedAt            time.Time      `json:"updated_at"`
+	DeletedAt            *time.Time     `json:"deleted_at,omitempty"`
 	ProductDescription   string         `gorm:"type:text" json:"product_description"`
 	ID                   string         `json:"id"`
 	OrderName            string         `json:"order_name"`
 	UserID               string         `json:"user_id"`
+	OrganizationID       *string        `json:"organization_id"`
 	UserEmail            string         `json:"user_email"`
 	UserMembershipStatus string         `json:"user_membership_status"`
 	ProductName          string         `json:"product_name"`
@@ -36,10 +38,15 @@ type OrderResponse struct {
 	FinishType           string         `json:"finish_type"`
 	DeliverySpeed        string         `json:"delivery_speed"`
 	Status               string         `json:"status"`
+	CancellationReason   string         `json:"cancellation_reason,omitempty"`
 	MembershipType       string         `json:"membership_type"`
 	Details              map[string]any `json:"details"`
+	Currency             string         `json:"currency"`
 	Shots                []string       `json:"shots"`
+	QuotedAmount         int64          `json:"quoted_amount"`
 	Quantity             int            `json:"quantity"`
+	PlanShots            int            `json:"plan_shots"`
+	DiscountPercent      int            `json:"discount_percent"`
 }
 
 type TotalOrderResponse struct {
@@ -47,6 +54,165 @@ type TotalOrderResponse struct {
 	OrderNumbers OrdersCount      `json:"orders_count"`
 }
 
+type OrderStatusHistoryResponse struct {
+	CreatedAt     time.Time `json:"created_at"`
+	ID            string    `json:"id"`
+	OrderID       string    `json:"order_id"`
+	FromStatus    string    `json:"from_status"`
+	ToStatus      string    `json:"to_status"`
+	ChangedBy     string    `json:"changed_by"`
+	ChangedByRole string    `json:"changed_by_role"`
+	Note          string    `json:"note"`
+}
+
+type EmailOutboxResponse struct {
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	SentAt        *time.Time `json:"sent_at"`
+	ID            string     `json:"id"`
+	Channel       string     `json:"channel"`
+	Recipient     string     `json:"recipient"`
+	Subject       string     `json:"subject"`
+	Status        string     `json:"status"`
+	LastError     string     `json:"last_error"`
+	Attempts      int        `json:"attempts"`
+	MaxAttempts   int        `json:"max_attempts"`
+}
+
+type TotalEmailOutboxResponse struct {
+	Emails []*EmailOutboxResponse `json:"emails"`
+	Total  int64                  `json:"total"`
+}
+
+type QuoteLineItemResponse struct {
+	Kind        string `json:"kind"`
+	Description string `json:"description"`
+	Quantity    int    `json:"quantity"`
+	UnitPrice   int64  `json:"unit_price"`
+	Amount      int64  `json:"amount"`
+}
+
+type QuoteResponse struct {
+	CreatedAt       time.Time                `json:"created_at"`
+	RespondedAt     *time.Time               `json:"responded_at"`
+	ID              string                   `json:"id"`
+	OrderID         string                   `json:"order_id"`
+	Status          string                   `json:"status"`
+	Currency        string                   `json:"currency"`
+	Notes           string                   `json:"notes"`
+	RejectionReason string                   `json:"rejection_reason"`
+	LineItems       []*QuoteLineItemResponse `json:"line_items"`
+	Total           int64                    `json:"total"`
+}
+
+type CatalogItemResponse struct {
+	ID          string `json:"id"`
+	Category    string `json:"category"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	BasePrice   int64  `json:"base_price"`
+	SortOrder   int    `json:"sort_order"`
+	Active      bool   `json:"active"`
+}
+
+type CatalogResponse struct {
+	ShootTypes     []*CatalogItemResponse `json:"shoot_types"`
+	FinishTypes    []*CatalogItemResponse `json:"finish_types"`
+	Shots          []*CatalogItemResponse `json:"shots"`
+	DeliverySpeeds []*CatalogItemResponse `json:"delivery_speeds"`
+}
+
+type InvoiceLineItemResponse struct {
+	Kind        string `json:"kind"`
+	Description string `json:"description"`
+	Quantity    int    `json:"quantity"`
+	UnitPrice   int64  `json:"unit_price"`
+	Amount      int64  `json:"amount"`
+}
+
+type InvoiceResponse struct {
+	CreatedAt          time.Time                  `json:"created_at"`
+	UpdatedAt          time.Time                  `json:"updated_at"`
+	IssuedAt           *time.Time                 `json:"issued_at"`
+	DueAt              *time.Time                 `json:"due_at"`
+	PaidAt             *time.Time                 `json:"paid_at"`
+	ID                 string                     `json:"id"`
+	InvoiceNumber      string                     `json:"invoice_number"`
+	OrderID            string                     `json:"order_id"`
+	OrderName          string                     `json:"order_name"`
+	UserID             string                     `json:"user_id"`
+	Status             string                     `json:"status"`
+	Currency           string                     `json:"currency"`
+	Notes              string                     `json:"notes"`
+	LineItems          []*InvoiceLineItemResponse `json:"line_items"`
+	Subtotal           int64                      `json:"subtotal"`
+	DiscountAmount     int64                      `json:"discount_amount"`
+	TaxAmount          int64                      `json:"tax_amount"`
+	Total              int64                      `json:"total"`
+	TaxRateBasisPoints int                        `json:"tax_rate_basis_points"`
+}
+
+type TotalInvoiceResponse struct {
+	Invoices []*InvoiceResponse `json:"invoices"`
+	Total    int64              `json:"total"`
+}
+
+type PaymentResponse struct {
+	CreatedAt      time.Time  `json:"created_at"`
+	PaidAt         *time.Time `json:"paid_at"`
+	ID             string     `json:"id"`
+	InvoiceID      string     `json:"invoice_id"`
+	OrderID        string     `json:"order_id"`
+	Provider       string     `json:"provider"`
+	Reference      string     `json:"reference"`
+	CheckoutURL    string     `json:"checkout_url"`
+	Status         string     `json:"status"`
+	Currency       string     `json:"currency"`
+	FailureReason  string     `json:"failure_reason"`
+	Amount         int64      `json:"amount"`
+	RefundedAmount int64      `json:"refunded_amount"`
+}
+
+type AdminResponse struct {
+	CreatedAt          time.Time  `json:"created_at"`
+	LastLoginAt        *time.Time `json:"last_login_at"`
+	ID                 string     `json:"id"`
+	Username           string     `json:"username"`
+	Email              string     `json:"email"`
+	Name               string     `json:"name"`
+	Active             bool       `json:"active"`
+	MustChangePassword bool       `json:"must_change_password"`
+	TOTPEnabled        bool       `json:"totp_enabled"`
+}
+
+// TokenResponse is a short-lived access token with the refresh token that renews it
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"`
+}
+
+// AdminLoginResponse carries the session tokens, or only a challenge token when the admin has
+// two-factor authentication enabled
+type AdminLoginResponse struct {
+	*TokenResponse
+	ChallengeToken     string `json:"challenge_token,omitempty"`
+	TwoFactorRequired  bool   `json:"two_factor_required"`
+	MustChangePassword bool   `json:"must_change_password"`
+}
+
+type TwoFactorSetupResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// RecoveryCodesResponse shows recovery codes the only time they exist in plain text
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
 type PostResponse struct {
 	CreatedAt  time.Time `json:"created_at"`
 	UpdatedAt  time.Time `json:"updated_at"`
@@ -67,3 +233,45 @@ type UploadImageResponse struct {
 	ImageURL string `json:"image_url"`
 	FileName string `json:"file_name"`
 }
+
+type RoleResponse struct {
+	CreatedAt   time.Time `json:"created_at"`
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Permissions []string  `json:"permissions"`
+}
+
+type DataExportResponse struct {
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	ID          string     `json:"id"`
+	UserID      string     `json:"user_id"`
+	Status      string     `json:"status"`
+	DownloadURL string     `json:"download_url,omitempty"`
+}
+
+type OrganizationResponse struct {
+	CreatedAt time.Time `json:"created_at"`
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Role      string    `json:"role,omitempty"`
+}
+
+type OrganizationMemberResponse struct {
+	JoinedAt time.Time `json:"joined_at"`
+	UserID   string    `json:"user_id"`
+	Name     string    `json:"name"`
+	Email    string    `json:"email"`
+	Role     string    `json:"role"`
+}
+
+type OrganizationInvitationResponse struct {
+	CreatedAt      time.Time `json:"created_at"`
+	ExpiresAt      time.Time `json:"expires_at"`
+	ID             string    `json:"id"`
+	OrganizationID string    `json:"organization_id"`
+	Email          string    `json:"email"`
+	Role           string    `json:"role"`
+}
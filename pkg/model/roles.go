@@ -0,0 +1,67 @@
+package model
+
+import "time"
+
+// Permissions granted to staff through their roles
+const (
+	PermissionUsersRead             = "users:read"
+	PermissionUsersUpdate           = "users:update"
+	PermissionUsersUpdateMembership = "users:update_membership"
+	PermissionOrdersRead            = "orders:read"
+	PermissionOrdersUpdateStatus    = "orders:update_status"
+	PermissionOrdersDelete          = "orders:delete"
+	PermissionQuotesManage          = "quotes:manage"
+	PermissionInvoicesManage        = "invoices:manage"
+	PermissionPaymentsRefund        = "payments:refund"
+	PermissionCatalogManage         = "catalog:manage"
+	PermissionEmailsManage          = "emails:manage"
+	PermissionPostsWrite            = "posts:write"
+	PermissionPostsPublish          = "posts:publish"
+	PermissionPostsDelete           = "posts:delete"
+	PermissionGalleryWrite          = "gallery:write"
+	PermissionGalleryDelete         = "gallery:delete"
+	PermissionStaffManage           = "staff:manage"
+	PermissionSessionsRevoke        = "sessions:revoke"
+	PermissionPlansManage           = "plans:manage"
+	PermissionAnalyticsRead         = "analytics:read"
+	PermissionOrganizationsManage   = "organizations:manage"
+)
+
+// OwnerRole is the role seeded with every permission and given to the first admin
+const OwnerRole = "owner"
+
+// Permission is an action on the API that can be granted to a role
+type Permission struct {
+	Key         string `gorm:"primaryKey" json:"key"`
+	Description string `gorm:"not null" json:"description"`
+}
+
+type Role struct {
+	CreatedAt   time.Time        `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time        `gorm:"autoUpdateTime" json:"updated_at"`
+	ID          string           `gorm:"default:uuid_generate_v4()" json:"id"`
+	Name        string           `gorm:"unique;not null" json:"name"`
+	Description string           `json:"description"`
+	Permissions []RolePermission `gorm:"foreignKey:RoleID;constraint:OnDelete:CASCADE" json:"permissions"`
+}
+
+type RolePermission struct {
+	RoleID     string `gorm:"type:uuid;primaryKey" json:"role_id"`
+	Permission string `gorm:"primaryKey" json:"permission"`
+}
+
+// AdminRole assigns a role to a staff account
+type AdminRole struct {
+	AdminID string `gorm:"type:uuid;primaryKey" json:"admin_id"`
+	RoleID  string `gorm:"type:uuid;primaryKey" json:"role_id"`
+}
+
+type RoleRequest struct {
+	Name        string   `json:"name" validate:"required,min=2,max=50"`
+	Description string   `json:"description" validate:"omitempty"`
+	Permissions []string `json:"permissions" validate:"required,min=1,dive,required"`
+}
+
+type AdminRolesRequest struct {
+	RoleIDs []string `json:"role_ids" validate:"required,dive,uuid"`
+}
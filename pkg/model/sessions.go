@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// RefreshToken is one link in a chain of rotating refresh tokens. Only the hash of the token is
+// stored. Every refresh revokes the token and issues a new one in the same family
+type RefreshToken struct {
+	CreatedAt       time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	ExpiresAt       time.Time  `gorm:"not null" json:"expires_at"`
+	AccessExpiresAt time.Time  `gorm:"not null" json:"access_expires_at"`
+	RevokedAt       *time.Time `json:"revoked_at"`
+	ID              string     `gorm:"default:uuid_generate_v4()" json:"id"`
+	FamilyID        string     `gorm:"type:uuid;not null" json:"family_id"`
+	SubjectID       string     `gorm:"not null" json:"subject_id"`
+	Role            string     `gorm:"not null" json:"role"`
+	TokenHash       string     `gorm:"unique;not null" json:"-"`
+	AccessTokenID   string     `gorm:"not null" json:"access_token_id"`
+}
+
+// RevokedToken lists the jti of an access token that must be refused before it expires
+type RevokedToken struct {
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	ExpiresAt time.Time `gorm:"not null" json:"expires_at"`
+	JTI       string    `gorm:"column:jti;primaryKey" json:"jti"`
+}
+
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
+}
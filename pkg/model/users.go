@@ -7,14 +7,15 @@ type GetUserByEmailRequest struct {
 }
 
 type User struct {
-	CreatedAt        time.Time `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt        time.Time `gorm:"autoUpdateTime" json:"updated_at"`
-	ID               string    `gorm:"default:uuid_generate_v4()" json:"id"`
-	Name             string    `gorm:"not null" json:"name"`
-	Email            string    `gorm:"not null;unique" json:"email"`
-	CompanyName      string    `gorm:"not null" json:"company_name"`
-	PhoneNumber      string    `gorm:"not null" json:"phone_number"`
-	MembershipStatus string    `gorm:"default:PAYG" json:"membership_status"`
+	CreatedAt        time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt        time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt        *time.Time `json:"deleted_at"`
+	ID               string     `gorm:"default:uuid_generate_v4()" json:"id"`
+	Name             string     `gorm:"not null" json:"name"`
+	Email            string     `gorm:"not null;unique" json:"email"`
+	CompanyName      string     `gorm:"not null" json:"company_name"`
+	PhoneNumber      string     `gorm:"not null" json:"phone_number"`
+	MembershipStatus string     `gorm:"default:PAYG" json:"membership_status"`
 }
 
 type CreateUserRequest struct {
@@ -25,6 +26,10 @@ type CreateUserRequest struct {
 	Phone       string `json:"phone_number" validate:"required"`
 }
 
-type MembershipStatusChangeRequest struct {
-	Status string `json:"membership_status" validate:"required"`
+// UpdateUserRequest changes the profile fields that are sent. The email address belongs to the
+// sign in provider and cannot be changed here. An empty phone number removes it
+type UpdateUserRequest struct {
+	Name        *string `json:"name" validate:"omitempty,min=1,max=255"`
+	CompanyName *string `json:"company_name" validate:"omitempty,max=255"`
+	Phone       *string `json:"phone_number" validate:"omitnil,max=20,eq=|min=7"`
 }
@@ -76,3 +76,28 @@ func ExtractPublicID(url string) (string, error) {
 
 	return matches[1], nil
 }
+
+// FormatAmount renders an amount given in minor currency units, e.g. FormatAmount("NGN", 150000) is "NGN 1,500.00"
+func FormatAmount(currency string, amount int64) string {
+	const minorUnits = 100
+
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+
+	major := strconv.FormatInt(amount/minorUnits, 10)
+
+	var grouped strings.Builder
+
+	for i, digit := range major {
+		if i > 0 && (len(major)-i)%3 == 0 {
+			grouped.WriteByte(',')
+		}
+
+		grouped.WriteRune(digit)
+	}
+
+	return fmt.Sprintf("%s %s%s.%02d", currency, sign, grouped.String(), amount%minorUnits)
+}
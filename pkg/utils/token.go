@@ -1,7 +1,14 @@
 package utils
 
 import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
+	"math/big"
+	"strings"
 	"time"
 
 	"github.com/MogboPython/belvaphilips_backend/internal/config"
@@ -10,13 +17,26 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
-const noOfHours = 72
+// AccessTokenTTL is how long an access token is valid. Sessions last longer through refresh tokens
+const AccessTokenTTL = 15 * time.Minute
 
-func GenerateToken(id, role string) (string, error) {
+const (
+	randomTokenBytes   = 32
+	recoveryCodeLength = 10
+)
+
+// ChallengeTokenTTL is how long an admin has to enter a two-factor code after the password
+const ChallengeTokenTTL = 5 * time.Minute
+
+const challengePurpose = "admin_2fa"
+
+// GenerateToken signs an access token. The jti lets the token be revoked before it expires
+func GenerateToken(id, role, jti string) (string, error) {
 	claims := jwt.MapClaims{
 		"sessionId": id,
 		"role":      role,
-		"exp":       time.Now().Add(time.Hour * noOfHours).Unix(),
+		"jti":       jti,
+		"exp":       time.Now().Add(AccessTokenTTL).Unix(),
 	}
 
 	// Create token, sign and generate encoded token
@@ -31,6 +51,122 @@ func GenerateToken(id, role string) (string, error) {
 	return t, nil
 }
 
+// GenerateChallengeToken signs the token that carries an admin from the password step to the
+// two-factor step of signing in. It is signed with a key derived from JWT_SECRET so it can never
+// be used as an access token
+func GenerateChallengeToken(adminID string) (string, error) {
+	claims := jwt.MapClaims{
+		"sessionId": adminID,
+		"purpose":   challengePurpose,
+		"exp":       time.Now().Add(ChallengeTokenTTL).Unix(),
+	}
+
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(challengeKey())
+}
+
+// ParseChallengeToken returns the admin ID of a valid challenge token
+func ParseChallengeToken(challenge string) (string, error) {
+	token, err := jwt.Parse(challenge, func(*jwt.Token) (any, error) {
+		return challengeKey(), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return "", err
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["purpose"] != challengePurpose {
+		return "", jwt.ErrTokenInvalidClaims
+	}
+
+	adminID, _ := claims["sessionId"].(string)
+	if adminID == "" {
+		return "", jwt.ErrTokenInvalidClaims
+	}
+
+	return adminID, nil
+}
+
+func challengeKey() []byte {
+	mac := hmac.New(sha256.New, []byte(config.Config("JWT_SECRET")))
+	mac.Write([]byte(challengePurpose))
+
+	return mac.Sum(nil)
+}
+
+// GenerateRefreshToken returns a random opaque refresh token
+func GenerateRefreshToken() (string, error) {
+	return randomToken()
+}
+
+// GenerateInvitationToken returns a random opaque token for an emailed invitation
+func GenerateInvitationToken() (string, error) {
+	return randomToken()
+}
+
+func randomToken() (string, error) {
+	token := make([]byte, randomTokenBytes)
+
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
+
+// HashToken returns the SHA-256 digest of a random token for storage. Unlike passwords such
+// tokens carry enough entropy that a slow hash is not needed
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// HashPassword hashes a password with bcrypt
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(hash), nil
+}
+
+// GenerateTemporaryPassword returns a random password of length characters for an account
+// that must choose its own password at the next sign in
+func GenerateTemporaryPassword(length int) (string, error) {
+	// Characters that are easy to confuse when read from an email are left out
+	return randomString("ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnpqrstuvwxyz23456789", length)
+}
+
+// GenerateRecoveryCode returns a one-time two-factor recovery code such as "k7m2p-x9qhd"
+func GenerateRecoveryCode() (string, error) {
+	code, err := randomString("abcdefghjkmnpqrstuvwxyz23456789", recoveryCodeLength)
+	if err != nil {
+		return "", err
+	}
+
+	return code[:recoveryCodeLength/2] + "-" + code[recoveryCodeLength/2:], nil
+}
+
+// NormalizeRecoveryCode strips the formatting people add or drop when typing a recovery code
+func NormalizeRecoveryCode(code string) string {
+	return strings.NewReplacer("-", "", " ", "").Replace(strings.ToLower(strings.TrimSpace(code)))
+}
+
+func randomString(alphabet string, length int) (string, error) {
+	value := make([]byte, length)
+
+	for i := range value {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(alphabet))))
+		if err != nil {
+			return "", err
+		}
+
+		value[i] = alphabet[n.Int64()]
+	}
+
+	return string(value), nil
+}
+
 // CheckPasswordHash compare password with hash
 func CheckPasswordHash(password, hash string) bool {
 	err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
@@ -0,0 +1,84 @@
+package utils
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTOTPCode(t *testing.T) {
+	t.Parallel()
+
+	// Test vectors for HMAC-SHA1 from RFC 6238 appendix B
+	key := []byte("12345678901234567890")
+
+	cases := []struct {
+		code string
+		unix int64
+	}{
+		{unix: 59, code: "94287082"},
+		{unix: 1111111109, code: "07081804"},
+		{unix: 1111111111, code: "14050471"},
+		{unix: 1234567890, code: "89005924"},
+		{unix: 2000000000, code: "69279037"},
+		{unix: 20000000000, code: "65353130"},
+	}
+
+	for _, tc := range cases {
+		assert.Equal(t, tc.code, totpCode(key, uint64(tc.unix/totpPeriod), 8), "T=%d", tc.unix)
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	t.Parallel()
+
+	secret := totpEncoding.EncodeToString([]byte("12345678901234567890"))
+	now := time.Unix(1111111111, 0)
+
+	t.Run("Should accept the current code and return its time step", func(t *testing.T) {
+		t.Parallel()
+
+		step, ok := ValidateTOTP(secret, "050471", now)
+
+		assert.True(t, ok)
+		assert.Equal(t, int64(1111111111/totpPeriod), step)
+	})
+
+	t.Run("Should allow one period of clock drift", func(t *testing.T) {
+		t.Parallel()
+
+		_, ok := ValidateTOTP(secret, "050471", now.Add(totpPeriod*time.Second))
+		assert.True(t, ok)
+
+		_, ok = ValidateTOTP(secret, "050471", now.Add(3*totpPeriod*time.Second))
+		assert.False(t, ok)
+	})
+
+	t.Run("Should reject wrong codes and malformed secrets", func(t *testing.T) {
+		t.Parallel()
+
+		_, ok := ValidateTOTP(secret, "123456", now)
+		assert.False(t, ok)
+
+		_, ok = ValidateTOTP("not base32!", "050471", now)
+		assert.False(t, ok)
+	})
+}
+
+func TestGenerateTOTPSecret(t *testing.T) {
+	t.Parallel()
+
+	secret, err := GenerateTOTPSecret()
+	assert.NoError(t, err)
+
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	assert.NoError(t, err)
+	assert.Len(t, key, totpSecretSize)
+
+	uri := TOTPProvisioningURI("BelvaPhilips", "owner@example.com", secret)
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/BelvaPhilips:owner@example.com?"))
+	assert.Contains(t, uri, "secret="+secret)
+}